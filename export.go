@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/config"
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/export"
+)
+
+// runExport writes the snapshots to files instead of running the worker.
+func runExport(args []string) error {
+	fmt.Printf("%s v%s %s built %s\n\n", projectName, version, commit, buildDate)
+
+	var filter export.Filter
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", "parquet", "output format, one of parquet, csv, jsonl")
+	dir := flags.String("dir", "export", "directory to write the partitioned Parquet files to")
+	full := flags.Bool("full", false, "export all snapshots and replace existing partitions instead of continuing from the last export")
+	entity := flags.String("entity", "planet_snapshots", "entity to export as CSV or JSONL, one of "+strings.Join(export.Entities(), ", "))
+	out := flags.String("out", "", "file to write CSV or JSONL to (default <entity>.<format>)")
+	flags.Func("from", "only export snapshots taken at or after this time (RFC3339 or YYYY-MM-DD)", timeFlag(&filter.From))
+	flags.Func("to", "only export snapshots taken before this time (RFC3339 or YYYY-MM-DD)", timeFlag(&filter.To))
+	flags.Func("planet", "only export rows of the planet with this ID", func(s string) error {
+		id, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return err
+		}
+		filter.PlanetID = new(int32)
+		*filter.PlanetID = int32(id)
+		return nil
+	})
+	flags.StringVar(&filter.Sector, "sector", "", "only export rows of planets in this sector")
+	flags.StringVar(&filter.Faction, "faction", "", "only export rows of planets owned by this faction")
+	_ = flags.Parse(args)
+
+	cfg := config.MustGet()
+	setupLogging(cfg)
+	logger := loggerFor("export")
+
+	dbClient := mustConnectDB(cfg, logger)
+	defer disconnectDB(dbClient, logger)
+
+	var err error
+	switch *format {
+	case "parquet":
+		err = export.New(dbClient, *dir, logger).Run(context.Background(), *full)
+	case string(export.FormatCSV), string(export.FormatJSONL):
+		if *out == "" {
+			*out = *entity + "." + *format
+		}
+		err = exportRows(dbClient, *entity, export.Format(*format), filter, *out, logger)
+	default:
+		err = fmt.Errorf("unsupported format %q", *format)
+	}
+	return err
+}
+
+// exportRows streams the rows of `entity` to the file at `path`, which is removed if the export fails.
+func exportRows(dbClient *db.Client, entity string, format export.Format, filter export.Filter, path string, logger *slog.Logger) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	n, err := export.WriteRows(context.Background(), dbClient, entity, format, filter, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	logger.Info("exported rows", "rows", n, "entity", entity, "path", path)
+	return nil
+}
+
+// timeFlag parses RFC3339 times or UTC dates into `t`.
+func timeFlag(t *time.Time) func(string) error {
+	return func(s string) (err error) {
+		if *t, err = time.Parse(time.RFC3339, s); err == nil {
+			return nil
+		}
+		*t, err = time.Parse(time.DateOnly, s)
+		return err
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/config"
+	"github.com/stnokott/helldivers-client/internal/gaps"
+)
+
+// runGaps reports gaps in the snapshot history according to SNAPSHOT_CRON instead of running the worker.
+func runGaps(args []string) error {
+	fmt.Printf("%s v%s %s built %s\n\n", projectName, version, commit, buildDate)
+
+	flags := flag.NewFlagSet("gaps", flag.ExitOnError)
+	since := flags.Duration("since", 0, "only analyze snapshots of this recent period (e.g. 168h), analyze all if 0")
+	fill := flags.Bool("fill", false, "fill gaps from the raw API data archived in ARCHIVE_DIR")
+	mark := flags.Bool("mark", false, "record remaining gaps in the snapshot_gaps table")
+	_ = flags.Parse(args)
+
+	cfg := config.MustGet()
+	setupLogging(cfg)
+	logger := loggerFor("gaps")
+
+	dbClient := mustConnectDB(cfg, logger)
+	defer disconnectDB(dbClient, logger)
+
+	analyzer, err := gaps.New(dbClient, cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	opts := gaps.Options{Fill: *fill, Mark: *mark}
+	if *since > 0 {
+		opts.Since = time.Now().Add(-*since).UTC()
+	}
+	return analyzer.Run(context.Background(), opts)
+}
@@ -57,6 +57,30 @@ func (resp *GetApiV1PlanetsAllResponse) Data() (*[]Planet, error) {
 	return nil, respErr(resp.HTTPResponse)
 }
 
+// Data implements the interface required for client processing
+func (resp *GetApiV1AssignmentsResponse) Data() (*Assignment2, error) {
+	if resp.StatusCode() == 200 {
+		return resp.JSON200, nil
+	}
+	return nil, respErr(resp.HTTPResponse)
+}
+
+// Data implements the interface required for client processing
+func (resp *GetApiV1CampaignsResponse) Data() (*Campaign2, error) {
+	if resp.StatusCode() == 200 {
+		return resp.JSON200, nil
+	}
+	return nil, respErr(resp.HTTPResponse)
+}
+
+// Data implements the interface required for client processing
+func (resp *GetApiV1PlanetsResponse) Data() (*Planet, error) {
+	if resp.StatusCode() == 200 {
+		return resp.JSON200, nil
+	}
+	return nil, respErr(resp.HTTPResponse)
+}
+
 func respErr(resp *http.Response) error {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -0,0 +1,143 @@
+// Package archive stores raw API responses on disk, so that they can be processed again later.
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/transform"
+)
+
+const (
+	fileExt = ".json"
+	// timeLayout sorts lexicographically and is safe for use in file names
+	timeLayout = "20060102T150405.000000Z"
+)
+
+// Archive is a directory containing one file with raw API data per sync.
+type Archive struct {
+	dir string
+	// retention is how long files are kept, relative to the latest war time, or 0 to keep them forever
+	retention time.Duration
+}
+
+// New creates a new Archive in `dir` which keeps files for `retention`.
+//
+// A retention of 0 keeps all files, so that the archive grows with every sync.
+func New(dir string, retention time.Duration) *Archive {
+	return &Archive{dir: dir, retention: retention}
+}
+
+// Write stores `data` in the archive, keyed by the time of the war data.
+//
+// Existing data for the same time is overwritten.
+// Files older than the retention before the time of `data` are deleted afterwards.
+func (a *Archive) Write(data transform.APIData) error {
+	if data.War == nil || data.War.Now == nil {
+		return errors.New("war time unavailable, cannot archive data")
+	}
+	if err := os.MkdirAll(a.dir, 0o755); err != nil {
+		return fmt.Errorf("creating archive directory: %w", err)
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding API data: %w", err)
+	}
+
+	// write to a temporary file first so that readers never see partial data
+	name := filepath.Join(a.dir, data.War.Now.UTC().Format(timeLayout)+fileExt)
+	tmp := name + ".tmp"
+	if err = os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("writing archive file: %w", err)
+	}
+	if err = os.Rename(tmp, name); err != nil {
+		return fmt.Errorf("writing archive file: %w", err)
+	}
+	if a.retention > 0 {
+		return a.prune(data.War.Now.Add(-a.retention))
+	}
+	return nil
+}
+
+// prune deletes all archived data with a war time before `cutoff`.
+func (a *Archive) prune(cutoff time.Time) error {
+	files, err := a.files()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if !f.time.Before(cutoff) {
+			continue
+		}
+		if err = os.Remove(filepath.Join(a.dir, f.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("deleting archive file: %w", err)
+		}
+	}
+	return nil
+}
+
+// file is a single archived sync.
+type file struct {
+	name string
+	time time.Time
+}
+
+// files lists all archive files in the archive directory, ignoring unrelated files.
+func (a *Archive) files() ([]file, error) {
+	entries, err := os.ReadDir(a.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading archive directory: %w", err)
+	}
+
+	var files []file
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, fileExt) {
+			continue
+		}
+		t, err := time.Parse(timeLayout, strings.TrimSuffix(name, fileExt))
+		if err != nil {
+			// not an archive file
+			continue
+		}
+		files = append(files, file{name: name, time: t})
+	}
+	return files, nil
+}
+
+// Between returns all archived data with a war time strictly between `from` and `to`, in ascending order.
+func (a *Archive) Between(from, to time.Time) ([]transform.APIData, error) {
+	files, err := a.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range files {
+		if f.time.After(from) && f.time.Before(to) {
+			names = append(names, f.name)
+		}
+	}
+	sort.Strings(names)
+
+	data := make([]transform.APIData, len(names))
+	for i, name := range names {
+		b, err := os.ReadFile(filepath.Join(a.dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading archive file: %w", err)
+		}
+		if err = json.Unmarshal(b, &data[i]); err != nil {
+			return nil, fmt.Errorf("decoding archive file %s: %w", name, err)
+		}
+	}
+	return data, nil
+}
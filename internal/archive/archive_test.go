@@ -0,0 +1,102 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/api"
+	"github.com/stnokott/helldivers-client/internal/transform"
+)
+
+func archivedData(t time.Time) transform.APIData {
+	return transform.APIData{
+		War:      &api.War{Now: &t},
+		Upstream: "http://localhost:4000",
+	}
+}
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	a := New(filepath.Join(dir, "archive"), 0)
+
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		if err := a.Write(archivedData(base.Add(time.Duration(i) * time.Minute))); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	// unrelated files are ignored
+	if err := os.WriteFile(filepath.Join(dir, "archive", "README.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := a.Between(base, base.Add(4*time.Minute))
+	if err != nil {
+		t.Fatalf("Between() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Between() returned %d entries, want 3", len(got))
+	}
+	for i, data := range got {
+		want := base.Add(time.Duration(i+1) * time.Minute)
+		if !data.War.Now.Equal(want) {
+			t.Errorf("entry %d has time %v, want %v", i, data.War.Now, want)
+		}
+		if data.Upstream != "http://localhost:4000" {
+			t.Errorf("entry %d has upstream %q, want %q", i, data.Upstream, "http://localhost:4000")
+		}
+	}
+}
+
+func TestArchiveRetention(t *testing.T) {
+	dir := t.TempDir()
+	a := New(dir, 2*time.Minute)
+
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		if err := a.Write(archivedData(base.Add(time.Duration(i) * time.Minute))); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	// unrelated files are kept
+	if err := os.WriteFile(filepath.Join(dir, "README.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Write(archivedData(base.Add(5 * time.Minute))); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got, err := a.Between(time.Time{}, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Between() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Between() returned %d entries, want 3", len(got))
+	}
+	if want := base.Add(3 * time.Minute); !got[0].War.Now.Equal(want) {
+		t.Errorf("oldest entry has time %v, want %v", got[0].War.Now, want)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "README.json")); err != nil {
+		t.Errorf("unrelated file was deleted: %v", err)
+	}
+}
+
+func TestArchiveMissingDir(t *testing.T) {
+	a := New(filepath.Join(t.TempDir(), "missing"), 0)
+	got, err := a.Between(time.Time{}, time.Now())
+	if err != nil {
+		t.Fatalf("Between() error = %v, want nil", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Between() returned %d entries, want 0", len(got))
+	}
+}
+
+func TestArchiveWriteWithoutTime(t *testing.T) {
+	a := New(t.TempDir(), 0)
+	if err := a.Write(transform.APIData{}); err == nil {
+		t.Error("Write() error = nil, want error")
+	}
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/stnokott/helldivers-client/internal/api"
+	"github.com/stnokott/helldivers-client/internal/config"
+)
+
+// tokenSource provides the bearer token attached to API requests.
+type tokenSource interface {
+	Token() (string, error)
+}
+
+// newTokenSource creates a token source from the config.
+//
+// A static token takes precedence over a signing key. If neither is configured, nil is returned.
+func newTokenSource(cfg *config.Config) (tokenSource, error) {
+	if cfg.APIAuthToken != "" {
+		return staticToken(cfg.APIAuthToken), nil
+	}
+	if cfg.APIAuthSigningKey == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(cfg.APIAuthSigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("decoding signing key: %w", err)
+	}
+	if cfg.APIAuthTokenLifetime <= 0 {
+		return nil, fmt.Errorf("token lifetime must be positive, got %s", cfg.APIAuthTokenLifetime)
+	}
+	return newJWTSource(key, cfg.APIAuthTokenLifetime, cfg.APIAuthIssuer, cfg.APIAuthAudience), nil
+}
+
+// staticToken is a pre-shared bearer token.
+type staticToken string
+
+func (t staticToken) Token() (string, error) {
+	return string(t), nil
+}
+
+// jwtSource signs tokens locally using a key shared with the API.
+//
+// Tokens are cached and re-signed once most of their lifetime has passed,
+// so that no request is sent with a token which is about to expire.
+type jwtSource struct {
+	key      []byte
+	lifetime time.Duration
+	issuer   string
+	audience string
+	now      func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func newJWTSource(key []byte, lifetime time.Duration, issuer string, audience string) *jwtSource {
+	return &jwtSource{
+		key:      key,
+		lifetime: lifetime,
+		issuer:   issuer,
+		audience: audience,
+		now:      time.Now,
+	}
+}
+
+// refreshMargin returns how long before expiry a token is replaced.
+func (s *jwtSource) refreshMargin() time.Duration {
+	return min(s.lifetime/5, time.Minute)
+}
+
+func (s *jwtSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.token != "" && now.Add(s.refreshMargin()).Before(s.expiry) {
+		return s.token, nil
+	}
+
+	expiry := now.Add(s.lifetime)
+	claims := jwt.RegisteredClaims{
+		Issuer:    s.issuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiry),
+	}
+	if s.audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.audience}
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("signing token: %w", err)
+	}
+	s.token, s.expiry = token, expiry
+	return token, nil
+}
+
+// authRequestEditor attaches a bearer token from `src` to every request.
+func authRequestEditor(src tokenSource) api.RequestEditorFn {
+	return func(_ context.Context, req *http.Request) error {
+		token, err := src.Token()
+		if err != nil {
+			return fmt.Errorf("obtaining API token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/stnokott/helldivers-client/internal/config"
+)
+
+var testSigningKey = []byte("0123456789abcdef0123456789abcdef")
+
+// newAuthServer returns a server which only responds with HTTP 200 if the request contains a token accepted by `validate`.
+func newAuthServer(t *testing.T, validate func(token string) error) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := validate(token); err != nil {
+			t.Logf("rejected token: %v", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func doAuthorized(t *testing.T, src tokenSource, url string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = authRequestEditor(src)(context.Background(), req); err != nil {
+		t.Fatalf("request editor error = %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestAuthStaticToken(t *testing.T) {
+	server := newAuthServer(t, func(token string) error {
+		if token != "secret" {
+			return jwt.ErrTokenMalformed
+		}
+		return nil
+	})
+
+	if got := doAuthorized(t, staticToken("secret"), server.URL); got != http.StatusOK {
+		t.Errorf("valid token: status = %d, want %d", got, http.StatusOK)
+	}
+	if got := doAuthorized(t, staticToken("wrong"), server.URL); got != http.StatusUnauthorized {
+		t.Errorf("invalid token: status = %d, want %d", got, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthJWT(t *testing.T) {
+	server := newAuthServer(t, func(token string) error {
+		_, err := jwt.Parse(
+			token,
+			func(*jwt.Token) (any, error) { return testSigningKey, nil },
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+			jwt.WithIssuer("client"),
+			jwt.WithAudience("api"),
+			jwt.WithExpirationRequired(),
+		)
+		return err
+	})
+
+	src := newJWTSource(testSigningKey, time.Hour, "client", "api")
+	if got := doAuthorized(t, src, server.URL); got != http.StatusOK {
+		t.Errorf("valid token: status = %d, want %d", got, http.StatusOK)
+	}
+
+	wrongKey := newJWTSource([]byte("wrong"), time.Hour, "client", "api")
+	if got := doAuthorized(t, wrongKey, server.URL); got != http.StatusUnauthorized {
+		t.Errorf("wrong key: status = %d, want %d", got, http.StatusUnauthorized)
+	}
+
+	wrongAudience := newJWTSource(testSigningKey, time.Hour, "client", "other")
+	if got := doAuthorized(t, wrongAudience, server.URL); got != http.StatusUnauthorized {
+		t.Errorf("wrong audience: status = %d, want %d", got, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthJWTRefresh(t *testing.T) {
+	const lifetime = 10 * time.Minute
+	start := time.Now()
+	now := start
+
+	src := newJWTSource(testSigningKey, lifetime, "", "")
+	src.now = func() time.Time { return now }
+
+	first, err := src.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+
+	now = start.Add(lifetime / 2)
+	if second, _ := src.Token(); second != first {
+		t.Error("token was renewed although most of its lifetime is left")
+	}
+
+	// the token would still be valid here, but is about to expire
+	now = start.Add(lifetime - src.refreshMargin())
+	third, err := src.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if third == first {
+		t.Fatal("token was not renewed before expiry")
+	}
+
+	claims := new(jwt.RegisteredClaims)
+	if _, err = jwt.ParseWithClaims(
+		third,
+		claims,
+		func(*jwt.Token) (any, error) { return testSigningKey, nil },
+		jwt.WithTimeFunc(func() time.Time { return now }),
+	); err != nil {
+		t.Fatalf("failed to parse renewed token: %v", err)
+	}
+	if want := now.Add(lifetime).Truncate(time.Second); !claims.ExpiresAt.Equal(want) {
+		t.Errorf("renewed token expires at %v, want %v", claims.ExpiresAt, want)
+	}
+}
+
+func TestNewTokenSource(t *testing.T) {
+	validKey := base64.StdEncoding.EncodeToString(testSigningKey)
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		want    string // type of the returned source
+		wantErr bool
+	}{
+		{
+			name: "none",
+			cfg:  config.Config{APIAuthTokenLifetime: time.Hour},
+			want: "<nil>",
+		},
+		{
+			name: "static token precedence",
+			cfg:  config.Config{APIAuthToken: "secret", APIAuthSigningKey: validKey, APIAuthTokenLifetime: time.Hour},
+			want: "client.staticToken",
+		},
+		{
+			name: "signing key",
+			cfg:  config.Config{APIAuthSigningKey: validKey, APIAuthTokenLifetime: time.Hour},
+			want: "*client.jwtSource",
+		},
+		{
+			name:    "invalid signing key",
+			cfg:     config.Config{APIAuthSigningKey: "not base64!", APIAuthTokenLifetime: time.Hour},
+			wantErr: true,
+		},
+		{
+			name:    "zero lifetime",
+			cfg:     config.Config{APIAuthSigningKey: validKey},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTokenSource(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newTokenSource() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if gotType := fmt.Sprintf("%T", got); gotType != tt.want {
+				t.Errorf("newTokenSource() = %s, want %s", gotType, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when requests are rejected because the API failed too often in a row.
+var ErrCircuitOpen = errors.New("circuit breaker open, API is considered unavailable")
+
+// circuitBreaker stops requests to an API which keeps failing.
+//
+// After `threshold` consecutive failures, all requests are rejected for `cooldown`.
+// Afterwards, a single trial request is let through: if it succeeds, the breaker closes again,
+// otherwise it stays open for another cooldown period.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	log       *slog.Logger
+
+	mu            sync.Mutex
+	failures      int
+	openUntil     time.Time
+	trialInFlight bool
+}
+
+// newCircuitBreaker creates a new breaker. A threshold <= 0 disables it.
+func newCircuitBreaker(threshold int, cooldown time.Duration, logger *slog.Logger) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		log:       logger,
+	}
+}
+
+func (b *circuitBreaker) enabled() bool {
+	return b != nil && b.threshold > 0
+}
+
+// allow returns ErrCircuitOpen if the request should not be sent.
+//
+// `trial` is true if the request is the single trial request of a half-open breaker.
+// Its outcome must either be recorded or the trial must be released.
+func (b *circuitBreaker) allow() (trial bool, err error) {
+	if !b.enabled() {
+		return false, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return false, nil
+	}
+	if time.Now().Before(b.openUntil) || b.trialInFlight {
+		return false, ErrCircuitOpen
+	}
+	// half-open: let a single request through to check whether the API recovered
+	b.trialInFlight = true
+	return true, nil
+}
+
+// release ends a trial request without recording its outcome, e.g. because it was cancelled.
+//
+// The next request becomes the trial instead.
+func (b *circuitBreaker) release() {
+	if !b.enabled() {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trialInFlight = false
+}
+
+// record updates the breaker with the outcome of a request.
+func (b *circuitBreaker) record(success bool) {
+	if !b.enabled() {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trialInFlight = false
+	if success {
+		if b.failures >= b.threshold {
+			b.log.Info("API recovered, closing circuit breaker")
+		}
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+		b.log.Warn("API keeps failing, opening circuit breaker", "failures", b.failures, "cooldown", b.cooldown)
+	}
+}
+
+// open reports whether requests are currently being rejected, which includes requests while a trial is in flight.
+func (b *circuitBreaker) open() bool {
+	if !b.enabled() {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.threshold && (time.Now().Before(b.openUntil) || b.trialInFlight)
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/stnokott/helldivers-client/internal/api"
+)
+
+// ResponseInfo contains metadata about an API response.
+//
+// Attach it to a request context using WithResponseInfo to have it filled by the client.
+type ResponseInfo struct {
+	// Unchanged is true if the response body is identical to the previous response for the same endpoint.
+	Unchanged bool
+	// Upstream is the root URL of the API instance which served the response.
+	Upstream string
+}
+
+type responseInfoKey struct{}
+
+// WithResponseInfo returns a context which causes the client to fill `info` when a response was received.
+func WithResponseInfo(ctx context.Context, info *ResponseInfo) context.Context {
+	return context.WithValue(ctx, responseInfoKey{}, info)
+}
+
+func responseInfoFrom(ctx context.Context) *ResponseInfo {
+	if info, ok := ctx.Value(responseInfoKey{}).(*ResponseInfo); ok {
+		return info
+	}
+	return nil
+}
+
+// cacheEntry holds the last response received for an endpoint.
+type cacheEntry struct {
+	etag         string
+	lastModified string
+	contentType  string
+	body         []byte
+	hash         [sha256.Size]byte
+}
+
+// cachingHTTPClient wraps another client and revalidates GET requests using `ETag`/`Last-Modified`.
+//
+// The last response body of each endpoint is kept in memory. If the server responds with HTTP 304,
+// the cached body is returned instead. Callers can find out whether a response changed since the previous
+// request using ResponseInfo.
+type cachingHTTPClient struct {
+	next api.HttpRequestDoer
+	log  *slog.Logger
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+func newCachingHTTPClient(next api.HttpRequestDoer, logger *slog.Logger) *cachingHTTPClient {
+	return &cachingHTTPClient{
+		next:    next,
+		log:     logger,
+		entries: map[string]*cacheEntry{},
+	}
+}
+
+func (c *cachingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return c.next.Do(req)
+	}
+	key := req.URL.String()
+	cached := c.get(key)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := c.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		_ = resp.Body.Close()
+		c.log.DebugContext(req.Context(), "not modified, using cached response", "path", req.URL.Path)
+		setUnchanged(req.Context())
+		return cachedResponse(resp, cached), nil
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read response body: %w", err)
+		}
+		entry := &cacheEntry{
+			etag:         resp.Header.Get("ETag"),
+			lastModified: resp.Header.Get("Last-Modified"),
+			contentType:  resp.Header.Get("Content-Type"),
+			body:         body,
+			hash:         sha256.Sum256(body),
+		}
+		if cached != nil && cached.hash == entry.hash {
+			setUnchanged(req.Context())
+		}
+		c.put(key, entry)
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	default:
+		return resp, nil
+	}
+}
+
+// Invalidate drops all cached responses, so that the next responses are reported as changed.
+func (c *cachingHTTPClient) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]*cacheEntry{}
+}
+
+func (c *cachingHTTPClient) get(key string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[key]
+}
+
+func (c *cachingHTTPClient) put(key string, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+func setUnchanged(ctx context.Context) {
+	if info := responseInfoFrom(ctx); info != nil {
+		info.Unchanged = true
+	}
+}
+
+// cachedResponse turns a 304 response into a 200 response containing the cached body.
+func cachedResponse(resp *http.Response, cached *cacheEntry) *http.Response {
+	out := *resp
+	out.StatusCode = http.StatusOK
+	out.Status = "200 OK"
+	out.Header = resp.Header.Clone()
+	out.Header.Set("Content-Type", cached.contentType)
+	out.Header.Set("Content-Length", strconv.Itoa(len(cached.body)))
+	out.ContentLength = int64(len(cached.body))
+	out.Body = io.NopCloser(bytes.NewReader(cached.body))
+	return &out
+}
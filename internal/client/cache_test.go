@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func doCached(t *testing.T, c *cachingHTTPClient, url string) (body string, unchanged bool) {
+	t.Helper()
+	info := new(ResponseInfo)
+	req, err := http.NewRequestWithContext(WithResponseInfo(context.Background(), info), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Do() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b), info.Unchanged
+}
+
+func TestCachingHTTPClientETag(t *testing.T) {
+	const etag = `"v1"`
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	c := newCachingHTTPClient(http.DefaultClient, slog.Default())
+
+	body, unchanged := doCached(t, c, server.URL)
+	if body != `{"id":1}` || unchanged {
+		t.Errorf("first response = (%s, unchanged=%v), want (%s, unchanged=false)", body, unchanged, `{"id":1}`)
+	}
+	body, unchanged = doCached(t, c, server.URL)
+	if body != `{"id":1}` || !unchanged {
+		t.Errorf("second response = (%s, unchanged=%v), want (%s, unchanged=true)", body, unchanged, `{"id":1}`)
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("got %d requests with %d not modified, want 2 with 1 not modified", requests, notModified)
+	}
+
+	c.Invalidate()
+	if _, unchanged = doCached(t, c, server.URL); unchanged {
+		t.Error("response after Invalidate() reported as unchanged")
+	}
+	if notModified != 1 {
+		t.Error("request after Invalidate() was sent with conditional headers")
+	}
+}
+
+func TestCachingHTTPClientBodyHash(t *testing.T) {
+	body := `{"id":1}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// server without support for conditional requests
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	c := newCachingHTTPClient(http.DefaultClient, slog.Default())
+
+	tests := []struct {
+		name          string
+		body          string
+		wantUnchanged bool
+	}{
+		{name: "first", body: `{"id":1}`, wantUnchanged: false},
+		{name: "identical", body: `{"id":1}`, wantUnchanged: true},
+		{name: "changed", body: `{"id":2}`, wantUnchanged: false},
+		{name: "identical after change", body: `{"id":2}`, wantUnchanged: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body = tt.body
+			got, unchanged := doCached(t, c, server.URL)
+			if got != tt.body {
+				t.Errorf("body = %s, want %s", got, tt.body)
+			}
+			if unchanged != tt.wantUnchanged {
+				t.Errorf("unchanged = %v, want %v", unchanged, tt.wantUnchanged)
+			}
+		})
+	}
+}
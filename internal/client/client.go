@@ -3,38 +3,107 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sync"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/stnokott/helldivers-client/internal/api"
 	"github.com/stnokott/helldivers-client/internal/config"
+	"github.com/stnokott/helldivers-client/internal/logging"
+	"github.com/stnokott/helldivers-client/internal/metrics"
 )
 
-// Client wraps the generated OpenAPI client
+// Client wraps the generated OpenAPI client.
+//
+// It can query multiple upstream API instances, failing over to the next one
+// if an instance is unavailable or serves outdated data.
+//
+// Queries of war-scoped data take the ID of the war returned by WarID.
+// Since the API only serves the current war of each upstream, they skip upstreams serving another war.
 type Client struct {
-	api *api.ClientWithResponses
-	log *log.Logger
+	upstreams     []*upstream
+	preferPrimary bool
+	staleAfter    time.Duration
+	// batchConcurrency limits the concurrent requests of batch queries like PlanetsByIndex
+	batchConcurrency int
+	rateLimit        int
+	rateLimitWindow  time.Duration
+	log              *slog.Logger
+
+	mu sync.Mutex
+	// active is the index of the upstream which served the latest successful request
+	active int
+	// served maps each endpoint to the upstream which served it last
+	served map[string]*upstream
 }
 
-const _maxHTTPRetries = 3
+// New creates a new client instance.
+//
+// `identity` provides defaults for the identification headers which are not set in `cfg`.
+// Requests are recorded in `m`, which may be nil if metrics are disabled.
+func New(cfg *config.Config, identity Identity, m *metrics.Metrics, logger *slog.Logger) (*Client, error) {
+	if len(cfg.APIRootURLs) == 0 {
+		return nil, errors.New("no API URL configured")
+	}
+
+	options := upstreamOptions{
+		retry: retryPolicy{
+			maxRetry:  cfg.APIMaxRetries,
+			baseDelay: cfg.APIRetryBaseDelay,
+			maxDelay:  cfg.APIRetryMaxDelay,
+		},
+		rateLimit:        cfg.APIRateLimit,
+		rateLimitWindow:  cfg.APIRateLimitWindow,
+		breakerThreshold: cfg.APICircuitBreakerThreshold,
+		breakerCooldown:  cfg.APICircuitBreakerCooldown,
+		apiOptions: []api.ClientOption{
+			api.WithRequestEditorFn(identityRequestEditor(identityFrom(cfg, identity))),
+		},
+		metrics: m,
+	}
 
-// New creates a new client instance
-func New(cfg *config.Config, logger *log.Logger) (*Client, error) {
-	options := api.WithHTTPClient(
-		newRateLimitHTTPClient(_maxHTTPRetries, logger),
-	)
-	c, err := api.NewClientWithResponses(cfg.APIRootURL, options)
+	tokens, err := newTokenSource(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("client initialization: %w", err)
+		return nil, fmt.Errorf("API authentication: %w", err)
+	}
+	if tokens != nil {
+		options.apiOptions = append(options.apiOptions, api.WithRequestEditorFn(authRequestEditor(tokens)))
+	}
+
+	upstreams := make([]*upstream, len(cfg.APIRootURLs))
+	for i, url := range cfg.APIRootURLs {
+		// distinguish log messages of different upstreams
+		if upstreams[i], err = newUpstream(url, options, logger.With("upstream", url)); err != nil {
+			return nil, err
+		}
 	}
 
 	return &Client{
-		api: c,
-		log: logger,
+		upstreams:        upstreams,
+		preferPrimary:    cfg.APIPreferPrimary,
+		staleAfter:       cfg.APIUpstreamStaleAfter,
+		batchConcurrency: max(cfg.APIFetchConcurrency, 1),
+		rateLimit:        cfg.APIRateLimit,
+		rateLimitWindow:  cfg.APIRateLimitWindow,
+		log:              logger,
+		served:           map[string]*upstream{},
 	}, nil
 }
 
+// InvalidateCache drops all cached responses.
+//
+// This should be called when the data of previous responses could not be processed, so that
+// subsequent responses are not reported as unchanged.
+func (c *Client) InvalidateCache() {
+	for _, u := range c.upstreams {
+		u.cache.Invalidate()
+	}
+}
+
 // Connect implements main.ConnectWaiter.
 func (c *Client) Connect(ctx context.Context) error {
 	ticker := time.NewTicker(2 * time.Second)
@@ -48,40 +117,329 @@ func (c *Client) Connect(ctx context.Context) error {
 			if _, err := c.WarID(ctx); err == nil {
 				return nil
 			} else {
-				c.log.Printf("API query: %v", err)
+				c.log.WarnContext(ctx, "API query failed", logging.Err(err))
 			}
 		}
 	}
 }
 
-// WarID returns the ID of the current war
+// WarID returns the ID of the current war.
+//
+// The wars of all other upstreams are checked again before they serve war-scoped data,
+// since they might have moved on to another war season since.
 func (c *Client) WarID(ctx context.Context) (*api.WarId, error) {
-	return processResp(ctx, c.api.GetRawApiWarSeasonCurrentWarIDWithResponse)
+	info := new(ResponseInfo)
+	id, err := query(WithResponseInfo(ctx, info), c, "war ID", func(a *api.ClientWithResponses) requestFunc[*api.GetRawApiWarSeasonCurrentWarIDResponse] {
+		return a.GetRawApiWarSeasonCurrentWarIDWithResponse
+	}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range c.upstreams {
+		if u.url == info.Upstream && id.Id != nil {
+			u.setWar(*id.Id)
+		} else {
+			u.setWar(0)
+		}
+	}
+	if callerInfo := responseInfoFrom(ctx); callerInfo != nil {
+		*callerInfo = *info
+	}
+	return id, nil
 }
 
-// War returns the current war
-func (c *Client) War(ctx context.Context) (*api.War, error) {
-	return processResp(ctx, c.api.GetApiV1WarWithResponse)
+// War returns the war with ID `warID`.
+//
+// Upstreams whose war data has not advanced for longer than the configured threshold
+// are considered stale and the next upstream is tried.
+func (c *Client) War(ctx context.Context, warID int32) (*api.War, error) {
+	return query(ctx, c, "war", func(a *api.ClientWithResponses) requestFunc[*api.GetApiV1WarResponse] {
+		return a.GetApiV1WarWithResponse
+	}, c.inWar(warID), c.warFreshness)
 }
 
-// Assignments returns all currently active assignments
-func (c *Client) Assignments(ctx context.Context) (*[]api.Assignment2, error) {
-	return processResp(ctx, c.api.GetApiV1AssignmentsAllWithResponse)
+// Assignments returns all currently active assignments of the war with ID `warID`
+func (c *Client) Assignments(ctx context.Context, warID int32) (*[]api.Assignment2, error) {
+	return query(ctx, c, "assignments", func(a *api.ClientWithResponses) requestFunc[*api.GetApiV1AssignmentsAllResponse] {
+		return a.GetApiV1AssignmentsAllWithResponse
+	}, c.inWar(warID), nil)
 }
 
-// Campaigns returns all currently active campaigns
-func (c *Client) Campaigns(ctx context.Context) (*[]api.Campaign2, error) {
-	return processResp(ctx, c.api.GetApiV1CampaignsAllWithResponse)
+// Campaigns returns all currently active campaigns of the war with ID `warID`
+func (c *Client) Campaigns(ctx context.Context, warID int32) (*[]api.Campaign2, error) {
+	return query(ctx, c, "campaigns", func(a *api.ClientWithResponses) requestFunc[*api.GetApiV1CampaignsAllResponse] {
+		return a.GetApiV1CampaignsAllWithResponse
+	}, c.inWar(warID), nil)
 }
 
 // Dispatches returns all currently active dispatches
 func (c *Client) Dispatches(ctx context.Context) (*[]api.Dispatch, error) {
-	return processResp(ctx, c.api.GetApiV1DispatchesAllWithResponse)
+	return query(ctx, c, "dispatches", func(a *api.ClientWithResponses) requestFunc[*api.GetApiV1DispatchesAllResponse] {
+		return a.GetApiV1DispatchesAllWithResponse
+	}, nil, nil)
+}
+
+// Planets returns all planets of the war with ID `warID`
+func (c *Client) Planets(ctx context.Context, warID int32) (*[]api.Planet, error) {
+	return query(ctx, c, "planets", func(a *api.ClientWithResponses) requestFunc[*api.GetApiV1PlanetsAllResponse] {
+		return a.GetApiV1PlanetsAllWithResponse
+	}, c.inWar(warID), nil)
+}
+
+// Assignment returns a single assignment of the war with ID `warID` by its index
+func (c *Client) Assignment(ctx context.Context, warID int32, index int64) (*api.Assignment2, error) {
+	return query(ctx, c, fmt.Sprintf("assignment %d", index), func(a *api.ClientWithResponses) requestFunc[*api.GetApiV1AssignmentsResponse] {
+		return func(ctx context.Context, reqEditors ...api.RequestEditorFn) (*api.GetApiV1AssignmentsResponse, error) {
+			return a.GetApiV1AssignmentsWithResponse(ctx, index, reqEditors...)
+		}
+	}, c.inWar(warID), nil)
+}
+
+// Campaign returns a single campaign of the war with ID `warID` by its index
+func (c *Client) Campaign(ctx context.Context, warID int32, index int32) (*api.Campaign2, error) {
+	return query(ctx, c, fmt.Sprintf("campaign %d", index), func(a *api.ClientWithResponses) requestFunc[*api.GetApiV1CampaignsResponse] {
+		return func(ctx context.Context, reqEditors ...api.RequestEditorFn) (*api.GetApiV1CampaignsResponse, error) {
+			return a.GetApiV1CampaignsWithResponse(ctx, index, reqEditors...)
+		}
+	}, c.inWar(warID), nil)
+}
+
+// Planet returns a single planet of the war with ID `warID` by its index
+func (c *Client) Planet(ctx context.Context, warID int32, index int32) (*api.Planet, error) {
+	return query(ctx, c, fmt.Sprintf("planet %d", index), func(a *api.ClientWithResponses) requestFunc[*api.GetApiV1PlanetsResponse] {
+		return func(ctx context.Context, reqEditors ...api.RequestEditorFn) (*api.GetApiV1PlanetsResponse, error) {
+			return a.GetApiV1PlanetsWithResponse(ctx, index, reqEditors...)
+		}
+	}, c.inWar(warID), nil)
+}
+
+// PlanetsByIndex queries the planets of the war with ID `warID` with the given indices concurrently, with at most API_FETCH_CONCURRENCY requests at once.
+//
+// A planet which could not be queried does not cancel the others. In that case, the planets which could be queried
+// are returned together with an error. The response is only reported as unchanged if all planets are unchanged.
+func (c *Client) PlanetsByIndex(ctx context.Context, warID int32, indices []int32) (*[]api.Planet, error) {
+	planets := make([]*api.Planet, len(indices))
+	infos := make([]ResponseInfo, len(indices))
+	errs := make([]error, len(indices))
+
+	// we don't use errgroup.WithContext since one failing planet should not cancel the others
+	g := new(errgroup.Group)
+	g.SetLimit(c.batchConcurrency)
+	for i, index := range indices {
+		g.Go(func() error {
+			planet, err := c.Planet(WithResponseInfo(ctx, &infos[i]), warID, index)
+			if err != nil {
+				errs[i] = fmt.Errorf("planet %d: %w", index, err)
+				return nil
+			}
+			planets[i] = planet
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	queried := make([]api.Planet, 0, len(indices))
+	for _, planet := range planets {
+		if planet != nil {
+			queried = append(queried, *planet)
+		}
+	}
+	err := errors.Join(errs...)
+
+	if callerInfo := responseInfoFrom(ctx); callerInfo != nil {
+		callerInfo.Unchanged = err == nil
+		for _, info := range infos {
+			callerInfo.Unchanged = callerInfo.Unchanged && info.Unchanged
+			if info.Upstream != "" {
+				callerInfo.Upstream = info.Upstream
+			}
+		}
+	}
+	return &queried, err
+}
+
+// RateLimitBudget returns how long the configured client-side rate limit needs at most to let `n` requests through,
+// assuming that the tokens have been used up by other requests.
+//
+// It should be added to the timeout of batch queries like PlanetsByIndex.
+func (c *Client) RateLimitBudget(n int) time.Duration {
+	if c.rateLimit <= 0 || c.rateLimitWindow <= 0 || n <= 0 {
+		return 0
+	}
+	return time.Duration(n) * c.rateLimitWindow / time.Duration(c.rateLimit)
 }
 
-// Planets returns all planets in the current war
-func (c *Client) Planets(ctx context.Context) (*[]api.Planet, error) {
-	return processResp(ctx, c.api.GetApiV1PlanetsAllWithResponse)
+// inWar returns a scope which restricts queries to upstreams serving the war with ID `warID`.
+//
+// The API only serves the current war of an upstream, which might already have moved on to the next war season, or not yet.
+// The war of each upstream is remembered until the next call of WarID and only queried again if it does not match.
+func (c *Client) inWar(warID int32) func(context.Context, *upstream) error {
+	return func(ctx context.Context, u *upstream) error {
+		if u.currentWar() == warID {
+			return nil
+		}
+		// the caller is only interested in the info of the scoped query
+		resp, err := processResp[api.WarId](WithResponseInfo(ctx, new(ResponseInfo)), u.api.GetRawApiWarSeasonCurrentWarIDWithResponse)
+		if err != nil {
+			return fmt.Errorf("war ID: %w", err)
+		}
+		if resp.Id == nil {
+			return errors.New("war ID: missing in response")
+		}
+		u.setWar(*resp.Id)
+		if *resp.Id != warID {
+			return fmt.Errorf("serves war %d instead of %d", *resp.Id, warID)
+		}
+		return nil
+	}
+}
+
+// warFreshness returns the time the war data served by `u` dates from.
+// It marks `u` as stale if the data has not advanced for too long.
+func (c *Client) warFreshness(u *upstream, war *api.War) (time.Time, bool) {
+	if war.Now == nil {
+		return time.Time{}, true
+	}
+	fresh := c.staleAfter <= 0 || time.Since(*war.Now) <= c.staleAfter
+	if u.setStale(!fresh) {
+		if fresh {
+			c.log.Info("upstream serves current data again", "upstream", u.url)
+		} else {
+			c.log.Warn("upstream is stale", "upstream", u.url, "data_time", *war.Now)
+		}
+	}
+	return *war.Now, fresh
+}
+
+// candidates returns all upstreams in the order they should be tried.
+//
+// Healthy upstreams come first, either in configured order or starting with the upstream
+// which served the latest request. Unhealthy upstreams are only tried as last resort.
+// If `ctx` is pinned to an upstream using WithUpstream, only that upstream is returned.
+func (c *Client) candidates(ctx context.Context) []*upstream {
+	if url, ok := pinnedUpstreamFrom(ctx); ok {
+		for _, u := range c.upstreams {
+			if u.url == url {
+				return []*upstream{u}
+			}
+		}
+	}
+
+	c.mu.Lock()
+	start := c.active
+	c.mu.Unlock()
+	if c.preferPrimary {
+		start = 0
+	}
+
+	healthy := make([]*upstream, 0, len(c.upstreams))
+	var unhealthy []*upstream
+	for i := range c.upstreams {
+		u := c.upstreams[(start+i)%len(c.upstreams)]
+		if u.healthy() {
+			healthy = append(healthy, u)
+		} else {
+			unhealthy = append(unhealthy, u)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// markServed records that `u` successfully served `endpoint`.
+//
+// It returns false if the endpoint was previously served by another upstream.
+func (c *Client) markServed(endpoint string, u *upstream) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.upstreams {
+		if c.upstreams[i] == u {
+			c.active = i
+		}
+	}
+	previous, ok := c.served[endpoint]
+	c.served[endpoint] = u
+	return ok && previous == u
+}
+
+type requestFunc[PT any] func(context.Context, ...api.RequestEditorFn) (PT, error)
+
+// query tries each upstream until one of them returns a valid response.
+//
+// If `scope` is not nil, upstreams for which it returns an error are skipped.
+// If `freshness` is not nil, it returns the time the data dates from and whether it is current.
+// Outdated responses are only used if no upstream has current data, in which case the most recent one is returned.
+func query[
+	T any,
+	PT interface{ Data() (*T, error) },
+](
+	ctx context.Context,
+	c *Client,
+	endpoint string,
+	request func(*api.ClientWithResponses) requestFunc[PT],
+	scope func(context.Context, *upstream) error,
+	freshness func(*upstream, *T) (time.Time, bool),
+) (*T, error) {
+	var (
+		errs         []error
+		fallback     *T
+		fallbackU    *upstream
+		fallbackInfo ResponseInfo
+		fallbackTime time.Time
+	)
+
+	for _, u := range c.candidates(ctx) {
+		var (
+			info = new(ResponseInfo)
+			data *T
+			err  error
+		)
+		if scope != nil {
+			err = scope(ctx, u)
+		}
+		if err == nil {
+			data, err = processResp(WithResponseInfo(ctx, info), request(u.api))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", u.url, err))
+			if ctx.Err() != nil {
+				break
+			}
+			if len(c.upstreams) > 1 {
+				c.log.WarnContext(ctx, "query failed, trying next upstream", logging.KeyEndpoint, endpoint, "upstream", u.url, logging.Err(err))
+			}
+			continue
+		}
+		if freshness != nil {
+			dataTime, fresh := freshness(u, data)
+			if !fresh {
+				if fallback == nil || dataTime.After(fallbackTime) {
+					fallback, fallbackU, fallbackInfo, fallbackTime = data, u, *info, dataTime
+				}
+				continue
+			}
+		}
+		c.finish(ctx, endpoint, u, *info)
+		return data, nil
+	}
+
+	if fallback != nil {
+		c.log.WarnContext(ctx, "no upstream has current data", logging.KeyEndpoint, endpoint, "upstream", fallbackU.url)
+		c.finish(ctx, endpoint, fallbackU, fallbackInfo)
+		return fallback, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// finish reports the response metadata of a successful query to the caller.
+func (c *Client) finish(ctx context.Context, endpoint string, u *upstream, info ResponseInfo) {
+	if !c.markServed(endpoint, u) {
+		// the response is only unchanged if compared to the same upstream's previous response
+		info.Unchanged = false
+	}
+	info.Upstream = u.url
+	if callerInfo := responseInfoFrom(ctx); callerInfo != nil {
+		*callerInfo = info
+	}
 }
 
 func processResp[
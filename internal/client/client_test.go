@@ -5,26 +5,37 @@ package client
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"testing"
 
 	"github.com/stnokott/helldivers-client/internal/config"
 )
 
-var logger = log.Default()
+var logger = slog.Default()
+
+var testIdentity = DefaultIdentity("helldivers-client", "0.0.0", "test")
 
 func mustClient() *Client {
 	config := config.MustGet()
 
-	client, err := New(config, logger)
+	client, err := New(config, testIdentity, nil, logger)
 	if err != nil {
 		panic(err)
 	}
 	return client
 }
 
+func mustWarID(client *Client) int32 {
+	id, err := client.WarID(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	return *id.Id
+}
+
 func TestClientHosts(t *testing.T) {
-	host := config.MustGet().APIRootURL
+	host := config.MustGet().APIRootURLs[0]
+	warID := mustWarID(mustClient())
 	tests := []struct {
 		name    string
 		cfg     *config.Config
@@ -32,29 +43,29 @@ func TestClientHosts(t *testing.T) {
 	}{
 		{
 			name:    "trailing slash",
-			cfg:     &config.Config{APIRootURL: host + "/"},
+			cfg:     &config.Config{APIRootURLs: []string{host + "/"}},
 			wantErr: false,
 		},
 		{
 			name:    "no trailing slash",
-			cfg:     &config.Config{APIRootURL: host},
+			cfg:     &config.Config{APIRootURLs: []string{host}},
 			wantErr: false,
 		},
 		{
 			name:    "wrong endpoint",
-			cfg:     &config.Config{APIRootURL: host + "/api"},
+			cfg:     &config.Config{APIRootURLs: []string{host + "/api"}},
 			wantErr: true,
 		},
 		{
 			name:    "wrong host",
-			cfg:     &config.Config{APIRootURL: "127.0.0.1"},
+			cfg:     &config.Config{APIRootURLs: []string{"127.0.0.1"}},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, _ := New(tt.cfg, logger)
-			_, err := client.War(context.Background())
+			client, _ := New(tt.cfg, testIdentity, nil, logger)
+			_, err := client.War(context.Background(), warID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Client.War() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -82,7 +93,7 @@ func TestClientWarId(t *testing.T) {
 
 func TestClientWar(t *testing.T) {
 	client := mustClient()
-	got, err := client.War(context.Background())
+	got, err := client.War(context.Background(), mustWarID(client))
 	if err != nil {
 		t.Errorf("Client.War() error = %v, want nil", err)
 		return
@@ -99,7 +110,7 @@ func TestClientWar(t *testing.T) {
 
 func TestClientAssignments(t *testing.T) {
 	client := mustClient()
-	got, err := client.Assignments(context.Background())
+	got, err := client.Assignments(context.Background(), mustWarID(client))
 	if err != nil {
 		t.Errorf("Client.Assignments() error = %v, want nil", err)
 		return
@@ -121,7 +132,7 @@ func TestClientAssignments(t *testing.T) {
 
 func TestClientCampaigns(t *testing.T) {
 	client := mustClient()
-	got, err := client.Campaigns(context.Background())
+	got, err := client.Campaigns(context.Background(), mustWarID(client))
 	if err != nil {
 		t.Errorf("Client.Campaigns() error = %v, want nil", err)
 		return
@@ -157,7 +168,7 @@ func TestClientDispatches(t *testing.T) {
 
 func TestClientPlanets(t *testing.T) {
 	client := mustClient()
-	got, err := client.Planets(context.Background())
+	got, err := client.Planets(context.Background(), mustWarID(client))
 	if err != nil {
 		t.Errorf("Client.Planets() error = %v, want nil", err)
 		return
@@ -1,100 +1,260 @@
 package client
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/stnokott/helldivers-client/internal/logging"
+	"github.com/stnokott/helldivers-client/internal/metrics"
 )
 
 var defaultBackoff = 5 * time.Second
 
-// rateLimitHTTPClient defines a non-thread safe HTTP client which can automatically deal with rate limit headers.
+// rateLimitHTTPClient defines an HTTP client which can automatically deal with rate limits and transient errors.
+//
+// It is safe for concurrent use. All requests share a single token bucket, which is initialized
+// from the configured limit and adjusted from `X-RateLimit-*` response headers when present.
+// When the API responds with HTTP 429, all callers pause until the backoff has passed.
+//
+// Idempotent requests failing with a transient network error or HTTP 5xx are retried with exponential backoff.
+// If requests keep failing, a circuit breaker rejects further requests for a while.
 type rateLimitHTTPClient struct {
-	client   *http.Client
-	maxRetry int
-	log      *log.Logger
+	client  *http.Client
+	retry   retryPolicy
+	limiter *rate.Limiter
+	window  time.Duration
+	breaker *circuitBreaker
+	// metrics is nil if metrics are disabled
+	metrics *metrics.Metrics
+	log     *slog.Logger
+
+	mu          sync.Mutex
+	pausedUntil time.Time
 }
 
-func newRateLimitHTTPClient(maxRetry int, logger *log.Logger) *rateLimitHTTPClient {
+// iterResult describes what to do after a single request attempt.
+type iterResult int
+
+const (
+	iterDone         iterResult = iota // return response or error to caller
+	iterRetryBackoff                   // retry after exponential backoff
+	iterRetryPaused                    // retry once the shared pause has passed
+)
+
+// newRateLimitHTTPClient creates a new client allowing `limit` requests per `window`.
+// A limit <= 0 disables client-side rate limiting until a limit is learned from response headers.
+func newRateLimitHTTPClient(retry retryPolicy, limit int, window time.Duration, breaker *circuitBreaker, logger *slog.Logger) *rateLimitHTTPClient {
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if limit > 0 && window > 0 {
+		limiter = rate.NewLimiter(perWindow(limit, window), limit)
+	}
 	return &rateLimitHTTPClient{
-		client:   http.DefaultClient,
-		maxRetry: maxRetry,
-		log:      logger,
+		client:  http.DefaultClient,
+		retry:   retry,
+		limiter: limiter,
+		window:  window,
+		breaker: breaker,
+		log:     logger,
 	}
 }
 
+func perWindow(limit int, window time.Duration) rate.Limit {
+	return rate.Limit(float64(limit) / window.Seconds())
+}
+
 func (c *rateLimitHTTPClient) Do(req *http.Request) (*http.Response, error) {
-	c.log.Printf("requesting %s", req.URL.Path)
+	trial, err := c.breaker.allow()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if req.Context().Err() == nil {
+		c.breaker.record(err == nil)
+	} else if trial {
+		// cancellation by the caller says nothing about the API health, but must not block further trials
+		c.breaker.release()
+	}
+	return resp, err
+}
+
+func (c *rateLimitHTTPClient) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	c.log.DebugContext(ctx, "requesting", "path", req.URL.Path)
+	endpoint := routeOf(req.URL.Path)
 
 	// start retry loop
-	for n := c.maxRetry; n >= 0; n-- {
-		resp, err := c.doIter(req)
-		if resp != nil {
-			return resp, nil
-		}
-		if err != nil {
+	for attempt := 0; ; attempt++ {
+		if err := c.wait(ctx); err != nil {
 			return nil, err
 		}
-		if n > 0 {
-			c.log.Printf("will retry %d more times", n)
+		resp, result, err := c.doIter(req, endpoint)
+		if result == iterDone {
+			return resp, err
+		}
+		if attempt >= c.retry.maxRetry {
+			return nil, fmt.Errorf("no valid response after %d retries: %w", c.retry.maxRetry, err)
+		}
+		c.log.InfoContext(ctx, "will retry", "path", req.URL.Path, "remaining", c.retry.maxRetry-attempt)
+		c.metrics.ObserveRetry(endpoint)
+		if result == iterRetryBackoff {
+			backoff := c.retry.backoff(attempt)
+			c.log.InfoContext(ctx, "retrying after backoff", "path", req.URL.Path, "backoff", backoff.Round(time.Millisecond))
+			if err := sleep(ctx, backoff); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// wait blocks until a shared backoff has passed and a token is available.
+//
+// Another request may pause all callers while this one is waiting for a token,
+// so the pause is checked again until none is active once the token has been taken.
+func (c *rateLimitHTTPClient) wait(ctx context.Context) error {
+	for {
+		if pause := c.pauseRemaining(); pause > 0 {
+			if err := sleep(ctx, pause); err != nil {
+				return err
+			}
+		}
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
 		}
-		// no response, but also no err, so we go to the next iteration
+		if c.pauseRemaining() <= 0 {
+			return nil
+		}
+	}
+}
+
+// pauseRemaining returns how long all callers still have to wait, or a non-positive duration if they don't.
+func (c *rateLimitHTTPClient) pauseRemaining() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Until(c.pausedUntil)
+}
+
+// pause makes all callers wait for at least `d` before sending their next request.
+func (c *rateLimitHTTPClient) pause(d time.Duration) {
+	until := time.Now().Add(d)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if until.After(c.pausedUntil) {
+		c.pausedUntil = until
 	}
-	return nil, fmt.Errorf("no valid response after %d retries", c.maxRetry)
 }
 
 // doIter performs a retry iteration.
-// It can have three different outcomes:
-//  1. successful request -> returns response, nil err
-//  2. unsuccessful request -> waits if 429, then returns nil response, nil err
-//  3. context cancelled while waiting -> returns nil response, err
-func (c *rateLimitHTTPClient) doIter(req *http.Request) (*http.Response, error) {
+// It can have the following outcomes:
+//  1. response which should not be retried (success or client error) -> returns response, iterDone
+//  2. transient network error or HTTP 5xx -> returns error, iterRetryBackoff
+//  3. HTTP 429 or HTTP 5xx with Retry-After -> pauses all callers, returns error, iterRetryPaused
+//  4. non-retryable error (e.g. context cancelled) -> returns error, iterDone
+func (c *rateLimitHTTPClient) doIter(req *http.Request, endpoint string) (*http.Response, iterResult, error) {
+	idempotent := isIdempotent(req.Method)
+
+	start := time.Now()
 	resp, err := c.client.Do(req)
-	if err == nil && resp.StatusCode == 200 {
-		return resp, nil
-	}
 	if err != nil {
-		c.log.Printf("** HTTP error: %v", err)
-		return nil, err
+		c.metrics.ObserveRequest(endpoint, 0, time.Since(start))
+		c.log.WarnContext(req.Context(), "HTTP error", "path", req.URL.Path, logging.Err(err))
+		if idempotent && req.Context().Err() == nil && isTransient(err) {
+			return nil, iterRetryBackoff, err
+		}
+		return nil, iterDone, err
+	}
+	c.metrics.ObserveRequest(endpoint, resp.StatusCode, time.Since(start))
+	c.learnLimits(resp.Header)
+	if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return resp, iterDone, nil
+	}
+
+	c.log.WarnContext(req.Context(), "HTTP error", "path", req.URL.Path, "status", resp.StatusCode)
+	_ = resp.Body.Close()
+	errStatus := fmt.Errorf("HTTP status %s", resp.Status)
+	if !idempotent {
+		return nil, iterDone, errStatus
 	}
-	c.log.Printf("** HTTP error: %s", resp.Status)
 	if resp.StatusCode != http.StatusTooManyRequests {
-		// no wait needed, immediately return
-		return nil, nil
+		// the server might tell us how long it will be unavailable, which replaces the exponential backoff
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			c.pause(retryAfter)
+			return nil, iterRetryPaused, errStatus
+		}
+		return nil, iterRetryBackoff, errStatus
 	}
 
+	c.metrics.ObserveRateLimited(endpoint)
 	retryAfter := c.retryAfter(resp.Header)
-	c.log.Printf("** retrying after %s", retryAfter.String())
-	backoffTimer := time.NewTimer(retryAfter)
-	ctx := req.Context()
-	select {
-	case <-backoffTimer.C:
-		return nil, nil
-	case <-ctx.Done():
-		if !backoffTimer.Stop() {
-			// drain channel
-			<-backoffTimer.C
+	c.log.WarnContext(req.Context(), "rate limited, pausing all requests", "path", req.URL.Path, "pause", retryAfter)
+	c.pause(retryAfter)
+	return nil, iterRetryPaused, errStatus
+}
+
+// learnLimits adjusts the token bucket to the limits announced by the server.
+//
+// Supported headers are `X-RateLimit-Limit` (requests per configured window),
+// `X-RateLimit-Remaining` and `X-RateLimit-Reset` (seconds until the window resets).
+func (c *rateLimitHTTPClient) learnLimits(header http.Header) {
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil && limit > 0 && c.window > 0 {
+		if newLimit := perWindow(limit, c.window); newLimit != c.limiter.Limit() || limit != c.limiter.Burst() {
+			c.log.Info("adjusting rate limit", "limit", limit, "window", c.window)
+			c.limiter.SetLimit(newLimit)
+			c.limiter.SetBurst(limit)
 		}
-		return nil, ctx.Err()
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining > 0 {
+		return
+	}
+	if reset, err := strconv.Atoi(header.Get("X-RateLimit-Reset")); err == nil && reset > 0 {
+		c.pause(time.Duration(reset) * time.Second)
 	}
 }
 
 func (c *rateLimitHTTPClient) retryAfter(header http.Header) time.Duration {
-	var backoff time.Duration
 	retryAfter := header.Get("Retry-After")
 	if retryAfter == "" {
-		c.log.Println("got no 'Retry-After' response header, using default backoff")
-		backoff = defaultBackoff
-	} else {
-		parsed, err := strconv.Atoi(retryAfter)
-		if err != nil {
-			c.log.Printf("got invalid 'Retry-After' response header (%v), using default backoff", err)
-			backoff = defaultBackoff
-		} else {
-			backoff = time.Duration(parsed) * time.Second
-		}
+		c.log.Info("got no 'Retry-After' response header, using default backoff")
+		return defaultBackoff
+	}
+	backoff, ok := parseRetryAfter(retryAfter)
+	if !ok {
+		c.log.Warn("got invalid 'Retry-After' response header, using default backoff", "retry_after", retryAfter)
+		return defaultBackoff
 	}
 	return backoff
 }
+
+// routeOf returns `path` with numeric segments replaced by a placeholder,
+// so that requests for single entities share one endpoint label.
+func routeOf(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			segments[i] = "{index}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// sleep waits for `d` or until the context is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
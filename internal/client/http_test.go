@@ -1,17 +1,31 @@
 package client
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
-	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/stnokott/helldivers-client/internal/logging"
+	"github.com/stnokott/helldivers-client/internal/metrics"
 )
 
+var testRetryPolicy = retryPolicy{
+	maxRetry:  3,
+	baseDelay: 10 * time.Millisecond,
+	maxDelay:  100 * time.Millisecond,
+}
+
 func TestRateLimitHTTPClientRetryAfter(t *testing.T) {
 	c := &rateLimitHTTPClient{
-		log: log.Default(),
+		log: slog.Default(),
 	}
 
 	type args struct {
@@ -29,6 +43,20 @@ func TestRateLimitHTTPClientRetryAfter(t *testing.T) {
 			},
 			want: 56 * time.Second,
 		},
+		{
+			name: "HTTP date",
+			args: args{
+				header: http.Header{"Retry-After": []string{time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}},
+			},
+			want: time.Hour,
+		},
+		{
+			name: "HTTP date in past",
+			args: args{
+				header: http.Header{"Retry-After": []string{"Wed, 21 Oct 2015 07:28:00 GMT"}},
+			},
+			want: 0,
+		},
 		{
 			name: "not a number",
 			args: args{
@@ -46,7 +74,9 @@ func TestRateLimitHTTPClientRetryAfter(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := c.retryAfter(tt.args.header); !reflect.DeepEqual(got, tt.want) {
+			got := c.retryAfter(tt.args.header)
+			// HTTP dates only have a precision of seconds
+			if got > tt.want || got < tt.want-time.Second {
 				t.Errorf("rateLimitHTTPClient.retryAfter() = %v, want %v", got, tt.want)
 			}
 		})
@@ -106,6 +136,52 @@ func TestRateLimitHTTPClient(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "HTTP 404",
+			serverFunc: func() http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(404)
+					w.Write([]byte("Not found"))
+				}
+			},
+			wantErr: false, // not retried, but passed on to the caller
+		},
+		{
+			name: "connection reset once",
+			serverFunc: func() http.HandlerFunc {
+				ok := false
+				ptrOK := &ok
+				return func(w http.ResponseWriter, r *http.Request) {
+					if !*ptrOK {
+						*ptrOK = true
+						conn, _, _ := w.(http.Hijacker).Hijack()
+						conn.Close()
+						return
+					}
+					w.WriteHeader(200)
+					w.Write([]byte("OK"))
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "HTTP 503 with Retry-After once",
+			serverFunc: func() http.HandlerFunc {
+				ok := false
+				ptrOK := &ok
+				return func(w http.ResponseWriter, r *http.Request) {
+					if !*ptrOK {
+						w.Header().Add("Retry-After", "1")
+						w.WriteHeader(503)
+						*ptrOK = true
+					} else {
+						w.WriteHeader(200)
+						w.Write([]byte("OK"))
+					}
+				}
+			},
+			wantErr: false,
+		},
 		{
 			name: "HTTP 500 forever",
 			serverFunc: func() http.HandlerFunc {
@@ -133,11 +209,8 @@ func TestRateLimitHTTPClient(t *testing.T) {
 			server := httptest.NewServer(tt.serverFunc())
 			defer server.Close()
 
-			c := rateLimitHTTPClient{
-				client:   server.Client(),
-				maxRetry: 3,
-				log:      log.Default(),
-			}
+			c := newRateLimitHTTPClient(testRetryPolicy, 0, 0, nil, slog.Default())
+			c.client = server.Client()
 			req, err := http.NewRequest("GET", server.URL, nil)
 			if err != nil {
 				t.Errorf("failed to create request: %v", err)
@@ -157,3 +230,376 @@ func TestRateLimitHTTPClient(t *testing.T) {
 		})
 	}
 }
+
+func doConcurrent(t *testing.T, c *rateLimitHTTPClient, url string, n int) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest("GET", url, nil)
+			if err != nil {
+				t.Errorf("failed to create request: %v", err)
+				return
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Errorf("rateLimitHTTPClient.Do() error = %v, want nil", err)
+				return
+			}
+			_ = resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRateLimitHTTPClientConcurrentLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	// 5 requests per second, so 10 requests need to be spread over at least one second
+	c := newRateLimitHTTPClient(testRetryPolicy, 5, time.Second, nil, logging.Discard())
+	c.client = server.Client()
+
+	start := time.Now()
+	doConcurrent(t, c, server.URL, 10)
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("10 requests took %s, want at least 900ms with a limit of 5 requests per second", elapsed)
+	}
+}
+
+func TestRateLimitHTTPClientConcurrentPause(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		first429 time.Time
+		early    int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if first429.IsZero() {
+			first429 = time.Now()
+			w.Header().Add("Retry-After", "1")
+			w.WriteHeader(429)
+			return
+		}
+		if time.Since(first429) < 900*time.Millisecond {
+			// any request arriving during the backoff period was not paused
+			early++
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	c := newRateLimitHTTPClient(testRetryPolicy, 0, 0, nil, logging.Discard())
+	c.client = server.Client()
+
+	// send the first request alone so that it definitely hits the 429
+	doConcurrent(t, c, server.URL, 1)
+	doConcurrent(t, c, server.URL, 5)
+
+	if early > 0 {
+		t.Errorf("%d requests were sent during the backoff period, want 0", early)
+	}
+}
+
+func TestRateLimitHTTPClientPauseWhileWaiting(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []time.Time
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, time.Now())
+		mu.Unlock()
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	// one request per second, so the second request waits for a token
+	c := newRateLimitHTTPClient(testRetryPolicy, 1, time.Second, nil, logging.Discard())
+	c.client = server.Client()
+
+	start := time.Now()
+	doConcurrent(t, c, server.URL, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		doConcurrent(t, c, server.URL, 1)
+	}()
+	// another request pauses all callers while the second one is still waiting for its token
+	time.Sleep(200 * time.Millisecond)
+	c.pause(1500 * time.Millisecond)
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("server received %d requests, want 2", len(received))
+	}
+	if elapsed := received[1].Sub(start); elapsed < 1600*time.Millisecond {
+		t.Errorf("second request was sent after %s, want after the pause ending at 1.7s", elapsed)
+	}
+}
+
+func TestRateLimitHTTPClientLearnLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-RateLimit-Limit", "20")
+		w.Header().Add("X-RateLimit-Remaining", "19")
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	c := newRateLimitHTTPClient(testRetryPolicy, 5, 10*time.Second, nil, logging.Discard())
+	c.client = server.Client()
+
+	doConcurrent(t, c, server.URL, 3)
+
+	if got, want := c.limiter.Limit(), rate.Limit(2); got != want {
+		t.Errorf("limiter.Limit() = %v, want %v", got, want)
+	}
+	if got, want := c.limiter.Burst(), 20; got != want {
+		t.Errorf("limiter.Burst() = %d, want %d", got, want)
+	}
+}
+
+func TestRateLimitHTTPClientNotIdempotent(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(500)
+	}))
+	defer server.Close()
+
+	c := newRateLimitHTTPClient(testRetryPolicy, 0, 0, nil, logging.Discard())
+	c.client = server.Client()
+
+	req, err := http.NewRequest("POST", server.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	if _, err = c.Do(req); err == nil {
+		t.Error("rateLimitHTTPClient.Do() error = nil, want error")
+	}
+	if requests != 1 {
+		t.Errorf("server received %d requests, want 1", requests)
+	}
+}
+
+func TestRateLimitHTTPClientCircuitBreaker(t *testing.T) {
+	requests := 0
+	healthy := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if healthy {
+			w.WriteHeader(200)
+		} else {
+			w.WriteHeader(500)
+		}
+	}))
+	defer server.Close()
+
+	logger := logging.Discard()
+	noRetry := retryPolicy{maxRetry: 0}
+	breaker := newCircuitBreaker(2, 200*time.Millisecond, logger)
+	c := newRateLimitHTTPClient(noRetry, 0, 0, breaker, logger)
+	c.client = server.Client()
+
+	do := func() error {
+		req, err := http.NewRequest("GET", server.URL, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		resp, err := c.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	// two failures open the breaker
+	for i := 0; i < 2; i++ {
+		if err := do(); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d error = %v, want HTTP error", i, err)
+		}
+	}
+	if err := do(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("request while open error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if requests != 2 {
+		t.Errorf("server received %d requests, want 2", requests)
+	}
+
+	// after the cooldown, a successful trial request closes the breaker again
+	healthy = true
+	time.Sleep(250 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if err := do(); err != nil {
+			t.Fatalf("request %d after cooldown error = %v, want nil", i, err)
+		}
+	}
+}
+
+func TestRateLimitHTTPClientCircuitBreakerCancelledTrial(t *testing.T) {
+	healthy := false
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/block":
+			<-block
+		case healthy:
+			w.WriteHeader(200)
+		default:
+			w.WriteHeader(500)
+		}
+	}))
+	defer server.Close()
+	defer close(block)
+
+	logger := logging.Discard()
+	breaker := newCircuitBreaker(1, 50*time.Millisecond, logger)
+	c := newRateLimitHTTPClient(retryPolicy{maxRetry: 0}, 0, 0, breaker, logger)
+	c.client = server.Client()
+
+	do := func(ctx context.Context, path string) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", server.URL+path, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		resp, err := c.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := do(context.Background(), "/"); err == nil {
+		t.Fatal("request error = nil, want HTTP error")
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// the trial request times out, which must not keep the breaker half-open forever
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := do(ctx, "/block"); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("cancelled trial error = %v, want context error", err)
+	}
+
+	healthy = true
+	if err := do(context.Background(), "/"); err != nil {
+		t.Errorf("request after cancelled trial error = %v, want nil", err)
+	}
+}
+
+func TestRateLimitHTTPClientRetryAfterReplacesBackoff(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Add("Retry-After", "0")
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	slowBackoff := retryPolicy{maxRetry: 1, baseDelay: time.Minute, maxDelay: time.Minute}
+	c := newRateLimitHTTPClient(slowBackoff, 0, 0, nil, logging.Discard())
+	c.client = server.Client()
+
+	// the exponential backoff would exceed the timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("rateLimitHTTPClient.Do() error = %v, want nil", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := retryPolicy{
+		maxRetry:  10,
+		baseDelay: 100 * time.Millisecond,
+		maxDelay:  time.Second,
+	}
+	tests := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{attempt: 0, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{attempt: 1, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{attempt: 3, min: 400 * time.Millisecond, max: 800 * time.Millisecond},
+		{attempt: 4, min: 500 * time.Millisecond, max: time.Second},
+		{attempt: 100, min: 500 * time.Millisecond, max: time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if got := p.backoff(tt.attempt); got < tt.min || got > tt.max {
+				t.Errorf("retryPolicy.backoff(%d) = %s, want between %s and %s", tt.attempt, got, tt.min, tt.max)
+				break
+			}
+		}
+	}
+}
+
+func TestRateLimitHTTPClientMetrics(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Add("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := metrics.New(false)
+	c := newRateLimitHTTPClient(testRetryPolicy, 0, 0, nil, logging.Discard())
+	c.client = server.Client()
+	c.metrics = m
+
+	doConcurrent(t, c, server.URL+"/api/v1/planets/12", 1)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	for _, want := range []string{
+		`helldivers_client_api_requests_total{endpoint="/api/v1/planets/{index}",status="429"} 1`,
+		`helldivers_client_api_requests_total{endpoint="/api/v1/planets/{index}",status="200"} 1`,
+		`helldivers_client_api_retries_total{endpoint="/api/v1/planets/{index}"} 1`,
+		`helldivers_client_api_rate_limited_total{endpoint="/api/v1/planets/{index}"} 1`,
+		`helldivers_client_api_request_duration_seconds_count{endpoint="/api/v1/planets/{index}"} 2`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("metrics do not contain %s", want)
+		}
+	}
+}
+
+func TestRouteOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/planets", "/api/v1/planets"},
+		{"/api/v1/planets/12", "/api/v1/planets/{index}"},
+		{"/raw/api/WarSeason/801/Status", "/raw/api/WarSeason/{index}/Status"},
+	}
+	for _, tt := range tests {
+		if got := routeOf(tt.path); got != tt.want {
+			t.Errorf("routeOf(%s) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
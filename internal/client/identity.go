@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/stnokott/helldivers-client/internal/api"
+	"github.com/stnokott/helldivers-client/internal/config"
+)
+
+// Identity identifies this client to the API operators.
+type Identity struct {
+	// UserAgent is sent as `User-Agent` header
+	UserAgent string
+	// Client is sent as `X-Super-Client` header
+	Client string
+	// Contact is sent as `X-Super-Contact` header
+	Contact string
+}
+
+// DefaultIdentity creates an identity from build information.
+func DefaultIdentity(projectName, version, commit string) Identity {
+	return Identity{
+		UserAgent: fmt.Sprintf("%s/%s (%s)", projectName, version, commit),
+		Client:    projectName,
+	}
+}
+
+// identityFrom returns the identity configured in `cfg`, using `defaults` for unset values.
+func identityFrom(cfg *config.Config, defaults Identity) Identity {
+	id := Identity{
+		UserAgent: cfg.APIUserAgent,
+		Client:    cfg.APIClientName,
+		Contact:   cfg.APIContact,
+	}
+	if id.UserAgent == "" {
+		id.UserAgent = defaults.UserAgent
+	}
+	if id.Client == "" {
+		id.Client = defaults.Client
+	}
+	if id.Contact == "" {
+		id.Contact = defaults.Contact
+	}
+	return id
+}
+
+// identityRequestEditor sets the identification headers on every request.
+// Empty values are not sent.
+func identityRequestEditor(id Identity) api.RequestEditorFn {
+	headers := http.Header{}
+	if id.UserAgent != "" {
+		headers.Set("User-Agent", id.UserAgent)
+	}
+	if id.Client != "" {
+		headers.Set("X-Super-Client", id.Client)
+	}
+	if id.Contact != "" {
+		headers.Set("X-Super-Contact", id.Contact)
+	}
+	return func(_ context.Context, req *http.Request) error {
+		for key, values := range headers {
+			req.Header[key] = values
+		}
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stnokott/helldivers-client/internal/config"
+)
+
+func TestIdentityRequestEditor(t *testing.T) {
+	defaults := DefaultIdentity("helldivers-client", "1.2.3", "abcdef")
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want http.Header
+	}{
+		{
+			name: "defaults",
+			cfg:  config.Config{},
+			want: http.Header{
+				"User-Agent":     {"helldivers-client/1.2.3 (abcdef)"},
+				"X-Super-Client": {"helldivers-client"},
+			},
+		},
+		{
+			name: "configured",
+			cfg: config.Config{
+				APIUserAgent:  "my-client/1.0",
+				APIClientName: "my-client",
+				APIContact:    "me@example.com",
+			},
+			want: http.Header{
+				"User-Agent":      {"my-client/1.0"},
+				"X-Super-Client":  {"my-client"},
+				"X-Super-Contact": {"me@example.com"},
+			},
+		},
+		{
+			name: "contact only",
+			cfg: config.Config{
+				APIContact: "me@example.com",
+			},
+			want: http.Header{
+				"User-Agent":      {"helldivers-client/1.2.3 (abcdef)"},
+				"X-Super-Client":  {"helldivers-client"},
+				"X-Super-Contact": {"me@example.com"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "http://localhost", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			editor := identityRequestEditor(identityFrom(&tt.cfg, defaults))
+			if err = editor(context.Background(), req); err != nil {
+				t.Fatalf("editor error = %v", err)
+			}
+			for key := range tt.want {
+				if got := req.Header.Get(key); got != tt.want.Get(key) {
+					t.Errorf("header %s = %q, want %q", key, got, tt.want.Get(key))
+				}
+			}
+			if len(req.Header) != len(tt.want) {
+				t.Errorf("got headers %v, want %v", req.Header, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// retryPolicy defines how often and how long to wait between retries of failed requests.
+type retryPolicy struct {
+	maxRetry  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// backoff returns the delay before retry number `attempt` (starting at 0).
+//
+// The delay grows exponentially up to maxDelay. Half of it is randomized so that
+// concurrent callers don't retry in lockstep.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	d := p.maxDelay
+	if attempt < 32 {
+		if exp := p.baseDelay << attempt; exp > 0 && exp < p.maxDelay {
+			d = exp
+		}
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// isIdempotent reports whether a request may safely be sent again.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransient reports whether a transport error is likely to go away when retrying.
+func isTransient(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// parseRetryAfter parses a `Retry-After` header value, which is either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(time.Until(date), 0), true
+}
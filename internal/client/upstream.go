@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/api"
+	"github.com/stnokott/helldivers-client/internal/metrics"
+)
+
+// upstream is a single API instance.
+//
+// Each upstream has its own rate limit, circuit breaker and response cache,
+// so that a failing instance does not affect the others.
+type upstream struct {
+	url     string
+	api     *api.ClientWithResponses
+	cache   *cachingHTTPClient
+	breaker *circuitBreaker
+
+	mu    sync.Mutex
+	stale bool
+	// war is the ID of the war the upstream served last, 0 if unknown
+	war int32
+}
+
+func newUpstream(url string, options upstreamOptions, logger *slog.Logger) (*upstream, error) {
+	breaker := newCircuitBreaker(options.breakerThreshold, options.breakerCooldown, logger)
+	rateLimited := newRateLimitHTTPClient(options.retry, options.rateLimit, options.rateLimitWindow, breaker, logger)
+	rateLimited.metrics = options.metrics
+	cache := newCachingHTTPClient(rateLimited, logger)
+	apiOptions := append([]api.ClientOption{api.WithHTTPClient(cache)}, options.apiOptions...)
+	c, err := api.NewClientWithResponses(url, apiOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("client initialization for %s: %w", url, err)
+	}
+	return &upstream{
+		url:     url,
+		api:     c,
+		cache:   cache,
+		breaker: breaker,
+	}, nil
+}
+
+// healthy reports whether the upstream is expected to serve current data.
+func (u *upstream) healthy() bool {
+	return !u.breaker.open() && !u.isStale()
+}
+
+func (u *upstream) isStale() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.stale
+}
+
+// setStale marks the upstream as serving outdated data.
+// It returns true if the state changed.
+func (u *upstream) setStale(stale bool) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	changed := u.stale != stale
+	u.stale = stale
+	return changed
+}
+
+// currentWar returns the ID of the war the upstream served last, or 0 if it is unknown.
+func (u *upstream) currentWar() int32 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.war
+}
+
+func (u *upstream) setWar(id int32) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.war = id
+}
+
+// upstreamOptions contains the settings shared by all upstreams.
+type upstreamOptions struct {
+	retry            retryPolicy
+	rateLimit        int
+	rateLimitWindow  time.Duration
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	apiOptions       []api.ClientOption
+	metrics          *metrics.Metrics
+}
+
+type pinnedUpstreamKey struct{}
+
+// WithUpstream returns a context which restricts all queries to the upstream with root URL `url`.
+//
+// It is used to query all endpoints of a single sync from the same upstream, so that the data is consistent.
+// Queries fail instead of failing over to another upstream.
+func WithUpstream(ctx context.Context, url string) context.Context {
+	return context.WithValue(ctx, pinnedUpstreamKey{}, url)
+}
+
+func pinnedUpstreamFrom(ctx context.Context) (string, bool) {
+	url, ok := ctx.Value(pinnedUpstreamKey{}).(string)
+	return url, ok
+}
@@ -0,0 +1,287 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/config"
+	"github.com/stnokott/helldivers-client/internal/logging"
+)
+
+// fakeUpstream serves the war and war ID endpoints with a configurable status, war time and war ID.
+type fakeUpstream struct {
+	*httptest.Server
+	status   atomic.Int32
+	now      atomic.Int64
+	warID    atomic.Int32
+	requests atomic.Int32
+}
+
+const fakeWarID = 801
+
+func newFakeUpstream(t *testing.T) *fakeUpstream {
+	t.Helper()
+	u := new(fakeUpstream)
+	u.status.Store(http.StatusOK)
+	u.now.Store(time.Now().Unix())
+	u.warID.Store(fakeWarID)
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.requests.Add(1)
+		status := int(u.status.Load())
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/raw/api/WarSeason/current/WarID" {
+			_, _ = fmt.Fprintf(w, `{"id":%d}`, u.warID.Load())
+			return
+		}
+		now := time.Unix(u.now.Load(), 0).UTC().Format(time.RFC3339)
+		_, _ = fmt.Fprintf(w, `{"now":%q}`, now)
+	}))
+	t.Cleanup(u.Close)
+	return u
+}
+
+func newFailoverClient(t *testing.T, preferPrimary bool, upstreams ...*fakeUpstream) *Client {
+	t.Helper()
+	cfg := &config.Config{
+		APIPreferPrimary:      preferPrimary,
+		APIUpstreamStaleAfter: time.Hour,
+	}
+	for _, u := range upstreams {
+		cfg.APIRootURLs = append(cfg.APIRootURLs, u.URL)
+	}
+	c, err := New(cfg, Identity{}, nil, logging.Discard())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return c
+}
+
+func warUpstream(t *testing.T, c *Client) string {
+	t.Helper()
+	info := new(ResponseInfo)
+	if _, err := c.War(WithResponseInfo(context.Background(), info), fakeWarID); err != nil {
+		t.Fatalf("War() error = %v", err)
+	}
+	return info.Upstream
+}
+
+func TestClientFailover(t *testing.T) {
+	primary, secondary := newFakeUpstream(t), newFakeUpstream(t)
+	c := newFailoverClient(t, true, primary, secondary)
+
+	if got := warUpstream(t, c); got != primary.URL {
+		t.Errorf("healthy: served by %s, want primary %s", got, primary.URL)
+	}
+
+	primary.status.Store(http.StatusServiceUnavailable)
+	if got := warUpstream(t, c); got != secondary.URL {
+		t.Errorf("primary failing: served by %s, want secondary %s", got, secondary.URL)
+	}
+
+	primary.status.Store(http.StatusOK)
+	if got := warUpstream(t, c); got != primary.URL {
+		t.Errorf("primary recovered: served by %s, want primary %s", got, primary.URL)
+	}
+
+	secondary.status.Store(http.StatusServiceUnavailable)
+	primary.status.Store(http.StatusServiceUnavailable)
+	if _, err := c.War(context.Background(), fakeWarID); err == nil {
+		t.Error("all upstreams failing: War() error = nil, want error")
+	}
+}
+
+func TestClientFailoverSticky(t *testing.T) {
+	primary, secondary := newFakeUpstream(t), newFakeUpstream(t)
+	c := newFailoverClient(t, false, primary, secondary)
+
+	primary.status.Store(http.StatusServiceUnavailable)
+	if got := warUpstream(t, c); got != secondary.URL {
+		t.Errorf("primary failing: served by %s, want secondary %s", got, secondary.URL)
+	}
+
+	primary.status.Store(http.StatusOK)
+	primaryRequests := primary.requests.Load()
+	if got := warUpstream(t, c); got != secondary.URL {
+		t.Errorf("primary recovered: served by %s, want secondary %s", got, secondary.URL)
+	}
+	if primary.requests.Load() != primaryRequests {
+		t.Error("primary was queried although secondary is healthy")
+	}
+}
+
+func TestClientFailoverStale(t *testing.T) {
+	primary, secondary := newFakeUpstream(t), newFakeUpstream(t)
+	c := newFailoverClient(t, true, primary, secondary)
+
+	primary.now.Store(time.Now().Add(-2 * time.Hour).Unix())
+	if got := warUpstream(t, c); got != secondary.URL {
+		t.Errorf("primary stale: served by %s, want secondary %s", got, secondary.URL)
+	}
+	if c.upstreams[0].healthy() {
+		t.Error("stale primary is considered healthy")
+	}
+
+	secondary.now.Store(time.Now().Add(-3 * time.Hour).Unix())
+	if got := warUpstream(t, c); got != primary.URL {
+		t.Errorf("all stale: served by %s, want most recent primary %s", got, primary.URL)
+	}
+
+	primary.now.Store(time.Now().Unix())
+	if got := warUpstream(t, c); got != primary.URL {
+		t.Errorf("primary fresh again: served by %s, want primary %s", got, primary.URL)
+	}
+	if !c.upstreams[0].healthy() {
+		t.Error("fresh primary is not considered healthy")
+	}
+}
+
+func TestClientFailoverUnchanged(t *testing.T) {
+	primary, secondary := newFakeUpstream(t), newFakeUpstream(t)
+	c := newFailoverClient(t, true, primary, secondary)
+
+	unchanged := func() bool {
+		info := new(ResponseInfo)
+		if _, err := c.War(WithResponseInfo(context.Background(), info), fakeWarID); err != nil {
+			t.Fatalf("War() error = %v", err)
+		}
+		return info.Unchanged
+	}
+
+	_ = unchanged()
+	if !unchanged() {
+		t.Error("identical response from same upstream not reported as unchanged")
+	}
+
+	// both upstreams now have an identical cached response
+	primary.status.Store(http.StatusServiceUnavailable)
+	_ = unchanged()
+	primary.status.Store(http.StatusOK)
+	if unchanged() {
+		t.Error("response reported as unchanged after switching upstreams")
+	}
+}
+
+func TestClientWarScope(t *testing.T) {
+	primary, secondary := newFakeUpstream(t), newFakeUpstream(t)
+	c := newFailoverClient(t, true, primary, secondary)
+
+	// the primary already serves the next war season
+	primary.warID.Store(fakeWarID + 1)
+	if got := warUpstream(t, c); got != secondary.URL {
+		t.Errorf("primary in other war: served by %s, want secondary %s", got, secondary.URL)
+	}
+
+	info := new(ResponseInfo)
+	if _, err := c.War(WithResponseInfo(context.Background(), info), fakeWarID+1); err != nil {
+		t.Fatalf("War() error = %v", err)
+	}
+	if info.Upstream != primary.URL {
+		t.Errorf("next war: served by %s, want primary %s", info.Upstream, primary.URL)
+	}
+
+	// the secondary moves on as well, which is noticed once the current war ID has been queried again
+	secondary.warID.Store(fakeWarID + 1)
+	if _, err := c.WarID(context.Background()); err != nil {
+		t.Fatalf("WarID() error = %v", err)
+	}
+	if _, err := c.War(context.Background(), fakeWarID); err == nil {
+		t.Error("no upstream in war: War() error = nil, want error")
+	}
+}
+
+func TestClientPinnedUpstream(t *testing.T) {
+	primary, secondary := newFakeUpstream(t), newFakeUpstream(t)
+	c := newFailoverClient(t, true, primary, secondary)
+
+	pinned := WithUpstream(context.Background(), secondary.URL)
+	info := new(ResponseInfo)
+	if _, err := c.War(WithResponseInfo(pinned, info), fakeWarID); err != nil {
+		t.Fatalf("War() error = %v", err)
+	}
+	if info.Upstream != secondary.URL {
+		t.Errorf("pinned to secondary: served by %s, want %s", info.Upstream, secondary.URL)
+	}
+
+	// no failover to the healthy primary, which would mix data of different upstreams
+	secondary.status.Store(http.StatusServiceUnavailable)
+	primaryRequests := primary.requests.Load()
+	if _, err := c.War(pinned, fakeWarID); err == nil {
+		t.Error("pinned upstream failing: War() error = nil, want error")
+	}
+	if primary.requests.Load() != primaryRequests {
+		t.Error("primary was queried although the query is pinned to secondary")
+	}
+}
+
+func TestClientPlanetsByIndex(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			current := maxRunning.Load()
+			if n <= current || maxRunning.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		if r.URL.Path == "/api/v1/planets/3" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/raw/api/WarSeason/current/WarID" {
+			_, _ = fmt.Fprintf(w, `{"id":%d}`, fakeWarID)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"index":%s}`, strings.TrimPrefix(r.URL.Path, "/api/v1/planets/"))
+	}))
+	defer server.Close()
+
+	c, err := New(&config.Config{APIRootURLs: []string{server.URL}, APIFetchConcurrency: 2}, Identity{}, nil, logging.Discard())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	info := new(ResponseInfo)
+	planets, err := c.PlanetsByIndex(WithResponseInfo(context.Background(), info), fakeWarID, []int32{1, 2, 3, 4, 5})
+	if err == nil {
+		t.Error("PlanetsByIndex() error = nil, want error for planet 3")
+	}
+	var got []int32
+	for _, planet := range *planets {
+		got = append(got, *planet.Index)
+	}
+	if want := []int32{1, 2, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PlanetsByIndex() = %v, want partial result %v", got, want)
+	}
+	if info.Unchanged {
+		t.Error("partial result reported as unchanged")
+	}
+	if n := maxRunning.Load(); n > 2 {
+		t.Errorf("max. concurrent requests = %d, want <= 2", n)
+	}
+}
+
+func TestClientRateLimitBudget(t *testing.T) {
+	c := &Client{rateLimit: 5, rateLimitWindow: 10 * time.Second}
+	if got := c.RateLimitBudget(15); got != 30*time.Second {
+		t.Errorf("RateLimitBudget(15) = %v, want 30s", got)
+	}
+	c.rateLimit = 0
+	if got := c.RateLimitBudget(15); got != 0 {
+		t.Errorf("RateLimitBudget(15) without rate limit = %v, want 0", got)
+	}
+}
@@ -3,6 +3,9 @@ package db
 import (
 	"context"
 	"fmt"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/stnokott/helldivers-client/internal/db/gen"
 )
@@ -21,32 +24,89 @@ func (a *Assignment) Merge(ctx context.Context, tx *gen.Queries, onMerge onMerge
 	// Since we have static assignment IDs, but Identity task IDs, we cannot easily merge both together.
 	// (Composite types also don't work properly yet, see https://github.com/sqlc-dev/sqlc/issues/2760)
 	// This is why we apply the following procedure:
-	//   1. If assignment exists, delete all connected tasks first
-	//   2. Then, merge the assignment as usual, re-inserting the tasks along the way
-	exists, err := tx.AssignmentExists(ctx, a.ID)
+	//   1. If the tasks changed, delete all connected tasks and insert the new ones
+	//   2. Then, merge the assignment as usual, which leaves it untouched if neither it nor its tasks changed
+	stored, err := tx.GetAssignmentTasks(ctx, gen.GetAssignmentTasksParams{WarID: a.WarID, AssignmentID: a.ID})
 	if err != nil {
-		return fmt.Errorf("check if assignment (ID=%d) exists: %w", a.ID, err)
+		return fmt.Errorf("get tasks of assignment (ID=%d): %w", a.ID, err)
 	}
-	if exists {
-		if err = tx.DeleteAssignmentTasks(ctx, a.ID); err != nil {
+	tasksChanged := !assignmentTasksEqual(stored, a.Tasks)
+	if tasksChanged {
+		if err = tx.DeleteAssignmentTasks(ctx, gen.DeleteAssignmentTasksParams{WarID: a.WarID, AssignmentID: a.ID}); err != nil {
 			return fmt.Errorf("delete assignment tasks: %w", err)
 		}
+		if a.TaskIds, err = insertAssignmentTasks(ctx, tx, a.Tasks); err != nil {
+			return err
+		}
+	} else {
+		a.TaskIds = make([]int64, len(stored))
+		for i, task := range stored {
+			a.TaskIds[i] = task.ID
+		}
 	}
 
-	taskIDs, err := insertAssignmentTasks(ctx, tx, a.Tasks)
-	if err != nil {
-		return err
+	// existence of the assignment is also required for the task statistics
+	var exists bool
+	onAssignmentMerge := func(table gen.Table, e bool, affectedRows int64) {
+		exists = e
+		onMerge(table, e, affectedRows)
 	}
-	a.TaskIds = taskIDs
-
-	if _, err = tx.MergeAssignment(ctx, gen.MergeAssignmentParams(a.Assignment)); err != nil {
+	if err = upsert(ctx, gen.TableAssignments, tx.MergeAssignment, gen.MergeAssignmentParams(a.Assignment), onAssignmentMerge); err != nil {
 		return fmt.Errorf("insert assignment '%s': %v", a.Title, err)
 	}
-	onMerge(gen.TableAssignments, exists, 1)
-	onMerge(gen.TableAssignmentTasks, exists, int64(len(taskIDs)))
+	if tasksChanged {
+		onMerge(gen.TableAssignmentTasks, exists, int64(len(a.TaskIds)))
+	} else {
+		onMerge(gen.TableAssignmentTasks, true, 0)
+	}
 	return nil
 }
 
+// assignmentTasksEqual reports whether the stored tasks of an assignment equal `tasks`, in order.
+func assignmentTasksEqual(stored []gen.AssignmentTask, tasks []gen.AssignmentTask) bool {
+	if len(stored) != len(tasks) {
+		return false
+	}
+	for i, task := range tasks {
+		if stored[i].TaskType != task.TaskType ||
+			!numericsEqual(stored[i].Values, task.Values) ||
+			!numericsEqual(stored[i].ValueTypes, task.ValueTypes) {
+			return false
+		}
+	}
+	return true
+}
+
+// numericsEqual reports whether `a` and `b` contain the same numbers, regardless of their representation.
+func numericsEqual(a, b []pgtype.Numeric) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !numericEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func numericEqual(a, b pgtype.Numeric) bool {
+	if !a.Valid || !b.Valid || a.NaN || b.NaN || a.InfinityModifier != b.InfinityModifier {
+		return a.Valid == b.Valid && a.NaN == b.NaN && a.InfinityModifier == b.InfinityModifier
+	}
+	if a.Int == nil || b.Int == nil {
+		return a.Int == b.Int
+	}
+	// scale the number with the larger exponent, e.g. 7e2 and 700e0
+	x, y := new(big.Int).Set(a.Int), new(big.Int).Set(b.Int)
+	if a.Exp > b.Exp {
+		x.Mul(x, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(a.Exp-b.Exp)), nil))
+	} else if b.Exp > a.Exp {
+		y.Mul(y, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(b.Exp-a.Exp)), nil))
+	}
+	return x.Cmp(y) == 0
+}
+
 func insertAssignmentTasks(ctx context.Context, tx *gen.Queries, tasks []gen.AssignmentTask) ([]int64, error) {
 	taskIDs := make([]int64, len(tasks))
 	for i, task := range tasks {
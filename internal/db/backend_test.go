@@ -0,0 +1,72 @@
+//go:build integration
+
+package db_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/dbtest"
+)
+
+// backend runs the tests of package dbtest against PostgreSQL.
+var backend = dbtest.Backend{
+	New: func(t *testing.T) dbtest.Store {
+		return db.NewMigratedClient(t)
+	},
+	// counts are stored as numeric
+	MaxCount: math.MaxUint64,
+}
+
+func TestMergeStats(t *testing.T) {
+	dbtest.MergeStats(t, backend)
+}
+
+func TestSchema(t *testing.T) {
+	dbtest.Schema(t, backend)
+}
+
+func TestWars(t *testing.T) {
+	dbtest.Wars(t, backend)
+}
+
+func TestPlanets(t *testing.T) {
+	dbtest.Planets(t, backend)
+}
+
+func TestCampaigns(t *testing.T) {
+	dbtest.Campaigns(t, backend)
+}
+
+func TestEvents(t *testing.T) {
+	dbtest.Events(t, backend)
+}
+
+func TestAssignments(t *testing.T) {
+	dbtest.Assignments(t, backend)
+}
+
+func TestDispatches(t *testing.T) {
+	dbtest.Dispatches(t, backend)
+}
+
+func TestSnapshots(t *testing.T) {
+	dbtest.Snapshots(t, backend)
+}
+
+func TestSnapshotGaps(t *testing.T) {
+	dbtest.SnapshotGaps(t, backend)
+}
+
+func TestCampaignLifecycles(t *testing.T) {
+	dbtest.CampaignLifecycles(t, backend)
+}
+
+func TestWarClosure(t *testing.T) {
+	dbtest.WarClosure(t, backend)
+}
+
+func TestSyncRuns(t *testing.T) {
+	dbtest.SyncRuns(t, backend)
+}
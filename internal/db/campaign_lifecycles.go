@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+)
+
+// compile-time implementation check
+var _ EntityMerger = (*CampaignLifecycles)(nil)
+
+// CampaignLifecycles implements EntityMerger.
+//
+// It updates the lifecycles of all campaigns of a war according to the campaigns active at `Seen`:
+// active campaigns are started or extended, all others are ended.
+// Outcomes of ended campaigns are resolved once the planet of the campaign has been captured in a later snapshot.
+type CampaignLifecycles struct {
+	WarID int32
+	Seen  pgtype.Timestamp
+	// CampaignIDs are the IDs of the campaigns active at `Seen`, which must have been merged already.
+	CampaignIDs []int32
+	// PlanetOwners maps the IDs of all planets captured at `Seen` to their current owner.
+	// It resolves the outcomes of all ended campaigns on these planets.
+	PlanetOwners map[int32]string
+}
+
+// Merge implements EntityMerger.
+func (l *CampaignLifecycles) Merge(ctx context.Context, tx *gen.Queries, onMerge onMergeFunc) error {
+	for _, id := range l.CampaignIDs {
+		if err := upsert(ctx, gen.TableCampaignLifecycles, tx.MergeCampaignLifecycle, gen.MergeCampaignLifecycleParams{
+			WarID:      l.WarID,
+			CampaignID: id,
+			Seen:       l.Seen,
+		}, onMerge); err != nil {
+			return fmt.Errorf("failed to merge lifecycle of campaign ID=%d: %v", id, err)
+		}
+	}
+
+	rows, err := tx.EndCampaignLifecycles(ctx, gen.EndCampaignLifecyclesParams{WarID: l.WarID, ActiveIds: l.CampaignIDs})
+	if err != nil {
+		return fmt.Errorf("failed to end campaign lifecycles: %v", err)
+	}
+	// bulk updates are only reported if they changed anything, they do not correspond to a single entity
+	if rows > 0 {
+		onMerge(gen.TableCampaignLifecycles, true, rows)
+	}
+
+	planetIDs, owners := l.capturedPlanets()
+	rows, err = tx.ResolveCampaignOutcomes(ctx, gen.ResolveCampaignOutcomesParams{PlanetIds: planetIDs, Owners: owners, WarID: l.WarID})
+	if err != nil {
+		return fmt.Errorf("failed to resolve campaign outcomes: %v", err)
+	}
+	if rows > 0 {
+		onMerge(gen.TableCampaignLifecycles, true, rows)
+	}
+	return nil
+}
+
+// capturedPlanets returns the IDs of the planets in PlanetOwners, ordered by ID, and their owners.
+func (l *CampaignLifecycles) capturedPlanets() (planetIDs []int32, owners []string) {
+	planetIDs = make([]int32, 0, len(l.PlanetOwners))
+	for id := range l.PlanetOwners {
+		planetIDs = append(planetIDs, id)
+	}
+	slices.Sort(planetIDs)
+	owners = make([]string, len(planetIDs))
+	for i, id := range planetIDs {
+		owners[i] = l.PlanetOwners[id]
+	}
+	return
+}
+
+// CampaignLifecycles returns the lifecycles of all campaigns of war `warID` in the order they started,
+// together with the planets of the campaigns.
+func (c *Client) CampaignLifecycles(ctx context.Context, warID int32) ([]gen.GetCampaignLifecyclesRow, error) {
+	lifecycles, err := c.queries.GetCampaignLifecycles(ctx, warID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query campaign lifecycles: %v", err)
+	}
+	return lifecycles, nil
+}
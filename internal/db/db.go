@@ -4,46 +4,62 @@ package db
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/big"
 	"time"
 
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stnokott/helldivers-client/internal/config"
 	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/logging"
 )
 
 const appName = "HELLDIVERS_2_CLIENT"
 
 // Client is the abstraction layer for the MongoDB connector
 type Client struct {
-	conn    *pgx.Conn
+	pool    *pgxpool.Pool
 	queries *gen.Queries
-	log     *log.Logger
+	log     *slog.Logger
 }
 
 // New creates a new client and connects it to the DB
-func New(cfg *config.Config, logger *log.Logger) (*Client, error) {
-	pgxConfig, err := pgx.ParseConfig(cfg.PostgresURI)
+func New(cfg *config.Config, logger *slog.Logger) (*Client, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.PostgresURI)
 	if err != nil {
 		return nil, fmt.Errorf("parse config from ENV: %w", err)
 	}
-	pgxConfig.RuntimeParams["application_name"] = appName
+	poolConfig.ConnConfig.RuntimeParams["application_name"] = appName
+	if cfg.PostgresMaxConns > 0 {
+		poolConfig.MaxConns = cfg.PostgresMaxConns
+	}
+	poolConfig.MinConns = min(cfg.PostgresMinConns, poolConfig.MaxConns)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	logger.Printf("connecting to %s:%d/%s", pgxConfig.Host, pgxConfig.Port, pgxConfig.Database)
-	conn, err := pgx.ConnectConfig(ctx, pgxConfig)
+	connConfig := poolConfig.ConnConfig
+	logger.Info("connecting",
+		"host", connConfig.Host,
+		"port", connConfig.Port,
+		"database", connConfig.Database,
+		"max_conns", poolConfig.MaxConns,
+	)
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("connect: %w", err)
 	}
+	// the pool connects lazily, so we acquire a connection once to fail early
+	if err = pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("connect: %w", err)
+	}
 
-	queries := gen.New(conn)
+	queries := gen.New(pool)
 
 	return &Client{
-		conn:    conn,
+		pool:    pool,
 		queries: queries,
 		log:     logger,
 	}, nil
@@ -60,8 +76,8 @@ func (c *Client) Connect(ctx context.Context) error {
 			return nil
 		case <-ticker.C:
 			// ensure connection is stable
-			if err := c.conn.Ping(ctx); err != nil {
-				c.log.Printf("connect: %v", err)
+			if err := c.pool.Ping(ctx); err != nil {
+				c.log.Warn("connect failed", logging.Err(err))
 				continue
 			}
 			return nil
@@ -69,14 +85,12 @@ func (c *Client) Connect(ctx context.Context) error {
 	}
 }
 
-// Disconnect disconnects from the MongoDB instance
+// Disconnect closes all connections in the pool.
+//
+// It blocks until all acquired connections have been released and is safe to call multiple times.
 func (c *Client) Disconnect() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := c.conn.Close(ctx); err != nil {
-		return fmt.Errorf("disconnect: %w", err)
-	}
-	c.log.Println("disconnected")
+	c.pool.Close()
+	c.log.Info("disconnected")
 	return nil
 }
 
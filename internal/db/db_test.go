@@ -4,16 +4,16 @@ package db
 
 import (
 	"errors"
-	"io"
-	"log"
+	"log/slog"
 	"testing"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/stnokott/helldivers-client/internal/config"
+	"github.com/stnokott/helldivers-client/internal/logging"
 )
 
 func TestNew(t *testing.T) {
-	logger := log.Default()
+	logger := slog.Default()
 	type args struct {
 		cfg *config.Config
 	}
@@ -43,7 +43,7 @@ func TestNew(t *testing.T) {
 
 func TestClientDisconnect(t *testing.T) {
 	cfg := config.MustGet()
-	client, err := New(cfg, log.Default())
+	client, err := New(cfg, slog.Default())
 	if err != nil {
 		t.Fatalf("could not initialize DB connection: %v", err)
 	}
@@ -58,7 +58,7 @@ func TestClientDisconnect(t *testing.T) {
 func withClient(t *testing.T, do func(client *Client, migration *migrate.Migrate)) {
 	cfg := config.MustGet()
 
-	client, err := New(cfg, log.New(io.Discard, "", 0))
+	client, err := New(cfg, logging.Discard())
 	if err != nil {
 		t.Fatalf("could not initialize DB connection: %v", err)
 	}
@@ -90,3 +90,35 @@ func withClientMigrated(t *testing.T, do func(client *Client)) {
 		do(client)
 	})
 }
+
+func ptr[T any](x T) *T {
+	return &x
+}
+
+// NewMigratedClient connects to the test database and migrates it to the latest version.
+//
+// The database is migrated down again once the test has finished.
+// It is exported for the tests of package db_test.
+func NewMigratedClient(t *testing.T) *Client {
+	t.Helper()
+	client, err := New(config.MustGet(), logging.Discard())
+	if err != nil {
+		t.Fatalf("could not initialize DB connection: %v", err)
+	}
+	migration, err := client.newMigration("../../scripts/migrations")
+	if err != nil {
+		t.Fatalf("client.newMigration() error = %v, want nil", err)
+	}
+	t.Cleanup(func() {
+		if err = migration.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			t.Errorf("failed to migrate down: %v", err)
+		}
+		if err = client.Disconnect(); err != nil {
+			t.Logf("failed to disconnect: %v", err)
+		}
+	})
+	if err = migration.Up(); err != nil {
+		t.Fatalf("failed to migrate up: %v", err)
+	}
+	return client
+}
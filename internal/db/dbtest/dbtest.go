@@ -0,0 +1,294 @@
+// Package dbtest provides tests which run against every storage backend of package db.
+//
+// Each backend calls them from its own tests with a function creating an empty, migrated database.
+package dbtest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+)
+
+// Merger merges entities into a database, as implemented by every backend.
+type Merger interface {
+	Merge(ctx context.Context, collector stats.Collector, entities ...[]db.Entity) error
+}
+
+// Store reads back the merged entities, as implemented by every backend.
+//
+// SnapshotGaps, WarFinalPlanets and SyncRun have no client methods, the tests of each backend add them.
+type Store interface {
+	Merger
+	LatestSnapshotTime(ctx context.Context) (t time.Time, ok bool, err error)
+	LatestWarID(ctx context.Context) (id int32, ok bool, err error)
+	WarSummary(ctx context.Context, warID int32) (summary gen.WarSummary, ok bool, err error)
+	CampaignLifecycles(ctx context.Context, warID int32) ([]gen.GetCampaignLifecyclesRow, error)
+	SnapshotGaps(ctx context.Context, since time.Time) ([]gen.SnapshotGap, error)
+	WarFinalPlanets(ctx context.Context, warID int32) ([]gen.WarFinalPlanet, error)
+	SyncRun(ctx context.Context, id pgtype.UUID) (gen.SyncRun, error)
+}
+
+// Backend is a storage backend under test.
+type Backend struct {
+	// New returns a store for a new, empty database migrated to the latest version.
+	New func(t *testing.T) Store
+	// MaxCount is the largest count the backend stores, e.g. in the snapshot statistics.
+	MaxCount uint64
+}
+
+// historyTables are written by triggers, not by any merger.
+var historyTables = []gen.Table{
+	gen.TablePlanetHistory,
+	gen.TableBiomeHistory,
+	gen.TableCampaignHistory,
+	gen.TableAssignmentHistory,
+	gen.TableDispatchHistory,
+}
+
+// wantStats are the expected merge statistics of a single table.
+type wantStats struct {
+	inserted, updated, noop int64
+}
+
+// MergeStats merges all types of entities in several steps and checks the statistics reported for each table.
+func MergeStats(t *testing.T, backend Backend) {
+	// steps run in order against the same database, each in its own transaction.
+	steps := []struct {
+		name     string
+		entities []db.Entity
+		want     map[gen.Table]wantStats
+	}{
+		{
+			name:     "insert entities",
+			entities: Entities(false),
+			want: map[gen.Table]wantStats{
+				gen.TableWars:            {inserted: 1},
+				gen.TableCampaigns:       {inserted: 1},
+				gen.TableEvents:          {inserted: 1},
+				gen.TableBiomes:          {inserted: 1},
+				gen.TableHazards:         {inserted: 1},
+				gen.TablePlanets:         {inserted: 1},
+				gen.TableAssignmentTasks: {inserted: 1},
+				gen.TableAssignments:     {inserted: 1},
+				gen.TableDispatches:      {inserted: 1},
+			},
+		},
+		{
+			name:     "unchanged entities",
+			entities: Entities(false),
+			want: map[gen.Table]wantStats{
+				gen.TableWars:            {noop: 1},
+				gen.TableCampaigns:       {noop: 1},
+				gen.TableEvents:          {noop: 1},
+				gen.TableBiomes:          {noop: 1},
+				gen.TableHazards:         {noop: 1},
+				gen.TablePlanets:         {noop: 1},
+				gen.TableAssignmentTasks: {noop: 1},
+				gen.TableAssignments:     {noop: 1},
+				gen.TableDispatches:      {noop: 1},
+			},
+		},
+		{
+			name:     "changed entities",
+			entities: Entities(true),
+			want: map[gen.Table]wantStats{
+				gen.TableWars:            {updated: 1},
+				gen.TableCampaigns:       {updated: 1},
+				gen.TableEvents:          {updated: 1},
+				gen.TableBiomes:          {updated: 1},
+				gen.TableHazards:         {updated: 1},
+				gen.TablePlanets:         {updated: 1},
+				gen.TableAssignmentTasks: {updated: 1},
+				gen.TableAssignments:     {updated: 1},
+				gen.TableDispatches:      {updated: 1},
+			},
+		},
+		{
+			name: "insert snapshots",
+			entities: []db.Entity{
+				Snapshot(At(0), "Automatons", 1000),
+				Snapshot(At(10), "Automatons", 3000),
+				Lifecycles(At(0), true, "Automatons"),
+			},
+			want: map[gen.Table]wantStats{
+				gen.TableWarSnapshots:        {inserted: 2},
+				gen.TableEventSnapshots:      {inserted: 2},
+				gen.TableAssignmentSnapshots: {inserted: 2},
+				// one for the snapshot, one for its planet
+				gen.TableSnapshotStatistics: {inserted: 4},
+				gen.TablePlanetSnapshots:    {inserted: 2},
+				gen.TableSnapshots:          {inserted: 2},
+				gen.TableCampaignLifecycles: {inserted: 1},
+			},
+		},
+		{
+			name:     "insert derived",
+			entities: []db.Entity{Gaps(1), Lifecycles(At(10), true, "Automatons"), Closure()},
+			want: map[gen.Table]wantStats{
+				gen.TableSnapshotGaps:       {inserted: 1},
+				gen.TableCampaignLifecycles: {updated: 1},
+				gen.TableWarSummaries:       {inserted: 1},
+				gen.TableWarFinalPlanets:    {inserted: 1},
+			},
+		},
+		{
+			name:     "unchanged derived",
+			entities: []db.Entity{Gaps(1), Lifecycles(At(10), true, "Automatons"), Closure()},
+			want: map[gen.Table]wantStats{
+				gen.TableSnapshotGaps:       {noop: 1},
+				gen.TableCampaignLifecycles: {noop: 1},
+				gen.TableWarSummaries:       {noop: 1},
+				gen.TableWarFinalPlanets:    {noop: 1},
+			},
+		},
+		{
+			name: "changed derived",
+			entities: []db.Entity{
+				Snapshot(At(20), "Humans", 2000),
+				Gaps(2),
+				Lifecycles(At(20), true, "Humans"),
+				Closure(),
+			},
+			want: map[gen.Table]wantStats{
+				gen.TableWarSnapshots:        {inserted: 1},
+				gen.TableEventSnapshots:      {inserted: 1},
+				gen.TableAssignmentSnapshots: {inserted: 1},
+				gen.TableSnapshotStatistics:  {inserted: 2},
+				gen.TablePlanetSnapshots:     {inserted: 1},
+				gen.TableSnapshots:           {inserted: 1},
+				gen.TableSnapshotGaps:        {updated: 1},
+				gen.TableCampaignLifecycles:  {updated: 1},
+				gen.TableWarSummaries:        {updated: 1},
+				gen.TableWarFinalPlanets:     {updated: 1},
+			},
+		},
+		{
+			name:     "insert sync run",
+			entities: []db.Entity{SyncRun()},
+			want: map[gen.Table]wantStats{
+				gen.TableSyncRuns: {inserted: 1},
+			},
+		},
+		{
+			// runs are never updated
+			name:     "unchanged sync run",
+			entities: []db.Entity{SyncRun()},
+			want: map[gen.Table]wantStats{
+				gen.TableSyncRuns: {noop: 1},
+			},
+		},
+	}
+
+	// every table must either be covered by a step or be written by triggers
+	covered := map[gen.Table]bool{}
+	for _, table := range historyTables {
+		covered[table] = true
+	}
+	for _, step := range steps {
+		for table := range step.want {
+			covered[table] = true
+		}
+	}
+	for _, table := range gen.AllTables {
+		if !covered[table] {
+			t.Errorf("table %s is not covered by any merge step", table)
+		}
+	}
+
+	merger := backend.New(t)
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			collector := stats.NewCollector()
+			if err := merger.Merge(context.Background(), collector, step.entities); err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			for _, table := range gen.AllTables {
+				got := collector[table]
+				want := step.want[table]
+				if got.Inserted != want.inserted || got.Updated != want.updated || got.Noop != want.noop {
+					t.Errorf(
+						"%s: inserted/updated/noop = %d/%d/%d, want %d/%d/%d",
+						table, got.Inserted, got.Updated, got.Noop, want.inserted, want.updated, want.noop,
+					)
+				}
+			}
+		})
+	}
+}
+
+// Schema merges valid and invalid entities and checks that the constraints of the schema reject the invalid ones.
+func Schema(t *testing.T, backend Backend) {
+	tests := []struct {
+		name    string
+		entity  db.Entity
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			entity:  &db.Dispatch{ID: 1, CreateTime: db.PGTimestamp(At(0)), Message: "A valid dispatch"},
+			wantErr: false,
+		},
+		{
+			name:    "war FK violation",
+			entity:  &db.Campaign{ID: 1, Count: db.PGUint64(1), WarID: 12345},
+			wantErr: true,
+		},
+		{
+			name: "war without factions",
+			entity: &db.War{
+				ID:        1,
+				StartTime: db.PGTimestamp(At(0)),
+				EndTime:   db.PGTimestamp(At(10)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "war ending before it starts",
+			entity: &db.War{
+				ID:        1,
+				StartTime: db.PGTimestamp(At(10)),
+				EndTime:   db.PGTimestamp(At(0)),
+				Factions:  []string{"Humans"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "count out of range",
+			entity: &db.Campaign{
+				ID:    1,
+				Count: pgtype.Numeric{Int: new(big.Int).Lsh(big.NewInt(1), 64), Valid: true},
+				WarID: WarID,
+			},
+			wantErr: true,
+		},
+		{
+			name: "event ending before it starts",
+			entity: &db.Event{
+				ID:         1,
+				CampaignID: 5,
+				Faction:    "Automatons",
+				MaxHealth:  1,
+				StartTime:  db.PGTimestamp(At(10)),
+				EndTime:    db.PGTimestamp(At(0)),
+				WarID:      WarID,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merger := backend.New(t)
+			err := merger.Merge(context.Background(), stats.NewCollector(), Entities(false), []db.Entity{tt.entity})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Merge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
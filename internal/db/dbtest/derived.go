@@ -0,0 +1,249 @@
+package dbtest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+)
+
+// mergeSnapshots merges the entities and a snapshot of them at each of `minutes`, with the planet owned by `owners`.
+func mergeSnapshots(t *testing.T, store Store, minutes []int, owners []string) {
+	t.Helper()
+	entities := Entities(false)
+	for i, m := range minutes {
+		entities = append(entities, Snapshot(At(m), owners[i], uint64(1000*(i+1))))
+	}
+	if err := store.Merge(context.Background(), stats.NewCollector(), entities); err != nil {
+		t.Fatalf("failed to merge snapshots: %v", err)
+	}
+}
+
+// SnapshotGaps checks that gaps are replaced on every merge and must lie between snapshots.
+func SnapshotGaps(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	store := backend.New(t)
+	mergeSnapshots(t, store, []int{0, 5, 30, 60}, []string{"Automatons", "Automatons", "Automatons", "Automatons"})
+
+	merge := func(gaps ...gen.SnapshotGap) error {
+		return store.Merge(ctx, stats.NewCollector(), []db.Entity{&db.SnapshotGaps{Since: db.PGTimestamp(At(0)), Gaps: gaps}})
+	}
+	if err := merge(
+		gen.SnapshotGap{StartTime: db.PGTimestamp(At(5)), EndTime: db.PGTimestamp(At(30)), MissedRuns: 4},
+		gen.SnapshotGap{StartTime: db.PGTimestamp(At(30)), EndTime: db.PGTimestamp(At(60)), MissedRuns: 5},
+	); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	// second gap has been filled
+	if err := merge(gen.SnapshotGap{StartTime: db.PGTimestamp(At(5)), EndTime: db.PGTimestamp(At(30)), MissedRuns: 3}); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	stored, err := store.SnapshotGaps(ctx, At(0))
+	if err != nil {
+		t.Fatalf("SnapshotGaps() error = %v", err)
+	}
+	if len(stored) != 1 || !stored[0].StartTime.Time.Equal(At(5)) || stored[0].MissedRuns != 3 {
+		t.Errorf("SnapshotGaps() = %+v, want single updated gap starting at %v", stored, At(5))
+	}
+
+	if err = merge(gen.SnapshotGap{StartTime: db.PGTimestamp(At(1)), EndTime: db.PGTimestamp(At(5)), MissedRuns: 1}); err == nil {
+		t.Error("Merge() of gap after non-existent snapshot error = nil, want error")
+	}
+}
+
+// CampaignLifecycles checks that lifecycles follow the active campaigns and resolve their outcomes.
+func CampaignLifecycles(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	store := backend.New(t)
+	mergeSnapshots(t, store, []int{0, 5, 10}, []string{"Automatons", "Automatons", "Automatons"})
+
+	merge := func(seen int, owners map[int32]string, active ...int32) {
+		t.Helper()
+		lifecycles := &db.CampaignLifecycles{WarID: WarID, Seen: db.PGTimestamp(At(seen)), CampaignIDs: active, PlanetOwners: owners}
+		if err := store.Merge(ctx, stats.NewCollector(), []db.Entity{lifecycles}); err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+	}
+	get := func() gen.GetCampaignLifecyclesRow {
+		t.Helper()
+		lifecycles, err := store.CampaignLifecycles(ctx, WarID)
+		if err != nil {
+			t.Fatalf("CampaignLifecycles() error = %v", err)
+		}
+		if len(lifecycles) != 1 {
+			t.Fatalf("CampaignLifecycles() returned %d lifecycles, want 1", len(lifecycles))
+		}
+		return lifecycles[0]
+	}
+
+	merge(0, nil, 5)
+	merge(5, nil, 5)
+
+	lifecycle := get()
+	if !lifecycle.FirstSeen.Time.Equal(At(0)) || !lifecycle.LastSeen.Time.Equal(At(5)) {
+		t.Errorf("lifecycle seen %v - %v, want %v - %v", lifecycle.FirstSeen.Time, lifecycle.LastSeen.Time, At(0), At(5))
+	}
+	if lifecycle.PlanetID == nil || *lifecycle.PlanetID != PlanetID {
+		t.Errorf("lifecycle PlanetID = %v, want %d", lifecycle.PlanetID, PlanetID)
+	}
+	if lifecycle.Ended || lifecycle.Outcome != nil {
+		t.Errorf("lifecycle ended = %v with outcome %v, want active campaign", lifecycle.Ended, lifecycle.Outcome)
+	}
+
+	// campaign is gone, but its planet was not captured, so the outcome is unknown
+	merge(10, nil)
+	lifecycle = get()
+	if !lifecycle.Ended {
+		t.Error("lifecycle not ended after campaign disappeared")
+	}
+	if !lifecycle.LastSeen.Time.Equal(At(5)) {
+		t.Errorf("lifecycle LastSeen = %v, want %v", lifecycle.LastSeen.Time, At(5))
+	}
+	if lifecycle.Outcome != nil {
+		t.Errorf("lifecycle Outcome = %v, want nil while planet was not captured", *lifecycle.Outcome)
+	}
+
+	// the planet captured after the campaign ended resolves the outcome
+	merge(15, map[int32]string{PlanetID: "Automatons", PlanetID + 1: "Humans"})
+	lifecycle = get()
+	if lifecycle.Outcome == nil || *lifecycle.Outcome != "lost" {
+		t.Errorf("lifecycle Outcome = %v, want lost", lifecycle.Outcome)
+	}
+}
+
+// WarClosure checks the summary and final planets of a closed war.
+func WarClosure(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	store := backend.New(t)
+
+	if _, ok, err := store.LatestWarID(ctx); err != nil || ok {
+		t.Fatalf("LatestWarID() without snapshots = ok %v, error %v, want false, nil", ok, err)
+	}
+	if _, ok, err := store.WarSummary(ctx, WarID); err != nil || ok {
+		t.Fatalf("WarSummary() of open war = ok %v, error %v, want false, nil", ok, err)
+	}
+
+	// player counts are 1000, 2000 and 3000
+	mergeSnapshots(t, store, []int{0, 5, 10}, []string{"Automatons", "Automatons", "Humans"})
+
+	warID, ok, err := store.LatestWarID(ctx)
+	if err != nil || !ok || warID != WarID {
+		t.Fatalf("LatestWarID() = %d, %v, %v, want %d, true, nil", warID, ok, err, WarID)
+	}
+
+	for i, want := range []wantStats{{inserted: 1}, {noop: 1}} {
+		// closing again is a no-op
+		collector := stats.NewCollector()
+		if err = store.Merge(ctx, collector, []db.Entity{Closure()}); err != nil {
+			t.Fatalf("Merge() #%d error = %v", i, err)
+		}
+		for _, table := range []gen.Table{gen.TableWarSummaries, gen.TableWarFinalPlanets} {
+			got := collector[table]
+			if got.Inserted != want.inserted || got.Noop != want.noop {
+				t.Errorf("Merge() #%d %s: inserted/noop = %d/%d, want %d/%d", i, table, got.Inserted, got.Noop, want.inserted, want.noop)
+			}
+		}
+	}
+
+	summary, ok, err := store.WarSummary(ctx, WarID)
+	if err != nil || !ok {
+		t.Fatalf("WarSummary() = ok %v, error %v, want true, nil", ok, err)
+	}
+	if summary.SnapshotCount != 3 {
+		t.Errorf("SnapshotCount = %d, want 3", summary.SnapshotCount)
+	}
+	if !summary.FirstSnapshotTime.Time.Equal(At(0)) || !summary.FinalSnapshotTime.Time.Equal(At(10)) {
+		t.Errorf("snapshot times = %v - %v, want %v - %v", summary.FirstSnapshotTime.Time, summary.FinalSnapshotTime.Time, At(0), At(10))
+	}
+	if players, _ := summary.PeakPlayerCount.Int64Value(); players.Int64 != 3000 {
+		t.Errorf("PeakPlayerCount = %d, want 3000", players.Int64)
+	}
+
+	planets, err := store.WarFinalPlanets(ctx, WarID)
+	if err != nil {
+		t.Fatalf("WarFinalPlanets() error = %v", err)
+	}
+	if len(planets) != 1 || planets[0].PlanetID != PlanetID || planets[0].Owner != "Humans" {
+		t.Errorf("WarFinalPlanets() = %+v, want planet %d owned by Humans", planets, PlanetID)
+	}
+
+	if _, ok, err = store.WarSummary(ctx, WarID+1); err != nil || ok {
+		t.Errorf("WarSummary() of unknown war = ok %v, error %v, want false, nil", ok, err)
+	}
+}
+
+// SyncRuns checks that runs are recorded once with their endpoints and that failed runs state their error.
+func SyncRuns(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	store := backend.New(t)
+
+	msg := "merging failed"
+	runs := []*db.SyncRun{
+		{
+			ID:        pgtype.UUID{Bytes: [16]byte{1, 2, 3}, Valid: true},
+			Job:       "snapshot",
+			StartTime: db.PGTimestamp(At(0)),
+			EndTime:   db.PGTimestamp(At(1)),
+			Outcome:   db.SyncOutcomeSuccess,
+			Endpoints: []db.SyncRunEndpoint{
+				{Endpoint: "war", Status: "ok", LatencyMS: 120, Upstream: "https://api.example.com"},
+				{Endpoint: "dispatches", Status: "failed", LatencyMS: 10000, Error: "timeout"},
+			},
+			MergeCounts:        map[string]stats.TableCounts{"Snapshots": {Inserted: 1}},
+			SnapshotCreateTime: db.PGTimestamp(At(0)),
+		},
+		{
+			ID:        pgtype.UUID{Bytes: [16]byte{4, 5, 6}, Valid: true},
+			Job:       "snapshot",
+			StartTime: db.PGTimestamp(At(2)),
+			EndTime:   db.PGTimestamp(At(3)),
+			Outcome:   db.SyncOutcomeFailure,
+			Endpoints: []db.SyncRunEndpoint{{Endpoint: "war", Status: "ok", LatencyMS: 120}},
+			Error:     &msg,
+		},
+	}
+	for _, run := range runs {
+		// runs are never updated
+		for i, want := range []stats.TableCounts{{Inserted: 1}, {Noop: 1}} {
+			collector := stats.NewCollector()
+			if err := store.Merge(ctx, collector, []db.Entity{run}); err != nil {
+				t.Fatalf("Merge() of %s run #%d error = %v", run.Outcome, i, err)
+			}
+			if got := collector.Counts()[gen.TableSyncRuns.String()]; got != want {
+				t.Errorf("Merge() of %s run #%d stats = %+v, want %+v", run.Outcome, i, got, want)
+			}
+		}
+
+		stored, err := store.SyncRun(ctx, run.ID)
+		if err != nil {
+			t.Fatalf("SyncRun() error = %v", err)
+		}
+		if stored.Outcome != run.Outcome || !reflect.DeepEqual(stored.Error, run.Error) ||
+			stored.SnapshotCreateTime.Valid != run.SnapshotCreateTime.Valid || !stored.SnapshotCreateTime.Time.Equal(run.SnapshotCreateTime.Time) {
+			t.Errorf("SyncRun() = %+v, want %+v", stored, run)
+		}
+		var endpoints []db.SyncRunEndpoint
+		if err = json.Unmarshal(stored.Endpoints, &endpoints); err != nil || !reflect.DeepEqual(endpoints, run.Endpoints) {
+			t.Errorf("SyncRun().Endpoints = %s, want %+v", stored.Endpoints, run.Endpoints)
+		}
+	}
+
+	// a failed run has to state why
+	invalid := &db.SyncRun{
+		ID:        pgtype.UUID{Bytes: [16]byte{7, 8, 9}, Valid: true},
+		Job:       "snapshot",
+		StartTime: db.PGTimestamp(At(4)),
+		EndTime:   db.PGTimestamp(At(4)),
+		Outcome:   db.SyncOutcomeFailure,
+	}
+	if err := store.Merge(ctx, stats.NewCollector(), []db.Entity{invalid}); err == nil {
+		t.Error("Merge() of failed run without error = nil, want error")
+	}
+}
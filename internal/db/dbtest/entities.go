@@ -0,0 +1,267 @@
+package dbtest
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+)
+
+// schemaCase applies a change to a valid entity, which the schema has to reject if `wantErr` is set.
+type schemaCase[T db.Entity] struct {
+	name    string
+	modify  func(T)
+	wantErr bool
+}
+
+// entitySuite merges modified copies of a valid entity into a new database each.
+type entitySuite[T db.Entity] struct {
+	// table is the table valid entities are reported as inserted into
+	table gen.Table
+	// prerequisites returns the entities referenced by the entity
+	prerequisites func() []db.Entity
+	valid         func() T
+	// check optionally verifies a valid entity after it has been merged
+	check func(t *testing.T, store Store, entity T)
+}
+
+func (s entitySuite[T]) run(t *testing.T, backend Backend, cases []schemaCase[T]) {
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := backend.New(t)
+			if s.prerequisites != nil {
+				if err := store.Merge(ctx, stats.NewCollector(), s.prerequisites()); err != nil {
+					t.Fatalf("failed to merge prerequisites: %v", err)
+				}
+			}
+
+			entity := s.valid()
+			tt.modify(entity)
+			collector := stats.NewCollector()
+			err := store.Merge(ctx, collector, []db.Entity{entity})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Merge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				// any subsequent checks don't make sense if error encountered
+				return
+			}
+			if inserted := collector[s.table].Inserted; inserted != 1 {
+				t.Errorf("%s: inserted = %d, want 1", s.table, inserted)
+			}
+			if s.check != nil {
+				s.check(t, store, entity)
+			}
+		})
+	}
+}
+
+func warOnly() []db.Entity {
+	return []db.Entity{War()}
+}
+
+// Wars checks the constraints of wars.
+func Wars(t *testing.T, backend Backend) {
+	entitySuite[*db.War]{table: gen.TableWars, valid: War}.run(t, backend, []schemaCase[*db.War]{
+		{name: "valid", modify: func(*db.War) {}},
+		{
+			name: "start time after end time",
+			modify: func(w *db.War) {
+				w.StartTime, w.EndTime = w.EndTime, w.StartTime
+			},
+			wantErr: true,
+		},
+		{name: "empty faction list", modify: func(w *db.War) { w.Factions = nil }, wantErr: true},
+	})
+}
+
+// Planets checks the constraints of planets, their biomes and hazards.
+func Planets(t *testing.T, backend Backend) {
+	entitySuite[*db.Planet]{table: gen.TablePlanets, prerequisites: warOnly, valid: Planet}.run(t, backend, []schemaCase[*db.Planet]{
+		{name: "valid", modify: func(*db.Planet) {}},
+		{name: "empty required sector", modify: func(p *db.Planet) { p.Sector = "" }, wantErr: true},
+		{name: "negative max health", modify: func(p *db.Planet) { p.MaxHealth = -1 }, wantErr: true},
+		{name: "high max health", modify: func(p *db.Planet) { p.MaxHealth = math.MaxInt64 }},
+		{name: "position Y coordinate missing", modify: func(p *db.Planet) { p.Position = []float64{5} }, wantErr: true},
+		{name: "position has too many coordinates", modify: func(p *db.Planet) { p.Position = []float64{3, 4, 5} }, wantErr: true},
+		// the biome and hazard names of the planet are taken from its biome and hazards
+		{name: "empty foreign key biome name", modify: func(p *db.Planet) { p.BiomeName = "" }},
+		{name: "biome foreign key different", modify: func(p *db.Planet) { p.Biome.Name = "a different biome" }},
+		{name: "no hazards", modify: func(p *db.Planet) { p.Hazards = []gen.Hazard{} }},
+		{name: "biome name empty", modify: func(p *db.Planet) { p.Biome.Name = "" }, wantErr: true},
+		{name: "biome description empty", modify: func(p *db.Planet) { p.Biome.Description = "" }, wantErr: true},
+		{name: "hazard name empty", modify: func(p *db.Planet) { p.Hazards[0].Name = "" }, wantErr: true},
+		{name: "hazard description empty", modify: func(p *db.Planet) { p.Hazards[0].Description = "" }, wantErr: true},
+		{name: "war FK violation", modify: func(p *db.Planet) { p.WarID++ }, wantErr: true},
+	})
+}
+
+// Campaigns checks the constraints of campaigns, that they are scoped by war and keep their planet.
+func Campaigns(t *testing.T, backend Backend) {
+	prerequisites := func() []db.Entity {
+		return []db.Entity{War(), Planet()}
+	}
+	entitySuite[*db.Campaign]{table: gen.TableCampaigns, prerequisites: prerequisites, valid: Campaign}.run(t, backend, []schemaCase[*db.Campaign]{
+		{name: "valid", modify: func(*db.Campaign) {}},
+		{name: "without planet", modify: func(c *db.Campaign) { c.PlanetID = nil }},
+		{
+			name: "negative count",
+			modify: func(c *db.Campaign) {
+				c.Count = pgtype.Numeric{Int: big.NewInt(-1), Valid: true}
+			},
+			wantErr: true,
+		},
+		{
+			name: "planet FK violation",
+			modify: func(c *db.Campaign) {
+				planetID := int32(12345)
+				c.PlanetID = &planetID
+			},
+			wantErr: true,
+		},
+		{name: "war FK violation", modify: func(c *db.Campaign) { c.WarID++ }, wantErr: true},
+	})
+
+	t.Run("scoped by war", func(t *testing.T) {
+		ctx := context.Background()
+		store := backend.New(t)
+
+		// the same campaign ID may occur in multiple wars
+		otherWar, otherCampaign := War(), Campaign()
+		otherWar.ID++
+		otherCampaign.WarID, otherCampaign.PlanetID = otherWar.ID, nil
+		collector := stats.NewCollector()
+		if err := store.Merge(ctx, collector, []db.Entity{War(), Planet(), Campaign(), otherWar, otherCampaign}); err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		if inserted := collector[gen.TableCampaigns].Inserted; inserted != 2 {
+			t.Errorf("%s: inserted = %d, want 2", gen.TableCampaigns, inserted)
+		}
+	})
+
+	t.Run("planet kept", func(t *testing.T) {
+		ctx := context.Background()
+		store := backend.New(t)
+
+		// a missing planet does not overwrite the known one
+		withoutPlanet := Campaign()
+		withoutPlanet.PlanetID = nil
+		collector := stats.NewCollector()
+		if err := store.Merge(ctx, collector, Entities(false), []db.Entity{withoutPlanet, Lifecycles(At(0), true, "Automatons")}); err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		if noop := collector[gen.TableCampaigns].Noop; noop != 1 {
+			t.Errorf("%s: noop = %d, want 1", gen.TableCampaigns, noop)
+		}
+
+		// lifecycles are joined with the planet of their campaign
+		lifecycles, err := store.CampaignLifecycles(ctx, WarID)
+		if err != nil {
+			t.Fatalf("CampaignLifecycles() error = %v", err)
+		}
+		if len(lifecycles) != 1 || lifecycles[0].PlanetID == nil || *lifecycles[0].PlanetID != PlanetID {
+			t.Errorf("CampaignLifecycles() = %+v, want campaign on planet %d", lifecycles, PlanetID)
+		}
+	})
+}
+
+// Events checks the constraints of events.
+func Events(t *testing.T, backend Backend) {
+	prerequisites := func() []db.Entity {
+		return []db.Entity{War(), Planet(), Campaign()}
+	}
+	entitySuite[*db.Event]{table: gen.TableEvents, prerequisites: prerequisites, valid: Event}.run(t, backend, []schemaCase[*db.Event]{
+		{name: "valid", modify: func(*db.Event) {}},
+		{name: "max health high number", modify: func(e *db.Event) { e.MaxHealth = math.MaxInt64 }},
+		{name: "negative max health", modify: func(e *db.Event) { e.MaxHealth = -1 }, wantErr: true},
+		{name: "empty faction", modify: func(e *db.Event) { e.Faction = "" }, wantErr: true},
+		{
+			name: "start time after end time",
+			modify: func(e *db.Event) {
+				e.StartTime, e.EndTime = e.EndTime, e.StartTime
+			},
+			wantErr: true,
+		},
+		{name: "campaign FK violation", modify: func(e *db.Event) { e.CampaignID++ }, wantErr: true},
+	})
+}
+
+// Assignments checks the constraints of assignments and their tasks.
+func Assignments(t *testing.T, backend Backend) {
+	entitySuite[*db.Assignment]{table: gen.TableAssignments, prerequisites: warOnly, valid: Assignment}.run(t, backend, []schemaCase[*db.Assignment]{
+		{name: "valid", modify: func(*db.Assignment) {}},
+		{name: "empty required title", modify: func(a *db.Assignment) { a.Title = "" }, wantErr: true},
+		{name: "empty required expiration time", modify: func(a *db.Assignment) { a.Expiration = pgtype.Timestamp{} }, wantErr: true},
+		{
+			name: "mismatched assignment task array lengths",
+			modify: func(a *db.Assignment) {
+				a.Tasks[0].ValueTypes = a.Tasks[0].ValueTypes[:2]
+			},
+			wantErr: true,
+		},
+		{name: "war FK violation", modify: func(a *db.Assignment) { a.WarID++ }, wantErr: true},
+	})
+}
+
+// Dispatches checks the constraints of dispatches.
+func Dispatches(t *testing.T, backend Backend) {
+	entitySuite[*db.Dispatch]{table: gen.TableDispatches, valid: Dispatch}.run(t, backend, []schemaCase[*db.Dispatch]{
+		{name: "valid", modify: func(*db.Dispatch) {}},
+		{name: "empty message", modify: func(d *db.Dispatch) { d.Message = "" }, wantErr: true},
+	})
+}
+
+// Snapshots checks the constraints of snapshots and that the latest one is found.
+func Snapshots(t *testing.T, backend Backend) {
+	suite := entitySuite[*db.Snapshot]{
+		table:         gen.TableSnapshots,
+		prerequisites: func() []db.Entity { return Entities(false) },
+		valid: func() *db.Snapshot {
+			return Snapshot(At(0), "Automatons", 1000)
+		},
+		check: func(t *testing.T, store Store, snapshot *db.Snapshot) {
+			latest, ok, err := store.LatestSnapshotTime(context.Background())
+			if err != nil || !ok || !latest.Equal(snapshot.CreateTime.Time) {
+				t.Errorf("LatestSnapshotTime() = %v, %v, %v, want %v, true, nil", latest, ok, err, snapshot.CreateTime.Time)
+			}
+		},
+	}
+	cases := []schemaCase[*db.Snapshot]{
+		{name: "valid", modify: func(*db.Snapshot) {}},
+		{name: "campaign FK violation", modify: func(s *db.Snapshot) { s.CampaignIds = []int32{999} }, wantErr: true},
+		{name: "dispatch FK violation", modify: func(s *db.Snapshot) { s.DispatchIds = append(s.DispatchIds, 999) }, wantErr: true},
+		{name: "negative war impact multiplier", modify: func(s *db.Snapshot) { s.WarSnapshot.ImpactMultiplier *= -1 }, wantErr: true},
+		{name: "assignment FK violation", modify: func(s *db.Snapshot) { s.AssignmentSnapshots[0].AssignmentID++ }, wantErr: true},
+		{name: "planet FK violation", modify: func(s *db.Snapshot) { s.PlanetSnapshots[0].PlanetID++ }, wantErr: true},
+		{name: "negative planet health", modify: func(s *db.Snapshot) { s.PlanetSnapshots[0].Health *= -1 }, wantErr: true},
+		{name: "empty planet owner", modify: func(s *db.Snapshot) { s.PlanetSnapshots[0].CurrentOwner = "" }, wantErr: true},
+		{name: "nil planet attack IDs", modify: func(s *db.Snapshot) { s.PlanetSnapshots[0].AttackingPlanetIds = nil }, wantErr: true},
+		{name: "event FK violation", modify: func(s *db.Snapshot) { s.PlanetSnapshots[0].Event.EventID++ }, wantErr: true},
+		{name: "event negative health", modify: func(s *db.Snapshot) { s.PlanetSnapshots[0].Event.Health *= -1 }, wantErr: true},
+		{name: "max float64", modify: func(s *db.Snapshot) { s.PlanetSnapshots[0].RegenPerSecond = math.MaxFloat64 }},
+		{
+			name: "statistics max count",
+			modify: func(s *db.Snapshot) {
+				s.Statistics.AutomatonKills = db.PGUint64(backend.MaxCount)
+				s.PlanetSnapshots[0].Statistics.BulletsFired = db.PGUint64(backend.MaxCount)
+			},
+		},
+	}
+	if backend.MaxCount < math.MaxUint64 {
+		cases = append(cases, schemaCase[*db.Snapshot]{
+			name: "statistics above max count",
+			modify: func(s *db.Snapshot) {
+				s.Statistics.AutomatonKills = db.PGUint64(backend.MaxCount + 1)
+			},
+			wantErr: true,
+		})
+	}
+	suite.run(t, backend, cases)
+}
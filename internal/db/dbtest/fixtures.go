@@ -0,0 +1,209 @@
+package dbtest
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+)
+
+// WarID is the war all fixtures belong to.
+const WarID = 999
+
+// PlanetID is the planet all fixtures refer to.
+const PlanetID = 456
+
+var base = time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC)
+
+// At returns the time `minutes` after the first snapshot of the fixtures.
+func At(minutes int) time.Time {
+	return base.Add(time.Duration(minutes) * time.Minute)
+}
+
+// War returns the war all fixtures belong to.
+func War() *db.War {
+	return &db.War{
+		ID:        WarID,
+		StartTime: db.PGTimestamp(time.Date(2024, 1, 1, 1, 1, 1, 1, time.UTC)),
+		EndTime:   db.PGTimestamp(time.Date(2025, 1, 1, 1, 1, 1, 1, time.UTC)),
+		Factions:  []string{"Humans", "Automatons"},
+	}
+}
+
+// Planet returns the planet all fixtures refer to, with a biome and a hazard.
+func Planet() *db.Planet {
+	return &db.Planet{
+		Planet: gen.Planet{
+			ID:           PlanetID,
+			Name:         "Foo",
+			Sector:       "Bar",
+			Position:     []float64{1, 2},
+			WaypointIds:  []int32{1, 2, 3},
+			MaxHealth:    1000,
+			InitialOwner: "Super Humans",
+			WarID:        WarID,
+		},
+		Biome:   gen.Biome{Name: "FooBiome", Description: "This biome contains a lot of spaghetti"},
+		Hazards: []gen.Hazard{{Name: "BarHazard", Description: "This hazard contains a lot of bugs"}},
+	}
+}
+
+// Campaign returns campaign 5 on the planet.
+func Campaign() *db.Campaign {
+	planetID := int32(PlanetID)
+	return &db.Campaign{ID: 5, Type: 8, Count: db.PGUint64(100), WarID: WarID, PlanetID: &planetID}
+}
+
+// Event returns event 555 of the campaign.
+func Event() *db.Event {
+	return &db.Event{
+		ID:         555,
+		CampaignID: 5,
+		Type:       7,
+		Faction:    "Automatons",
+		MaxHealth:  55667788,
+		StartTime:  db.PGTimestamp(time.Date(2024, 1, 1, 1, 1, 1, 1, time.UTC)),
+		EndTime:    db.PGTimestamp(time.Date(2025, 1, 1, 1, 1, 1, 1, time.UTC)),
+		WarID:      WarID,
+	}
+}
+
+// Assignment returns assignment 3 with a single task.
+func Assignment() *db.Assignment {
+	return &db.Assignment{
+		Assignment: gen.Assignment{
+			ID:           3,
+			Title:        "Footitle",
+			Briefing:     "Foobriefing",
+			Description:  "Bardescription",
+			Expiration:   db.PGTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)),
+			RewardType:   8,
+			RewardAmount: db.PGUint64(100),
+			WarID:        WarID,
+		},
+		Tasks: []gen.AssignmentTask{
+			{
+				TaskType:   9,
+				Values:     []pgtype.Numeric{db.PGUint64(7), db.PGUint64(8), db.PGUint64(9)},
+				ValueTypes: []pgtype.Numeric{db.PGUint64(42), db.PGUint64(44), db.PGUint64(46)},
+			},
+		},
+	}
+}
+
+// Dispatch returns dispatch 123.
+func Dispatch() *db.Dispatch {
+	return &db.Dispatch{
+		ID:         123,
+		CreateTime: db.PGTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)),
+		Type:       5,
+		Message:    "A valid dispatch",
+	}
+}
+
+// Entities returns all war-scoped entities of a snapshot, optionally with every entity changed.
+//
+// Planets are merged before campaigns, which reference them.
+func Entities(changed bool) []db.Entity {
+	war, planet, campaign, event, assignment, dispatch := War(), Planet(), Campaign(), Event(), Assignment(), Dispatch()
+	if changed {
+		war.Factions = append(war.Factions, "Terminids")
+		campaign.Count = db.PGUint64(200)
+		event.MaxHealth++
+		planet.Name = "Changed"
+		planet.Biome.Description = "This biome contains less spaghetti"
+		planet.Hazards[0].Description = "This hazard contains fewer bugs"
+		assignment.Title = "Changed"
+		assignment.Tasks[0].Values[0] = db.PGUint64(10)
+		dispatch.Message = "A changed dispatch"
+	}
+	return []db.Entity{war, planet, campaign, event, assignment, dispatch}
+}
+
+func statistics(playerCount uint64) gen.SnapshotStatistic {
+	return gen.SnapshotStatistic{
+		MissionsWon:     db.PGUint64(6444323),
+		MissionsLost:    db.PGUint64(53555),
+		MissionTime:     db.PGUint64(445566),
+		TerminidKills:   db.PGUint64(6666677),
+		AutomatonKills:  db.PGUint64(7565465454),
+		IlluminateKills: db.PGUint64(5345433455),
+		BulletsFired:    db.PGUint64(888999399393222),
+		BulletsHit:      db.PGUint64(49324924499449222),
+		TimePlayed:      db.PGUint64(uint64(1 * 24 * 60 * time.Second)),
+		Deaths:          db.PGUint64(55223535),
+		Revives:         db.PGUint64(44442),
+		Friendlies:      db.PGUint64(2221111),
+		PlayerCount:     db.PGUint64(playerCount),
+	}
+}
+
+// Snapshot returns a snapshot of the entities taken at `createTime` with the planet owned by `owner`.
+func Snapshot(createTime time.Time, owner string, playerCount uint64) *db.Snapshot {
+	upstream := "http://localhost:4000"
+	return &db.Snapshot{
+		Snapshot: gen.Snapshot{
+			CreateTime:  db.PGTimestamp(createTime),
+			CampaignIds: []int32{5},
+			DispatchIds: []int32{123},
+			Upstream:    &upstream,
+		},
+		WarSnapshot: gen.WarSnapshot{WarID: WarID, ImpactMultiplier: 0.005},
+		AssignmentSnapshots: []gen.AssignmentSnapshot{
+			{AssignmentID: 3, Progress: []pgtype.Numeric{db.PGUint64(2), db.PGUint64(3), db.PGUint64(4)}},
+		},
+		PlanetSnapshots: []db.PlanetSnapshot{
+			{
+				PlanetSnapshot: gen.PlanetSnapshot{
+					PlanetID:           PlanetID,
+					Health:             556677,
+					CurrentOwner:       owner,
+					RegenPerSecond:     0.06,
+					AttackingPlanetIds: []int32{PlanetID},
+				},
+				Event:      &gen.EventSnapshot{EventID: 555, Health: 999999},
+				Statistics: statistics(playerCount),
+			},
+		},
+		Statistics: statistics(playerCount),
+	}
+}
+
+// Lifecycles returns the lifecycle of the campaign on the planet at `seen`, with the planet owned by `owner`.
+func Lifecycles(seen time.Time, active bool, owner string) *db.CampaignLifecycles {
+	l := &db.CampaignLifecycles{WarID: WarID, Seen: db.PGTimestamp(seen), PlanetOwners: map[int32]string{PlanetID: owner}}
+	if active {
+		l.CampaignIDs = []int32{5}
+	}
+	return l
+}
+
+// Gaps returns a single gap between the first two snapshots with `missed` runs.
+func Gaps(missed int32) *db.SnapshotGaps {
+	return &db.SnapshotGaps{
+		Since: db.PGTimestamp(At(0)),
+		Gaps: []gen.SnapshotGap{
+			{StartTime: db.PGTimestamp(At(0)), EndTime: db.PGTimestamp(At(10)), MissedRuns: missed},
+		},
+	}
+}
+
+// Closure returns the closure of the war after the last snapshot.
+func Closure() *db.WarClosure {
+	return &db.WarClosure{WarID: WarID, CloseTime: db.PGTimestamp(At(30))}
+}
+
+// SyncRun returns a successful run of the snapshot job which created the first snapshot.
+func SyncRun() *db.SyncRun {
+	return &db.SyncRun{
+		ID:                 pgtype.UUID{Bytes: [16]byte{1, 2, 3}, Valid: true},
+		Job:                "snapshot",
+		StartTime:          db.PGTimestamp(At(0)),
+		EndTime:            db.PGTimestamp(At(1)),
+		Outcome:            db.SyncOutcomeSuccess,
+		Endpoints:          []db.SyncRunEndpoint{{Endpoint: "war", Status: "ok", LatencyMS: 120}},
+		SnapshotCreateTime: db.PGTimestamp(At(0)),
+	}
+}
@@ -0,0 +1,81 @@
+package db
+
+import "context"
+
+// Entity is a typed entity transformed from API data, independent of where it is stored.
+type Entity interface {
+	// Accept calls the method of `v` handling the type of the entity.
+	Accept(ctx context.Context, v EntityVisitor) error
+}
+
+// EntityVisitor handles every type of Entity.
+//
+// Each storage backend implements it, so a new entity type does not compile until all backends support it.
+type EntityVisitor interface {
+	VisitWar(ctx context.Context, w *War) error
+	VisitCampaign(ctx context.Context, c *Campaign) error
+	VisitEvent(ctx context.Context, e *Event) error
+	VisitPlanet(ctx context.Context, p *Planet) error
+	VisitAssignment(ctx context.Context, a *Assignment) error
+	VisitDispatch(ctx context.Context, d *Dispatch) error
+	VisitSnapshot(ctx context.Context, s *Snapshot) error
+	VisitSnapshotGaps(ctx context.Context, g *SnapshotGaps) error
+	VisitCampaignLifecycles(ctx context.Context, l *CampaignLifecycles) error
+	VisitWarClosure(ctx context.Context, w *WarClosure) error
+	VisitSyncRun(ctx context.Context, r *SyncRun) error
+}
+
+// Accept implements Entity.
+func (w *War) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitWar(ctx, w)
+}
+
+// Accept implements Entity.
+func (c *Campaign) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitCampaign(ctx, c)
+}
+
+// Accept implements Entity.
+func (e *Event) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitEvent(ctx, e)
+}
+
+// Accept implements Entity.
+func (p *Planet) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitPlanet(ctx, p)
+}
+
+// Accept implements Entity.
+func (a *Assignment) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitAssignment(ctx, a)
+}
+
+// Accept implements Entity.
+func (d *Dispatch) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitDispatch(ctx, d)
+}
+
+// Accept implements Entity.
+func (s *Snapshot) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitSnapshot(ctx, s)
+}
+
+// Accept implements Entity.
+func (g *SnapshotGaps) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitSnapshotGaps(ctx, g)
+}
+
+// Accept implements Entity.
+func (l *CampaignLifecycles) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitCampaignLifecycles(ctx, l)
+}
+
+// Accept implements Entity.
+func (w *WarClosure) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitWarClosure(ctx, w)
+}
+
+// Accept implements Entity.
+func (r *SyncRun) Accept(ctx context.Context, v EntityVisitor) error {
+	return v.VisitSyncRun(ctx, r)
+}
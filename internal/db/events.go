@@ -15,15 +15,8 @@ type Event gen.Event
 
 // Merge implements EntityMerger.
 func (e *Event) Merge(ctx context.Context, tx *gen.Queries, onMerge onMergeFunc) error {
-	exists, err := tx.DispatchExists(ctx, e.ID)
-	if err != nil {
-		return fmt.Errorf("failed to check if event ID=%d exists: %v", e.ID, err)
-	}
-
-	rows, err := tx.MergeEvent(ctx, gen.MergeEventParams(*e))
-	if err != nil {
+	if err := upsert(ctx, gen.TableEvents, tx.MergeEvent, gen.MergeEventParams(*e), onMerge); err != nil {
 		return fmt.Errorf("failed to merge event (ID=%d): %v", e.ID, err)
 	}
-	onMerge(gen.TableEvents, exists, rows)
 	return nil
 }
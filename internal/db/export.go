@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+)
+
+// ExportPlanetSnapshots returns the planet snapshots of all snapshots taken after `after` until `until`,
+// joined with their planet, event and statistics.
+func (c *Client) ExportPlanetSnapshots(ctx context.Context, after, until time.Time) ([]gen.ExportPlanetSnapshotsRow, error) {
+	rows, err := c.queries.ExportPlanetSnapshots(ctx, gen.ExportPlanetSnapshotsParams{After: PGTimestamp(after), Until: PGTimestamp(until)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query planet snapshots: %v", err)
+	}
+	return rows, nil
+}
+
+// ExportWarStats returns the war snapshots of all snapshots taken after `after` until `until`, joined with the global statistics.
+func (c *Client) ExportWarStats(ctx context.Context, after, until time.Time) ([]gen.ExportWarStatsRow, error) {
+	rows, err := c.queries.ExportWarStats(ctx, gen.ExportWarStatsParams{After: PGTimestamp(after), Until: PGTimestamp(until)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query war stats: %v", err)
+	}
+	return rows, nil
+}
+
+// ExportAssignmentProgress returns the progress of each assignment task in all snapshots taken after `after` until `until`.
+func (c *Client) ExportAssignmentProgress(ctx context.Context, after, until time.Time) ([]gen.ExportAssignmentProgressRow, error) {
+	rows, err := c.queries.ExportAssignmentProgress(ctx, gen.ExportAssignmentProgressParams{After: PGTimestamp(after), Until: PGTimestamp(until)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assignment progress: %v", err)
+	}
+	return rows, nil
+}
+
+// ExportEvents returns the event snapshots of all snapshots taken after `after` until `until`, joined with their event.
+func (c *Client) ExportEvents(ctx context.Context, after, until time.Time) ([]gen.ExportEventsRow, error) {
+	rows, err := c.queries.ExportEvents(ctx, gen.ExportEventsParams{After: PGTimestamp(after), Until: PGTimestamp(until)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %v", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,140 @@
+//go:build integration
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestExportQueries(t *testing.T) {
+	base := time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return base.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	withClientMigrated(t, func(client *Client) {
+		ctx := context.Background()
+		mergeSnapshotsAt(t, client, at(0), at(5), at(10))
+
+		// the lower bound is exclusive, the upper bound inclusive
+		planets, err := client.ExportPlanetSnapshots(ctx, at(0), at(10))
+		if err != nil {
+			t.Fatalf("ExportPlanetSnapshots() error = %v", err)
+		}
+		if len(planets) != 2 || !planets[0].CreateTime.Time.Equal(at(5)) || !planets[1].CreateTime.Time.Equal(at(10)) {
+			t.Fatalf("ExportPlanetSnapshots() = %+v, want snapshots at %v and %v", planets, at(5), at(10))
+		}
+		planet := planets[0]
+		if planet.PlanetName != "Foo" || planet.Sector != "Bar" || planet.MaxHealth != 1000 {
+			t.Errorf("ExportPlanetSnapshots() planet = %+v, want planet joined", planet)
+		}
+		if planet.EventID == nil || *planet.EventID != 555 || planet.EventHealth == nil || *planet.EventHealth != 999999 {
+			t.Errorf("ExportPlanetSnapshots() event = %v/%v, want event 555 joined", planet.EventID, planet.EventHealth)
+		}
+		if planet.AutomatonKills != 7565465454 || planet.PlayerCount != 12345678 {
+			t.Errorf("ExportPlanetSnapshots() statistics = %+v, want planet statistics joined", planet)
+		}
+
+		wars, err := client.ExportWarStats(ctx, at(0), at(10))
+		if err != nil {
+			t.Fatalf("ExportWarStats() error = %v", err)
+		}
+		if len(wars) != 2 || wars[0].WarID != 999 || wars[0].ImpactMultiplier != 0.005 || wars[0].PlayerCount != 44899 {
+			t.Errorf("ExportWarStats() = %+v, want war 999 with global statistics", wars)
+		}
+
+		progress, err := client.ExportAssignmentProgress(ctx, at(0), at(5))
+		if err != nil {
+			t.Fatalf("ExportAssignmentProgress() error = %v", err)
+		}
+		// one row per progress value, only the first one has a task
+		if len(progress) != 3 {
+			t.Fatalf("ExportAssignmentProgress() = %+v, want 3 rows", progress)
+		}
+		for i, row := range progress {
+			if row.AssignmentID != 3 || row.Title != "Footitle" || row.TaskIndex != int32(i) || row.Progress != int64(i+2) {
+				t.Errorf("ExportAssignmentProgress()[%d] = %+v, want progress %d of assignment 3", i, row, i+2)
+			}
+		}
+		if progress[0].TaskType == nil || *progress[0].TaskType != 9 || progress[1].TaskType != nil {
+			t.Errorf("ExportAssignmentProgress() task types = %v, %v, want 9, nil", progress[0].TaskType, progress[1].TaskType)
+		}
+
+		events, err := client.ExportEvents(ctx, at(0), at(5))
+		if err != nil {
+			t.Fatalf("ExportEvents() error = %v", err)
+		}
+		if len(events) != 1 || events[0].EventID != 555 || events[0].PlanetID != 456 || events[0].Faction != "Automatons" || events[0].Health != 999999 {
+			t.Errorf("ExportEvents() = %+v, want event 555 on planet 456", events)
+		}
+	})
+}
+
+func TestForEachPlanetSnapshot(t *testing.T) {
+	base := time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return base.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	withClientMigrated(t, func(client *Client) {
+		ctx := context.Background()
+		mergeSnapshotsAt(t, client, at(0), at(5), at(10))
+
+		collect := func(params PlanetSnapshotParams) []PlanetSnapshotRow {
+			t.Helper()
+			var rows []PlanetSnapshotRow
+			if err := client.ForEachPlanetSnapshot(ctx, params, func(row PlanetSnapshotRow) error {
+				rows = append(rows, row)
+				return nil
+			}); err != nil {
+				t.Fatalf("ForEachPlanetSnapshot() error = %v", err)
+			}
+			return rows
+		}
+
+		rows := collect(PlanetSnapshotParams{})
+		if len(rows) != 3 {
+			t.Fatalf("ForEachPlanetSnapshot() without filter returned %d rows, want 3", len(rows))
+		}
+		var attacking []struct {
+			ID   int32
+			Name string
+		}
+		if err := json.Unmarshal(rows[0].AttackingPlanets, &attacking); err != nil {
+			t.Fatalf("invalid attacking planets %s: %v", rows[0].AttackingPlanets, err)
+		}
+		if len(attacking) != 1 || attacking[0].ID != 456 || attacking[0].Name != "Foo" {
+			t.Errorf("attacking planets = %s, want planet 456 resolved", rows[0].AttackingPlanets)
+		}
+		if rows[0].EventFaction == nil || *rows[0].EventFaction != "Automatons" {
+			t.Errorf("event faction = %v, want event joined", rows[0].EventFaction)
+		}
+
+		// the lower bound is inclusive, the upper bound exclusive
+		rows = collect(PlanetSnapshotParams{Since: PGTimestamp(at(5)), Until: PGTimestamp(at(10))})
+		if len(rows) != 1 || !rows[0].CreateTime.Time.Equal(at(5)) {
+			t.Errorf("ForEachPlanetSnapshot() in time range = %+v, want snapshot at %v", rows, at(5))
+		}
+
+		filters := []struct {
+			name   string
+			params PlanetSnapshotParams
+			want   int
+		}{
+			{name: "planet", params: PlanetSnapshotParams{PlanetID: ptr[int32](456)}, want: 3},
+			{name: "other planet", params: PlanetSnapshotParams{PlanetID: ptr[int32](1)}, want: 0},
+			{name: "sector", params: PlanetSnapshotParams{Sector: ptr("Bar")}, want: 3},
+			{name: "other sector", params: PlanetSnapshotParams{Sector: ptr("Baz")}, want: 0},
+			{name: "faction", params: PlanetSnapshotParams{Faction: ptr("Automatons")}, want: 3},
+			{name: "other faction", params: PlanetSnapshotParams{Faction: ptr("Humans")}, want: 0},
+		}
+		for _, tt := range filters {
+			if got := collect(tt.params); len(got) != tt.want {
+				t.Errorf("ForEachPlanetSnapshot() filtered by %s returned %d rows, want %d", tt.name, len(got), tt.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,212 @@
+//go:build integration
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stnokott/helldivers-client/internal/copytest"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+)
+
+// The fixtures below are used by the tests of PostgreSQL-only features.
+// The tests shared by all backends are in package dbtest.
+
+var validWar = War{
+	ID:        999,
+	StartTime: PGTimestamp(time.Date(2024, 1, 1, 1, 1, 1, 1, time.UTC)),
+	EndTime:   PGTimestamp(time.Date(2025, 1, 1, 1, 1, 1, 1, time.UTC)),
+	Factions:  []string{"Humans", "Automatons"},
+}
+
+var validAssignment = Assignment{
+	Assignment: gen.Assignment{
+		ID:           3,
+		Title:        "Footitle",
+		Briefing:     "Foobriefing",
+		Description:  "Bardescription",
+		Expiration:   PGTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)),
+		RewardType:   8,
+		RewardAmount: PGUint64(100),
+		WarID:        999,
+	},
+	Tasks: []gen.AssignmentTask{
+		{
+			TaskType:   9,
+			Values:     []pgtype.Numeric{PGUint64(7), PGUint64(8), PGUint64(9)},
+			ValueTypes: []pgtype.Numeric{PGUint64(42), PGUint64(44), PGUint64(46)},
+		},
+	},
+}
+
+var validCampaign = Campaign{
+	ID:    5,
+	Type:  8,
+	Count: PGUint64(100),
+	WarID: 999,
+}
+
+var validEvent = Event{
+	ID:         555,
+	CampaignID: 5,
+	Type:       7,
+	Faction:    "Automatons",
+	MaxHealth:  55667788,
+	StartTime:  PGTimestamp(time.Date(2024, 1, 1, 1, 1, 1, 1, time.UTC)),
+	EndTime:    PGTimestamp(time.Date(2025, 1, 1, 1, 1, 1, 1, time.UTC)),
+	WarID:      999,
+}
+
+var validPlanet = Planet{
+	Planet: gen.Planet{
+		ID:           456,
+		Name:         "Foo",
+		Sector:       "Bar",
+		Position:     []float64{1, 2},
+		WaypointIds:  []int32{1, 2, 3},
+		Disabled:     false,
+		BiomeName:    "FooBiome",
+		HazardNames:  []string{"BarHazard"},
+		MaxHealth:    1000,
+		InitialOwner: "Super Humans",
+		WarID:        999,
+	},
+	Biome: gen.Biome{
+		Name:        "FooBiome",
+		Description: "This biome contains a lot of spaghetti",
+	},
+	Hazards: []gen.Hazard{
+		{
+			Name:        "BarHazard",
+			Description: "This hazard contains a lot of bugs",
+		},
+	},
+}
+
+var validDispatch = Dispatch{
+	ID:         123,
+	CreateTime: PGTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)),
+	Type:       5,
+	Message:    "A valid dispatch",
+}
+
+var validSnapshot = Snapshot{
+	Snapshot: gen.Snapshot{
+		CreateTime:            PGTimestamp(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)),
+		WarSnapshotID:         -1,  // will be filled from Merge
+		AssignmentSnapshotIds: nil, // will be filled from Merge
+		CampaignIds:           []int32{5},
+		DispatchIds:           []int32{123},
+		StatisticsID:          -1,  // will be filled from Merge
+		PlanetSnapshotIds:     nil, // will be filled from Merge
+		Upstream:              ptr("http://localhost:4000"),
+	},
+	WarSnapshot: gen.WarSnapshot{
+		WarID:            999,
+		ImpactMultiplier: 0.005,
+	},
+	AssignmentSnapshots: []gen.AssignmentSnapshot{
+		{
+			AssignmentID: 3,
+			Progress:     []pgtype.Numeric{PGUint64(2), PGUint64(3), PGUint64(4)},
+		},
+	},
+	PlanetSnapshots: []PlanetSnapshot{
+		{
+			PlanetSnapshot: gen.PlanetSnapshot{
+				PlanetID:           456,
+				Health:             556677,
+				CurrentOwner:       "Automatons",
+				RegenPerSecond:     0.06,
+				AttackingPlanetIds: []int32{456},
+			},
+			Event: &gen.EventSnapshot{
+				EventID: 555,
+				Health:  999999,
+			},
+			Statistics: gen.SnapshotStatistic{
+				MissionsWon:     PGUint64(6444323),
+				MissionsLost:    PGUint64(53555),
+				MissionTime:     PGUint64(445566),
+				TerminidKills:   PGUint64(6666677),
+				AutomatonKills:  PGUint64(7565465454),
+				IlluminateKills: PGUint64(5345433455),
+				BulletsFired:    PGUint64(888999399393222),
+				BulletsHit:      PGUint64(49324924499449222),
+				TimePlayed:      PGUint64(uint64(1 * 24 * 60 * time.Second)),
+				Deaths:          PGUint64(55223535),
+				Revives:         PGUint64(44442),
+				Friendlies:      PGUint64(2221111),
+				PlayerCount:     PGUint64(12345678),
+			},
+		},
+	},
+	Statistics: gen.SnapshotStatistic{
+		MissionsWon:     PGUint64(564643344),
+		MissionsLost:    PGUint64(4324332),
+		MissionTime:     PGUint64(432432532552),
+		TerminidKills:   PGUint64(66878822),
+		AutomatonKills:  PGUint64(73737274),
+		IlluminateKills: PGUint64(112212441),
+		BulletsFired:    PGUint64(424444421112),
+		BulletsHit:      PGUint64(33444465767),
+		TimePlayed:      PGUint64(uint64(365 * 24 * 60 * time.Second)),
+		Deaths:          PGUint64(885545432),
+		Revives:         PGUint64(8765333),
+		Friendlies:      PGUint64(444432232),
+		PlayerCount:     PGUint64(44899),
+	},
+}
+
+// mergeWar merges a copy of validWar, which all war-scoped test entities belong to.
+func mergeWar(t *testing.T, client *Client) {
+	t.Helper()
+	var war War
+	if err := copytest.DeepCopy(&war, &validWar); err != nil {
+		t.Fatalf("failed to create war struct copy: %v", err)
+	}
+	if err := war.Merge(context.Background(), client.queries, func(gen.Table, bool, int64) {}); err != nil {
+		t.Fatalf("failed to merge war: %v", err)
+	}
+}
+
+// mergeSnapshotsAt merges a copy of validSnapshot including its dependencies for each of `times`.
+func mergeSnapshotsAt(t *testing.T, client *Client, times ...time.Time) {
+	t.Helper()
+	var (
+		war        War
+		assignment Assignment
+		event      Event
+		planet     Planet
+		campaign   Campaign
+		dispatch   Dispatch
+	)
+	if err := copytest.DeepCopy(
+		&war, &validWar,
+		&assignment, &validAssignment,
+		&event, &validEvent,
+		&planet, &validPlanet,
+		&campaign, &validCampaign,
+		&dispatch, &validDispatch,
+	); err != nil {
+		t.Fatalf("failed to create struct copies: %v", err)
+	}
+	mergers := []EntityMerger{&war, &campaign, &event, &assignment, &planet, &dispatch}
+	for _, createTime := range times {
+		var snapshot Snapshot
+		if err := copytest.DeepCopy(&snapshot, &validSnapshot); err != nil {
+			t.Fatalf("failed to create snapshot copy: %v", err)
+		}
+		snapshot.CreateTime = PGTimestamp(createTime)
+		mergers = append(mergers, &snapshot)
+	}
+	onMerge := func(gen.Table, bool, int64) {}
+	for _, merger := range mergers {
+		if err := merger.Merge(context.Background(), client.queries, onMerge); err != nil {
+			t.Fatalf("failed to merge %T: %v", merger, err)
+		}
+	}
+}
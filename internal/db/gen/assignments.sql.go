@@ -11,17 +11,6 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const assignmentExists = `-- name: AssignmentExists :one
-SELECT EXISTS(SELECT id, title, briefing, description, expiration, task_ids, reward_type, reward_amount FROM assignments WHERE id = $1)
-`
-
-func (q *Queries) AssignmentExists(ctx context.Context, id int64) (bool, error) {
-	row := q.db.QueryRow(ctx, assignmentExists, id)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
-}
-
 const deleteAssignmentTasks = `-- name: DeleteAssignmentTasks :exec
 DELETE FROM assignment_tasks
 WHERE id IN (
@@ -29,26 +18,75 @@ WHERE id IN (
     FROM assignments
     JOIN assignment_tasks
         ON assignment_tasks.id = ANY(task_ids)
-    WHERE assignments.id = $1
+    WHERE assignments.war_id = $1 AND assignments.id = $2
 )
 `
 
-func (q *Queries) DeleteAssignmentTasks(ctx context.Context, assignmentID int64) error {
-	_, err := q.db.Exec(ctx, deleteAssignmentTasks, assignmentID)
+type DeleteAssignmentTasksParams struct {
+	WarID        int32
+	AssignmentID int64
+}
+
+func (q *Queries) DeleteAssignmentTasks(ctx context.Context, arg DeleteAssignmentTasksParams) error {
+	_, err := q.db.Exec(ctx, deleteAssignmentTasks, arg.WarID, arg.AssignmentID)
 	return err
 }
 
 const getAssignment = `-- name: GetAssignment :one
 SELECT id FROM assignments
-WHERE id = $1
+WHERE war_id = $1 AND id = $2
 `
 
-func (q *Queries) GetAssignment(ctx context.Context, id int64) (int64, error) {
-	row := q.db.QueryRow(ctx, getAssignment, id)
+type GetAssignmentParams struct {
+	WarID int32
+	ID    int64
+}
+
+func (q *Queries) GetAssignment(ctx context.Context, arg GetAssignmentParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getAssignment, arg.WarID, arg.ID)
+	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
+const getAssignmentTasks = `-- name: GetAssignmentTasks :many
+SELECT assignment_tasks.id, assignment_tasks.task_type, assignment_tasks.values, assignment_tasks.value_types FROM assignments
+JOIN assignment_tasks
+    ON assignment_tasks.id = ANY(task_ids)
+WHERE assignments.war_id = $1 AND assignments.id = $2
+ORDER BY array_position(task_ids, assignment_tasks.id)
+`
+
+type GetAssignmentTasksParams struct {
+	WarID        int32
+	AssignmentID int64
+}
+
+func (q *Queries) GetAssignmentTasks(ctx context.Context, arg GetAssignmentTasksParams) ([]AssignmentTask, error) {
+	rows, err := q.db.Query(ctx, getAssignmentTasks, arg.WarID, arg.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AssignmentTask{}
+	for rows.Next() {
+		var i AssignmentTask
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskType,
+			&i.Values,
+			&i.ValueTypes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertAssignmentTask = `-- name: InsertAssignmentTask :one
 INSERT INTO assignment_tasks (
     task_type, values, value_types
@@ -71,14 +109,19 @@ func (q *Queries) InsertAssignmentTask(ctx context.Context, arg InsertAssignment
 	return id, err
 }
 
-const mergeAssignment = `-- name: MergeAssignment :execrows
+const mergeAssignment = `-- name: MergeAssignment :one
 INSERT INTO assignments (
-    id, title, briefing, description, expiration, task_ids, reward_type, reward_amount
+    id, title, briefing, description, expiration, task_ids, reward_type, reward_amount, war_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-ON CONFLICT (id) DO UPDATE
+ON CONFLICT (war_id, id) DO UPDATE
     SET title=$2, briefing=$3, description=$4, expiration=$5, task_ids=$6, reward_type=$7, reward_amount=$8
+WHERE (
+    assignments.title, assignments.briefing, assignments.description, assignments.expiration,
+    assignments.task_ids, assignments.reward_type, assignments.reward_amount
+) IS DISTINCT FROM ($2, $3, $4, $5, $6, $7, $8)
+RETURNING (xmax = 0) AS inserted
 `
 
 type MergeAssignmentParams struct {
@@ -90,10 +133,11 @@ type MergeAssignmentParams struct {
 	TaskIds      []int64
 	RewardType   int32
 	RewardAmount pgtype.Numeric
+	WarID        int32
 }
 
-func (q *Queries) MergeAssignment(ctx context.Context, arg MergeAssignmentParams) (int64, error) {
-	result, err := q.db.Exec(ctx, mergeAssignment,
+func (q *Queries) MergeAssignment(ctx context.Context, arg MergeAssignmentParams) (bool, error) {
+	row := q.db.QueryRow(ctx, mergeAssignment,
 		arg.ID,
 		arg.Title,
 		arg.Briefing,
@@ -102,9 +146,9 @@ func (q *Queries) MergeAssignment(ctx context.Context, arg MergeAssignmentParams
 		arg.TaskIds,
 		arg.RewardType,
 		arg.RewardAmount,
+		arg.WarID,
 	)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+	var inserted bool
+	err := row.Scan(&inserted)
+	return inserted, err
 }
@@ -11,51 +11,54 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const campaignExists = `-- name: CampaignExists :one
-SELECT EXISTS(SELECT id, type, count FROM campaigns WHERE id = $1)
-`
-
-func (q *Queries) CampaignExists(ctx context.Context, id int32) (bool, error) {
-	row := q.db.QueryRow(ctx, campaignExists, id)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
-}
-
 const getCampaign = `-- name: GetCampaign :one
 SELECT id FROM campaigns
-WHERE id = $1
+WHERE war_id = $1 AND id = $2
 `
 
-func (q *Queries) GetCampaign(ctx context.Context, id int32) (int32, error) {
-	row := q.db.QueryRow(ctx, getCampaign, id)
+type GetCampaignParams struct {
+	WarID int32
+	ID    int32
+}
+
+func (q *Queries) GetCampaign(ctx context.Context, arg GetCampaignParams) (int32, error) {
+	row := q.db.QueryRow(ctx, getCampaign, arg.WarID, arg.ID)
+	var id int32
 	err := row.Scan(&id)
 	return id, err
 }
 
-const mergeCampaign = `-- name: MergeCampaign :execrows
+const mergeCampaign = `-- name: MergeCampaign :one
 INSERT INTO campaigns (
-    id, type, count
+    id, type, count, war_id, planet_id
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4, $5
 )
-ON CONFLICT (id) DO UPDATE
-    SET type=$2, count=$3
+ON CONFLICT (war_id, id) DO UPDATE
+    SET type=$2, count=$3, planet_id=COALESCE($5, campaigns.planet_id)
 WHERE FALSE IN (
-    EXCLUDED.type=$2, EXCLUDED.count=$3
+    campaigns.type=$2, campaigns.count=$3, campaigns.planet_id IS NOT DISTINCT FROM COALESCE($5, campaigns.planet_id)
 )
+RETURNING (xmax = 0) AS inserted
 `
 
 type MergeCampaignParams struct {
-	ID    int32
-	Type  int32
-	Count pgtype.Numeric
+	ID       int32
+	Type     int32
+	Count    pgtype.Numeric
+	WarID    int32
+	PlanetID *int32
 }
 
-func (q *Queries) MergeCampaign(ctx context.Context, arg MergeCampaignParams) (int64, error) {
-	result, err := q.db.Exec(ctx, mergeCampaign, arg.ID, arg.Type, arg.Count)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+func (q *Queries) MergeCampaign(ctx context.Context, arg MergeCampaignParams) (bool, error) {
+	row := q.db.QueryRow(ctx, mergeCampaign,
+		arg.ID,
+		arg.Type,
+		arg.Count,
+		arg.WarID,
+		arg.PlanetID,
+	)
+	var inserted bool
+	err := row.Scan(&inserted)
+	return inserted, err
 }
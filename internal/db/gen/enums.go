@@ -20,6 +20,16 @@ const (
 	TableSnapshotStatistics                   // Snapshot Statistics
 	TablePlanetSnapshots                      // Planet Snapshots
 	TableSnapshots                            // Snapshots
+	TableSnapshotGaps                         // Snapshot Gaps
+	TableWarSummaries                         // War Summaries
+	TableWarFinalPlanets                      // War Final Planets
+	TablePlanetHistory                        // Planet History
+	TableBiomeHistory                         // Biome History
+	TableCampaignHistory                      // Campaign History
+	TableAssignmentHistory                    // Assignment History
+	TableDispatchHistory                      // Dispatch History
+	TableCampaignLifecycles                   // Campaign Lifecycles
+	TableSyncRuns                             // Sync Runs
 )
 
 var AllTables = []Table{
@@ -38,4 +48,14 @@ var AllTables = []Table{
 	TableSnapshotStatistics,
 	TablePlanetSnapshots,
 	TableSnapshots,
+	TableSnapshotGaps,
+	TableWarSummaries,
+	TableWarFinalPlanets,
+	TablePlanetHistory,
+	TableBiomeHistory,
+	TableCampaignHistory,
+	TableAssignmentHistory,
+	TableDispatchHistory,
+	TableCampaignLifecycles,
+	TableSyncRuns,
 }
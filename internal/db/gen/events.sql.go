@@ -11,39 +11,35 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const eventExists = `-- name: EventExists :one
-SELECT EXISTS(SELECT id, campaign_id, type, faction, max_health, start_time, end_time FROM events WHERE id = $1)
-`
-
-func (q *Queries) EventExists(ctx context.Context, id int32) (bool, error) {
-	row := q.db.QueryRow(ctx, eventExists, id)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
-}
-
 const getEvent = `-- name: GetEvent :one
 SELECT id FROM events
-WHERE id = $1
+WHERE war_id = $1 AND id = $2
 `
 
-func (q *Queries) GetEvent(ctx context.Context, id int32) (int32, error) {
-	row := q.db.QueryRow(ctx, getEvent, id)
+type GetEventParams struct {
+	WarID int32
+	ID    int32
+}
+
+func (q *Queries) GetEvent(ctx context.Context, arg GetEventParams) (int32, error) {
+	row := q.db.QueryRow(ctx, getEvent, arg.WarID, arg.ID)
+	var id int32
 	err := row.Scan(&id)
 	return id, err
 }
 
-const mergeEvent = `-- name: MergeEvent :execrows
+const mergeEvent = `-- name: MergeEvent :one
 INSERT INTO events (
-    id, campaign_id, type, faction, max_health, start_time, end_time
+    id, campaign_id, type, faction, max_health, start_time, end_time, war_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
-ON CONFLICT (id) DO UPDATE
+ON CONFLICT (war_id, id) DO UPDATE
     SET campaign_id=$2, type=$3, faction=$4, max_health=$5, start_time=$6, end_time=$7
 WHERE FALSE IN (
-    EXCLUDED.campaign_id=$2, EXCLUDED.type=$3, EXCLUDED.faction=$4, EXCLUDED.max_health=$5, EXCLUDED.start_time=$6, EXCLUDED.end_time=$7
+    events.campaign_id=$2, events.type=$3, events.faction=$4, events.max_health=$5, events.start_time=$6, events.end_time=$7
 )
+RETURNING (xmax = 0) AS inserted
 `
 
 type MergeEventParams struct {
@@ -54,10 +50,11 @@ type MergeEventParams struct {
 	MaxHealth  int64
 	StartTime  pgtype.Timestamp
 	EndTime    pgtype.Timestamp
+	WarID      int32
 }
 
-func (q *Queries) MergeEvent(ctx context.Context, arg MergeEventParams) (int64, error) {
-	result, err := q.db.Exec(ctx, mergeEvent,
+func (q *Queries) MergeEvent(ctx context.Context, arg MergeEventParams) (bool, error) {
+	row := q.db.QueryRow(ctx, mergeEvent,
 		arg.ID,
 		arg.CampaignID,
 		arg.Type,
@@ -65,9 +62,9 @@ func (q *Queries) MergeEvent(ctx context.Context, arg MergeEventParams) (int64,
 		arg.MaxHealth,
 		arg.StartTime,
 		arg.EndTime,
+		arg.WarID,
 	)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+	var inserted bool
+	err := row.Scan(&inserted)
+	return inserted, err
 }
@@ -25,6 +25,24 @@ type Assignment struct {
 	RewardType int32
 	// The amount of Type that will be awarded
 	RewardAmount pgtype.Numeric
+	// The war this assignment was given in
+	WarID int32
+}
+
+// Contains all versions of the assignments, e.g. previous briefings
+type AssignmentHistory struct {
+	WarID        int32
+	ID           int64
+	Title        string
+	Briefing     string
+	Description  string
+	Expiration   pgtype.Timestamp
+	RewardType   int32
+	RewardAmount pgtype.Numeric
+	// When this version was first merged
+	ValidFrom pgtype.Timestamp
+	// When this version was replaced, NULL for the current version
+	ValidTo pgtype.Timestamp
 }
 
 type AssignmentSnapshot struct {
@@ -32,6 +50,8 @@ type AssignmentSnapshot struct {
 	AssignmentID int64
 	// A list of numbers, how they represent progress is unknown.
 	Progress []pgtype.Numeric
+	// The war of the captured assignment
+	WarID int32
 }
 
 // Represents a task in an Assignment that needs to be completed to finish the assignment
@@ -52,6 +72,16 @@ type Biome struct {
 	Description string
 }
 
+// Contains all versions of the biomes, e.g. previous descriptions
+type BiomeHistory struct {
+	Name        string
+	Description string
+	// When this version was first merged
+	ValidFrom pgtype.Timestamp
+	// When this version was replaced, NULL for the current version
+	ValidTo pgtype.Timestamp
+}
+
 // Represents an ongoing campaign on a planet
 type Campaign struct {
 	// The unique identifier of this campaign
@@ -60,6 +90,37 @@ type Campaign struct {
 	Type int32
 	// Indicates how many campaigns have already been fought on this Planet
 	Count pgtype.Numeric
+	// The war this campaign is part of
+	WarID int32
+	// The planet on which this campaign is fought
+	PlanetID *int32
+}
+
+// Contains all versions of the campaigns
+type CampaignHistory struct {
+	WarID int32
+	ID    int32
+	Type  int32
+	Count pgtype.Numeric
+	// When this version was first merged
+	ValidFrom pgtype.Timestamp
+	// When this version was replaced, NULL for the current version
+	ValidTo  pgtype.Timestamp
+	PlanetID *int32
+}
+
+// Tracks when campaigns started and ended and how they were resolved, maintained after each snapshot
+type CampaignLifecycle struct {
+	WarID      int32
+	CampaignID int32
+	// Time of the first snapshot containing the campaign
+	FirstSeen pgtype.Timestamp
+	// Time of the latest snapshot containing the campaign
+	LastSeen pgtype.Timestamp
+	// Whether the campaign is no longer active, i.e. missing from the latest snapshot
+	Ended bool
+	// Whether the planet was liberated (or defended) or lost, NULL until the planet has been captured in a snapshot after the campaign ended
+	Outcome *string
 }
 
 // Represents a message from high command to the players, usually updates on the status of the war effort.
@@ -74,6 +135,18 @@ type Dispatch struct {
 	Message string
 }
 
+// Contains all versions of the dispatches, e.g. previous messages
+type DispatchHistory struct {
+	ID         int32
+	CreateTime pgtype.Timestamp
+	Type       int32
+	Message    string
+	// When this version was first merged
+	ValidFrom pgtype.Timestamp
+	// When this version was replaced, NULL for the current version
+	ValidTo pgtype.Timestamp
+}
+
 // Represents an ongoing event on a Planet.
 type Event struct {
 	ID         int32
@@ -88,6 +161,8 @@ type Event struct {
 	StartTime pgtype.Timestamp
 	// When the event will end (or has ended).
 	EndTime pgtype.Timestamp
+	// The war this event is part of
+	WarID int32
 }
 
 // Contains dynamic data about a currently-ongoing event
@@ -96,6 +171,8 @@ type EventSnapshot struct {
 	ID      int64
 	EventID int32
 	Health  int64
+	// The war of the captured event
+	WarID int32
 }
 
 // Describes an environmental hazards that can be present on a planet
@@ -126,6 +203,27 @@ type Planet struct {
 	MaxHealth int64
 	// The faction that originally owned the plane
 	InitialOwner string
+	// The war this planet is part of
+	WarID int32
+}
+
+// Contains all versions of the planets, e.g. previous names
+type PlanetHistory struct {
+	WarID        int32
+	ID           int32
+	Name         string
+	Sector       string
+	Position     []float64
+	WaypointIds  []int32
+	Disabled     bool
+	BiomeName    string
+	HazardNames  []string
+	MaxHealth    int64
+	InitialOwner string
+	// When this version was first merged
+	ValidFrom pgtype.Timestamp
+	// When this version was replaced, NULL for the current version
+	ValidTo pgtype.Timestamp
 }
 
 // Contains dynamic data about a planet currently part of this war
@@ -146,11 +244,13 @@ type PlanetSnapshot struct {
 	RegenPerSecond float64
 	// A set of statistics scoped to this planet.
 	StatisticsID int64
+	// The war of the captured planet
+	WarID int32
 }
 
 // Contains the dynamic data of any metrics changing over time.
 type Snapshot struct {
-	// The time the snapshot of the war was taken, auto-generated as current timestamp
+	// The time the snapshot of the war was taken, as reported by the API. All other data of the snapshot dates from this time.
 	CreateTime pgtype.Timestamp
 	// Dynamic data about current war
 	WarSnapshotID int64
@@ -164,6 +264,18 @@ type Snapshot struct {
 	PlanetSnapshotIds []int64
 	// Global statistics for the current war
 	StatisticsID int64
+	// Root URL of the API instance which served the snapshot data, NULL for snapshots taken before it was recorded
+	Upstream *string
+}
+
+// Marks intervals between two consecutive snapshots in which scheduled snapshots are missing, e.g. because the worker was down. Charts should not interpolate across these.
+type SnapshotGap struct {
+	// Time of the last snapshot before the gap
+	StartTime pgtype.Timestamp
+	// Time of the first snapshot after the gap
+	EndTime pgtype.Timestamp
+	// Number of scheduled snapshot runs which did not produce a snapshot
+	MissedRuns int32
 }
 
 // Contains statistics of missions, kills, success rate etc
@@ -191,6 +303,26 @@ type SnapshotStatistic struct {
 	PlayerCount pgtype.Numeric
 }
 
+// Ledger of all sync job runs, including failed ones
+type SyncRun struct {
+	// Run ID, also attached to all log records of the run
+	ID pgtype.UUID
+	// Name of the sync job, e.g. "snapshot"
+	Job       string
+	StartTime pgtype.Timestamp
+	EndTime   pgtype.Timestamp
+	// Whether the run succeeded or failed
+	Outcome string
+	// Array with one object per queried API endpoint, containing its status (ok, unchanged or failed), latency in milliseconds, upstream and error
+	Endpoints []byte
+	// Object with inserted, updated and unchanged rows per merged table, keyed by table name
+	MergeCounts []byte
+	// Error which made the run fail, NULL if it succeeded
+	Error *string
+	// Time of the snapshot created by the run, NULL if no snapshot was created
+	SnapshotCreateTime pgtype.Timestamp
+}
+
 // Represents the global information of the ongoing war
 type War struct {
 	ID int32
@@ -202,6 +334,14 @@ type War struct {
 	Factions []string
 }
 
+// Planet ownership at the end of a war season.
+type WarFinalPlanet struct {
+	WarID    int32
+	PlanetID int32
+	// The faction controlling the planet when the war ended, as of its latest snapshot or its initial owner if it has never been captured in a snapshot
+	Owner string
+}
+
 // Contains the dynamic data about a war.
 type WarSnapshot struct {
 	// Auto-generated by sequence
@@ -210,3 +350,20 @@ type WarSnapshot struct {
 	// A fraction used to calculate the impact of a mission on the war effort
 	ImpactMultiplier float64
 }
+
+// Summarizes a war season once it has ended, i.e. once the API reported a new war ID.
+type WarSummary struct {
+	WarID int32
+	// When the end of the war was detected
+	CloseTime pgtype.Timestamp
+	// Time of the first snapshot taken during the war
+	FirstSnapshotTime pgtype.Timestamp
+	// Time of the last snapshot taken during the war
+	FinalSnapshotTime pgtype.Timestamp
+	// Number of snapshots taken during the war
+	SnapshotCount int64
+	// Global statistics at the end of the war
+	FinalStatisticsID int64
+	// Highest number of players present in any snapshot of the war
+	PeakPlayerCount pgtype.Numeric
+}
@@ -9,17 +9,6 @@ import (
 	"context"
 )
 
-const biomeExists = `-- name: BiomeExists :one
-SELECT EXISTS(SELECT name, description FROM biomes WHERE name = $1)
-`
-
-func (q *Queries) BiomeExists(ctx context.Context, name string) (bool, error) {
-	row := q.db.QueryRow(ctx, biomeExists, name)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
-}
-
 const getBiome = `-- name: GetBiome :one
 SELECT name FROM biomes
 WHERE name = $1
@@ -44,27 +33,22 @@ func (q *Queries) GetHazard(ctx context.Context, name string) (string, error) {
 
 const getPlanet = `-- name: GetPlanet :one
 SELECT id FROM planets
-WHERE id = $1
+WHERE war_id = $1 AND id = $2
 `
 
-func (q *Queries) GetPlanet(ctx context.Context, id int32) (int32, error) {
-	row := q.db.QueryRow(ctx, getPlanet, id)
-	err := row.Scan(&id)
-	return id, err
+type GetPlanetParams struct {
+	WarID int32
+	ID    int32
 }
 
-const hazardExists = `-- name: HazardExists :one
-SELECT EXISTS(SELECT name, description FROM hazards WHERE name = $1)
-`
-
-func (q *Queries) HazardExists(ctx context.Context, name string) (bool, error) {
-	row := q.db.QueryRow(ctx, hazardExists, name)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+func (q *Queries) GetPlanet(ctx context.Context, arg GetPlanetParams) (int32, error) {
+	row := q.db.QueryRow(ctx, getPlanet, arg.WarID, arg.ID)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
 }
 
-const mergeBiome = `-- name: MergeBiome :execrows
+const mergeBiome = `-- name: MergeBiome :one
 INSERT INTO biomes (
     name, description
 ) VALUES (
@@ -73,8 +57,9 @@ INSERT INTO biomes (
 ON CONFLICT (name) DO UPDATE
     SET description=$2
 WHERE FALSE IN (
-    EXCLUDED.description=$2
+    biomes.description=$2
 )
+RETURNING (xmax = 0) AS inserted
 `
 
 type MergeBiomeParams struct {
@@ -82,15 +67,14 @@ type MergeBiomeParams struct {
 	Description string
 }
 
-func (q *Queries) MergeBiome(ctx context.Context, arg MergeBiomeParams) (int64, error) {
-	result, err := q.db.Exec(ctx, mergeBiome, arg.Name, arg.Description)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+func (q *Queries) MergeBiome(ctx context.Context, arg MergeBiomeParams) (bool, error) {
+	row := q.db.QueryRow(ctx, mergeBiome, arg.Name, arg.Description)
+	var inserted bool
+	err := row.Scan(&inserted)
+	return inserted, err
 }
 
-const mergeHazard = `-- name: MergeHazard :execrows
+const mergeHazard = `-- name: MergeHazard :one
 INSERT INTO hazards (
     name, description
 ) VALUES (
@@ -99,8 +83,9 @@ INSERT INTO hazards (
 ON CONFLICT (name) DO UPDATE
     SET description=$2
 WHERE FALSE IN (
-    EXCLUDED.description=$2
+    hazards.description=$2
 )
+RETURNING (xmax = 0) AS inserted
 `
 
 type MergeHazardParams struct {
@@ -108,25 +93,25 @@ type MergeHazardParams struct {
 	Description string
 }
 
-func (q *Queries) MergeHazard(ctx context.Context, arg MergeHazardParams) (int64, error) {
-	result, err := q.db.Exec(ctx, mergeHazard, arg.Name, arg.Description)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+func (q *Queries) MergeHazard(ctx context.Context, arg MergeHazardParams) (bool, error) {
+	row := q.db.QueryRow(ctx, mergeHazard, arg.Name, arg.Description)
+	var inserted bool
+	err := row.Scan(&inserted)
+	return inserted, err
 }
 
-const mergePlanet = `-- name: MergePlanet :execrows
+const mergePlanet = `-- name: MergePlanet :one
 INSERT INTO planets (
-    id, name, sector, position, waypoint_ids, disabled, biome_name, hazard_names, max_health, initial_owner
+    id, name, sector, position, waypoint_ids, disabled, biome_name, hazard_names, max_health, initial_owner, war_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
-ON CONFLICT (id) DO UPDATE
+ON CONFLICT (war_id, id) DO UPDATE
     SET name=$2, sector=$3, position=$4, waypoint_ids=$5, disabled=$6, biome_name=$7, hazard_names=$8, max_health=$9, initial_owner=$10
 WHERE FALSE IN (
-    EXCLUDED.name=$2, EXCLUDED.sector=$3, EXCLUDED.position=$4,EXCLUDED. waypoint_ids=$5, EXCLUDED.disabled=$6, EXCLUDED.biome_name=$7, EXCLUDED.hazard_names=$8, EXCLUDED.max_health=$9, EXCLUDED.initial_owner=$10
+    planets.name=$2, planets.sector=$3, planets.position=$4, planets.waypoint_ids=$5, planets.disabled=$6, planets.biome_name=$7, planets.hazard_names=$8, planets.max_health=$9, planets.initial_owner=$10
 )
+RETURNING (xmax = 0) AS inserted
 `
 
 type MergePlanetParams struct {
@@ -140,10 +125,11 @@ type MergePlanetParams struct {
 	HazardNames  []string
 	MaxHealth    int64
 	InitialOwner string
+	WarID        int32
 }
 
-func (q *Queries) MergePlanet(ctx context.Context, arg MergePlanetParams) (int64, error) {
-	result, err := q.db.Exec(ctx, mergePlanet,
+func (q *Queries) MergePlanet(ctx context.Context, arg MergePlanetParams) (bool, error) {
+	row := q.db.QueryRow(ctx, mergePlanet,
 		arg.ID,
 		arg.Name,
 		arg.Sector,
@@ -154,20 +140,9 @@ func (q *Queries) MergePlanet(ctx context.Context, arg MergePlanetParams) (int64
 		arg.HazardNames,
 		arg.MaxHealth,
 		arg.InitialOwner,
+		arg.WarID,
 	)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
-}
-
-const planetExists = `-- name: PlanetExists :one
-SELECT EXISTS(SELECT id, name, sector, position, waypoint_ids, disabled, biome_name, hazard_names, max_health, initial_owner FROM planets WHERE id = $1)
-`
-
-func (q *Queries) PlanetExists(ctx context.Context, id int32) (bool, error) {
-	row := q.db.QueryRow(ctx, planetExists, id)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+	var inserted bool
+	err := row.Scan(&inserted)
+	return inserted, err
 }
@@ -12,7 +12,7 @@ import (
 )
 
 const getLatestSnapshot = `-- name: GetLatestSnapshot :one
-SELECT create_time, war_snapshot_id, assignment_snapshot_ids, campaign_ids, dispatch_ids, planet_snapshot_ids, statistics_id FROM snapshots
+SELECT create_time, war_snapshot_id, assignment_snapshot_ids, campaign_ids, dispatch_ids, planet_snapshot_ids, statistics_id, upstream FROM snapshots
 ORDER BY create_time desc
 LIMIT 1
 `
@@ -28,15 +28,55 @@ func (q *Queries) GetLatestSnapshot(ctx context.Context) (Snapshot, error) {
 		&i.DispatchIds,
 		&i.PlanetSnapshotIds,
 		&i.StatisticsID,
+		&i.Upstream,
 	)
 	return i, err
 }
 
+const getLatestSnapshotTime = `-- name: GetLatestSnapshotTime :one
+SELECT create_time FROM snapshots
+ORDER BY create_time desc
+LIMIT 1
+`
+
+func (q *Queries) GetLatestSnapshotTime(ctx context.Context) (pgtype.Timestamp, error) {
+	row := q.db.QueryRow(ctx, getLatestSnapshotTime)
+	var create_time pgtype.Timestamp
+	err := row.Scan(&create_time)
+	return create_time, err
+}
+
+const getSnapshotTimes = `-- name: GetSnapshotTimes :many
+SELECT create_time FROM snapshots
+WHERE create_time >= $1
+ORDER BY create_time
+`
+
+func (q *Queries) GetSnapshotTimes(ctx context.Context, createTime pgtype.Timestamp) ([]pgtype.Timestamp, error) {
+	rows, err := q.db.Query(ctx, getSnapshotTimes, createTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.Timestamp{}
+	for rows.Next() {
+		var create_time pgtype.Timestamp
+		if err := rows.Scan(&create_time); err != nil {
+			return nil, err
+		}
+		items = append(items, create_time)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertAssignmentSnapshot = `-- name: InsertAssignmentSnapshot :one
 INSERT INTO assignment_snapshots (
-    assignment_id, progress
+    assignment_id, progress, war_id
 ) VALUES (
-    $1, $2
+    $1, $2, $3
 )
 RETURNING id
 `
@@ -44,10 +84,11 @@ RETURNING id
 type InsertAssignmentSnapshotParams struct {
 	AssignmentID int64
 	Progress     []pgtype.Numeric
+	WarID        int32
 }
 
 func (q *Queries) InsertAssignmentSnapshot(ctx context.Context, arg InsertAssignmentSnapshotParams) (int64, error) {
-	row := q.db.QueryRow(ctx, insertAssignmentSnapshot, arg.AssignmentID, arg.Progress)
+	row := q.db.QueryRow(ctx, insertAssignmentSnapshot, arg.AssignmentID, arg.Progress, arg.WarID)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
@@ -55,9 +96,9 @@ func (q *Queries) InsertAssignmentSnapshot(ctx context.Context, arg InsertAssign
 
 const insertEventSnapshot = `-- name: InsertEventSnapshot :one
 INSERT INTO event_snapshots (
-    event_id, health
+    event_id, health, war_id
 ) VALUES (
-    $1, $2
+    $1, $2, $3
 )
 RETURNING id
 `
@@ -65,10 +106,11 @@ RETURNING id
 type InsertEventSnapshotParams struct {
 	EventID int32
 	Health  int64
+	WarID   int32
 }
 
 func (q *Queries) InsertEventSnapshot(ctx context.Context, arg InsertEventSnapshotParams) (int64, error) {
-	row := q.db.QueryRow(ctx, insertEventSnapshot, arg.EventID, arg.Health)
+	row := q.db.QueryRow(ctx, insertEventSnapshot, arg.EventID, arg.Health, arg.WarID)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
@@ -76,9 +118,9 @@ func (q *Queries) InsertEventSnapshot(ctx context.Context, arg InsertEventSnapsh
 
 const insertPlanetSnapshot = `-- name: InsertPlanetSnapshot :one
 INSERT INTO planet_snapshots (
-    planet_id, health, current_owner, event_snapshot_id, attacking_planet_ids, regen_per_second, statistics_id
+    planet_id, health, current_owner, event_snapshot_id, attacking_planet_ids, regen_per_second, statistics_id, war_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
 RETURNING id
 `
@@ -91,6 +133,7 @@ type InsertPlanetSnapshotParams struct {
 	AttackingPlanetIds []int32
 	RegenPerSecond     float64
 	StatisticsID       int64
+	WarID              int32
 }
 
 func (q *Queries) InsertPlanetSnapshot(ctx context.Context, arg InsertPlanetSnapshotParams) (int64, error) {
@@ -102,6 +145,7 @@ func (q *Queries) InsertPlanetSnapshot(ctx context.Context, arg InsertPlanetSnap
 		arg.AttackingPlanetIds,
 		arg.RegenPerSecond,
 		arg.StatisticsID,
+		arg.WarID,
 	)
 	var id int64
 	err := row.Scan(&id)
@@ -110,30 +154,34 @@ func (q *Queries) InsertPlanetSnapshot(ctx context.Context, arg InsertPlanetSnap
 
 const insertSnapshot = `-- name: InsertSnapshot :one
 INSERT INTO snapshots (
-    war_snapshot_id, assignment_snapshot_ids, campaign_ids, dispatch_ids, planet_snapshot_ids, statistics_id
+    create_time, war_snapshot_id, assignment_snapshot_ids, campaign_ids, dispatch_ids, planet_snapshot_ids, statistics_id, upstream
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
 RETURNING create_time
 `
 
 type InsertSnapshotParams struct {
+	CreateTime            pgtype.Timestamp
 	WarSnapshotID         int64
 	AssignmentSnapshotIds []int64
 	CampaignIds           []int32
 	DispatchIds           []int32
 	PlanetSnapshotIds     []int64
 	StatisticsID          int64
+	Upstream              *string
 }
 
 func (q *Queries) InsertSnapshot(ctx context.Context, arg InsertSnapshotParams) (pgtype.Timestamp, error) {
 	row := q.db.QueryRow(ctx, insertSnapshot,
+		arg.CreateTime,
 		arg.WarSnapshotID,
 		arg.AssignmentSnapshotIds,
 		arg.CampaignIds,
 		arg.DispatchIds,
 		arg.PlanetSnapshotIds,
 		arg.StatisticsID,
+		arg.Upstream,
 	)
 	var create_time pgtype.Timestamp
 	err := row.Scan(&create_time)
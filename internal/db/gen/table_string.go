@@ -23,11 +23,21 @@ func _() {
 	_ = x[TableSnapshotStatistics-13]
 	_ = x[TablePlanetSnapshots-14]
 	_ = x[TableSnapshots-15]
+	_ = x[TableSnapshotGaps-16]
+	_ = x[TableWarSummaries-17]
+	_ = x[TableWarFinalPlanets-18]
+	_ = x[TablePlanetHistory-19]
+	_ = x[TableBiomeHistory-20]
+	_ = x[TableCampaignHistory-21]
+	_ = x[TableAssignmentHistory-22]
+	_ = x[TableDispatchHistory-23]
+	_ = x[TableCampaignLifecycles-24]
+	_ = x[TableSyncRuns-25]
 }
 
-const _Table_name = "WarsCampaignsEventsBiomesHazardsPlanetsAssignment TasksAssignmentsDispatchesWar SnapshotsEvent SnapshotsAssignment SnapshotsSnapshot StatisticsPlanet SnapshotsSnapshots"
+const _Table_name = "WarsCampaignsEventsBiomesHazardsPlanetsAssignment TasksAssignmentsDispatchesWar SnapshotsEvent SnapshotsAssignment SnapshotsSnapshot StatisticsPlanet SnapshotsSnapshotsSnapshot GapsWar SummariesWar Final PlanetsPlanet HistoryBiome HistoryCampaign HistoryAssignment HistoryDispatch HistoryCampaign LifecyclesSync Runs"
 
-var _Table_index = [...]uint8{0, 4, 13, 19, 25, 32, 39, 55, 66, 76, 89, 104, 124, 143, 159, 168}
+var _Table_index = [...]uint16{0, 4, 13, 19, 25, 32, 39, 55, 66, 76, 89, 104, 124, 143, 159, 168, 181, 194, 211, 225, 238, 254, 272, 288, 307, 316}
 
 func (i Table) String() string {
 	i -= 1
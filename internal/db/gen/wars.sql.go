@@ -22,7 +22,7 @@ func (q *Queries) GetWar(ctx context.Context, id int32) (int32, error) {
 	return id, err
 }
 
-const mergeWar = `-- name: MergeWar :execrows
+const mergeWar = `-- name: MergeWar :one
 INSERT INTO wars (
     id, start_time, end_time, factions
 ) VALUES (
@@ -31,8 +31,9 @@ INSERT INTO wars (
 ON CONFLICT (id) DO UPDATE
     SET start_time=$2, end_time=$3, factions=$4
 WHERE FALSE IN (
-    EXCLUDED.start_time=$2, EXCLUDED.end_time=$3, EXCLUDED.factions=$4
+    wars.start_time=$2, wars.end_time=$3, wars.factions=$4
 )
+RETURNING (xmax = 0) AS inserted
 `
 
 type MergeWarParams struct {
@@ -42,26 +43,14 @@ type MergeWarParams struct {
 	Factions  []string
 }
 
-func (q *Queries) MergeWar(ctx context.Context, arg MergeWarParams) (int64, error) {
-	result, err := q.db.Exec(ctx, mergeWar,
+func (q *Queries) MergeWar(ctx context.Context, arg MergeWarParams) (bool, error) {
+	row := q.db.QueryRow(ctx, mergeWar,
 		arg.ID,
 		arg.StartTime,
 		arg.EndTime,
 		arg.Factions,
 	)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
-}
-
-const warExists = `-- name: WarExists :one
-SELECT EXISTS(SELECT id, start_time, end_time, factions FROM wars WHERE id = $1)
-`
-
-func (q *Queries) WarExists(ctx context.Context, id int32) (bool, error) {
-	row := q.db.QueryRow(ctx, warExists, id)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+	var inserted bool
+	err := row.Scan(&inserted)
+	return inserted, err
 }
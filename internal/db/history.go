@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+)
+
+// The history tables are maintained by triggers whenever an entity is merged.
+// The helpers below answer what an entity looked like at a given time, which is converted to UTC like all timestamps in the DB.
+// `ok` is false if the entity did not exist at that time or was merged for the first time after it.
+
+// PlanetAt returns the planet as it was at `t`.
+func (c *Client) PlanetAt(ctx context.Context, warID int32, id int32, t time.Time) (planet gen.PlanetHistory, ok bool, err error) {
+	return historyAt(c.queries.GetPlanetAt(ctx, gen.GetPlanetAtParams{WarID: warID, ID: id, At: PGTimestamp(t.UTC())}))
+}
+
+// PlanetHistory returns all versions of the planet in ascending order.
+func (c *Client) PlanetHistory(ctx context.Context, warID int32, id int32) ([]gen.PlanetHistory, error) {
+	versions, err := c.queries.GetPlanetHistory(ctx, gen.GetPlanetHistoryParams{WarID: warID, ID: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history of planet ID=%d: %v", id, err)
+	}
+	return versions, nil
+}
+
+// BiomeAt returns the biome as it was at `t`.
+func (c *Client) BiomeAt(ctx context.Context, name string, t time.Time) (biome gen.BiomeHistory, ok bool, err error) {
+	return historyAt(c.queries.GetBiomeAt(ctx, gen.GetBiomeAtParams{Name: name, At: PGTimestamp(t.UTC())}))
+}
+
+// CampaignAt returns the campaign as it was at `t`.
+func (c *Client) CampaignAt(ctx context.Context, warID int32, id int32, t time.Time) (campaign gen.CampaignHistory, ok bool, err error) {
+	return historyAt(c.queries.GetCampaignAt(ctx, gen.GetCampaignAtParams{WarID: warID, ID: id, At: PGTimestamp(t.UTC())}))
+}
+
+// AssignmentAt returns the assignment as it was at `t`.
+func (c *Client) AssignmentAt(ctx context.Context, warID int32, id int64, t time.Time) (assignment gen.AssignmentHistory, ok bool, err error) {
+	return historyAt(c.queries.GetAssignmentAt(ctx, gen.GetAssignmentAtParams{WarID: warID, ID: id, At: PGTimestamp(t.UTC())}))
+}
+
+// DispatchAt returns the dispatch as it was at `t`.
+func (c *Client) DispatchAt(ctx context.Context, id int32, t time.Time) (dispatch gen.DispatchHistory, ok bool, err error) {
+	return historyAt(c.queries.GetDispatchAt(ctx, gen.GetDispatchAtParams{ID: id, At: PGTimestamp(t.UTC())}))
+}
+
+func historyAt[T any](version T, err error) (T, bool, error) {
+	var zero T
+	if errors.Is(err, pgx.ErrNoRows) {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, fmt.Errorf("failed to query %T: %v", version, err)
+	}
+	return version, true, nil
+}
@@ -0,0 +1,105 @@
+//go:build integration
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/copytest"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+)
+
+func TestPlanetHistory(t *testing.T) {
+	withClientMigrated(t, func(client *Client) {
+		ctx := context.Background()
+		mergeWar(t, client)
+
+		var planet Planet
+		if err := copytest.DeepCopy(&planet, &validPlanet); err != nil {
+			t.Fatalf("failed to create planet struct copy: %v", err)
+		}
+		merge := func() {
+			t.Helper()
+			if err := planet.Merge(ctx, client.queries, func(gen.Table, bool, int64) {}); err != nil {
+				t.Fatalf("failed to merge planet: %v", err)
+			}
+		}
+
+		merge()
+		oldName := planet.Name
+		beforeRename := time.Now().UTC()
+		// history times have microsecond precision
+		time.Sleep(time.Millisecond)
+
+		planet.Name = "Renamed"
+		merge()
+		// unchanged, no new version
+		merge()
+
+		versions, err := client.PlanetHistory(ctx, planet.WarID, planet.ID)
+		if err != nil {
+			t.Fatalf("PlanetHistory() error = %v", err)
+		}
+		if len(versions) != 2 {
+			t.Fatalf("PlanetHistory() returned %d versions, want 2", len(versions))
+		}
+		if versions[0].Name != oldName || versions[1].Name != "Renamed" {
+			t.Errorf("PlanetHistory() names = [%s, %s], want [%s, Renamed]", versions[0].Name, versions[1].Name, oldName)
+		}
+		if versions[1].ValidTo.Valid {
+			t.Errorf("current version has ValidTo = %v, want NULL", versions[1].ValidTo.Time)
+		}
+		if !versions[0].ValidTo.Time.Equal(versions[1].ValidFrom.Time) {
+			t.Errorf("previous version ends at %v, want %v", versions[0].ValidTo.Time, versions[1].ValidFrom.Time)
+		}
+
+		got, ok, err := client.PlanetAt(ctx, planet.WarID, planet.ID, beforeRename)
+		if err != nil || !ok || got.Name != oldName {
+			t.Errorf("PlanetAt(before rename) = %q, %v, %v, want %q, true, nil", got.Name, ok, err, oldName)
+		}
+		got, ok, err = client.PlanetAt(ctx, planet.WarID, planet.ID, time.Now().UTC())
+		if err != nil || !ok || got.Name != "Renamed" {
+			t.Errorf("PlanetAt(now) = %q, %v, %v, want Renamed, true, nil", got.Name, ok, err)
+		}
+		if _, ok, err = client.PlanetAt(ctx, planet.WarID, planet.ID, versions[0].ValidFrom.Time.Add(-time.Hour)); err != nil || ok {
+			t.Errorf("PlanetAt(before first merge) = %v, %v, want false, nil", ok, err)
+		}
+	})
+}
+
+func TestCampaignHistoryPlanet(t *testing.T) {
+	withClientMigrated(t, func(client *Client) {
+		ctx := context.Background()
+		onMerge := func(gen.Table, bool, int64) {}
+		mergeWar(t, client)
+
+		var planet Planet
+		if err := copytest.DeepCopy(&planet, &validPlanet); err != nil {
+			t.Fatalf("failed to create planet struct copy: %v", err)
+		}
+		if err := planet.Merge(ctx, client.queries, onMerge); err != nil {
+			t.Fatalf("failed to merge planet: %v", err)
+		}
+
+		campaign := validCampaign
+		campaign.PlanetID = &planet.ID
+		if err := campaign.Merge(ctx, client.queries, onMerge); err != nil {
+			t.Fatalf("Campaign.Merge() error = %v", err)
+		}
+		// a missing planet does not create a new version
+		campaign.PlanetID = nil
+		if err := campaign.Merge(ctx, client.queries, onMerge); err != nil {
+			t.Fatalf("Campaign.Merge() without planet error = %v", err)
+		}
+
+		history, err := client.queries.GetCampaignHistory(ctx, gen.GetCampaignHistoryParams{WarID: campaign.WarID, ID: campaign.ID})
+		if err != nil {
+			t.Fatalf("failed to fetch campaign history: %v", err)
+		}
+		if len(history) != 1 || history[0].PlanetID == nil || *history[0].PlanetID != planet.ID {
+			t.Errorf("campaign history = %+v, want single version on planet %d", history, planet.ID)
+		}
+	})
+}
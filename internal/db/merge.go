@@ -2,52 +2,150 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/stnokott/helldivers-client/internal/db/gen"
 	"github.com/stnokott/helldivers-client/internal/db/stats"
+	"github.com/stnokott/helldivers-client/internal/logging"
 )
 
 // EntityMerger provide a means of merging an entity to the database.
 type EntityMerger interface {
+	Entity
 	// Merge merges the implementing entity to the database. It should call `onMerge` when finished.
 	Merge(ctx context.Context, tx *gen.Queries, onMerge onMergeFunc) error
 }
 
 type onMergeFunc func(table gen.Table, exists bool, affectedRows int64)
 
-// Merge attempts to merge each `EntityMerger` to the database.
+// upsertFunc is a query which inserts or updates a single row.
 //
-// It will print statistics once finished.
-func (c *Client) Merge(ctx context.Context, mergers ...[]EntityMerger) error {
+// It returns whether the row was inserted (as opposed to updated) using `RETURNING (xmax = 0)`,
+// since xmax is only set for rows which have been updated.
+// If the existing row is left unchanged, no row is returned.
+type upsertFunc[P any] func(ctx context.Context, arg P) (inserted bool, err error)
+
+// upsert runs `query` with `arg` and reports the result for `table` to `onMerge`.
+//
+// The existence of the row is derived from the upsert itself, so no separate query is required.
+func upsert[P any](ctx context.Context, table gen.Table, query upsertFunc[P], arg P, onMerge onMergeFunc) error {
+	inserted, err := query(ctx, arg)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// the WHERE clause of the upsert prevented the update
+		onMerge(table, true, 0)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	onMerge(table, !inserted, 1)
+	return nil
+}
+
+// Merge attempts to merge each `Entity` to the database.
+//
+// Insert/update statistics are added to `collector`.
+func (c *Client) Merge(ctx context.Context, collector stats.Collector, entities ...[]Entity) error {
 	mergeFunc := func(qtx *gen.Queries, onMerge onMergeFunc) error {
+		v := txMerger{tx: qtx, onMerge: onMerge}
 		// run merges
-		for _, mSlice := range mergers {
-			if len(mSlice) == 0 {
-				c.log.Println("WARN: got 0 entities to merge")
+		for _, eSlice := range entities {
+			if len(eSlice) == 0 {
+				c.log.WarnContext(ctx, "got 0 entities to merge")
 			}
-			for _, merger := range mSlice {
-				if err := merger.Merge(ctx, qtx, onMerge); err != nil {
+			for _, entity := range eSlice {
+				if err := entity.Accept(ctx, v); err != nil {
 					return err
 				}
 			}
 		}
 		return nil
 	}
-	return c.withTx(ctx, mergeFunc)
+	return c.withTx(ctx, collector, mergeFunc)
+}
+
+// compile-time implementation check
+var _ EntityVisitor = txMerger{}
+
+// txMerger merges every type of entity within the transaction `tx`.
+type txMerger struct {
+	tx      *gen.Queries
+	onMerge onMergeFunc
+}
+
+func (m txMerger) merge(ctx context.Context, merger EntityMerger) error {
+	return merger.Merge(ctx, m.tx, m.onMerge)
+}
+
+// VisitWar implements EntityVisitor.
+func (m txMerger) VisitWar(ctx context.Context, w *War) error {
+	return m.merge(ctx, w)
+}
+
+// VisitCampaign implements EntityVisitor.
+func (m txMerger) VisitCampaign(ctx context.Context, c *Campaign) error {
+	return m.merge(ctx, c)
+}
+
+// VisitEvent implements EntityVisitor.
+func (m txMerger) VisitEvent(ctx context.Context, e *Event) error {
+	return m.merge(ctx, e)
 }
 
-func (c *Client) withTx(ctx context.Context, txFunc func(*gen.Queries, onMergeFunc) error) error {
-	tx, err := c.conn.BeginTx(ctx, pgx.TxOptions{})
+// VisitPlanet implements EntityVisitor.
+func (m txMerger) VisitPlanet(ctx context.Context, p *Planet) error {
+	return m.merge(ctx, p)
+}
+
+// VisitAssignment implements EntityVisitor.
+func (m txMerger) VisitAssignment(ctx context.Context, a *Assignment) error {
+	return m.merge(ctx, a)
+}
+
+// VisitDispatch implements EntityVisitor.
+func (m txMerger) VisitDispatch(ctx context.Context, d *Dispatch) error {
+	return m.merge(ctx, d)
+}
+
+// VisitSnapshot implements EntityVisitor.
+func (m txMerger) VisitSnapshot(ctx context.Context, s *Snapshot) error {
+	return m.merge(ctx, s)
+}
+
+// VisitSnapshotGaps implements EntityVisitor.
+func (m txMerger) VisitSnapshotGaps(ctx context.Context, g *SnapshotGaps) error {
+	return m.merge(ctx, g)
+}
+
+// VisitCampaignLifecycles implements EntityVisitor.
+func (m txMerger) VisitCampaignLifecycles(ctx context.Context, l *CampaignLifecycles) error {
+	return m.merge(ctx, l)
+}
+
+// VisitWarClosure implements EntityVisitor.
+func (m txMerger) VisitWarClosure(ctx context.Context, w *WarClosure) error {
+	return m.merge(ctx, w)
+}
+
+// VisitSyncRun implements EntityVisitor.
+func (m txMerger) VisitSyncRun(ctx context.Context, r *SyncRun) error {
+	return m.merge(ctx, r)
+}
+
+// withTx runs txFunc inside a transaction.
+//
+// The transaction holds a single pool connection for its whole lifetime, so statements
+// cached by pgx for the sqlc queries are reused within it just like with a single connection.
+func (c *Client) withTx(ctx context.Context, collector stats.Collector, txFunc func(*gen.Queries, onMergeFunc) error) error {
+	tx, err := c.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
 
-	// prepare insert/update statistics
-	stats := stats.NewCollector()
 	onMerge := func(table gen.Table, exists bool, affectedRows int64) {
-		collectAfterMerge(stats, table, exists, affectedRows)
+		collectAfterMerge(collector, table, exists, affectedRows)
 	}
 
 	qtx := c.queries.WithTx(tx)
@@ -56,21 +154,20 @@ func (c *Client) withTx(ctx context.Context, txFunc func(*gen.Queries, onMergeFu
 	if err != nil {
 		// roll back on error
 		c.rollback(ctx, tx)
-	} else {
-		// commit when no error
-		if errComm := tx.Commit(ctx); errComm != nil {
-			return fmt.Errorf("failed to commit: %w", errComm)
-		}
-		c.log.Println("changes committed")
-		stats.Print(c.log)
+		return err
+	}
+	// commit when no error
+	if errComm := tx.Commit(ctx); errComm != nil {
+		return fmt.Errorf("failed to commit: %w", errComm)
 	}
+	c.log.InfoContext(ctx, "changes committed")
 	return nil
 }
 
 func (c *Client) rollback(ctx context.Context, tx pgx.Tx) {
-	c.log.Println("error occured during merge, rolling back changes")
+	c.log.WarnContext(ctx, "error occured during merge, rolling back changes")
 	if errRb := tx.Rollback(ctx); errRb != nil {
-		c.log.Printf("failed to rollback: %v", errRb)
+		c.log.ErrorContext(ctx, "failed to rollback", logging.Err(errRb))
 	}
 }
 
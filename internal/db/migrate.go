@@ -3,7 +3,8 @@ package db
 import (
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5" // use pgx as driver
@@ -11,7 +12,7 @@ import (
 )
 
 func (c *Client) newMigration(scriptFolder string) (*migrate.Migrate, error) {
-	cfg := c.conn.Config()
+	cfg := c.pool.Config().ConnConfig
 	uri := fmt.Sprintf("pgx5://%s:%s@%s:%d/%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
 
 	migration, err := migrate.New(
@@ -40,9 +41,13 @@ func (c *Client) MigrateUp(migrationsFolder string) error {
 	return nil
 }
 
-// migrationLogger wraps log.Logger for usage with migrate package
+// migrationLogger wraps slog.Logger for usage with migrate package
 type migrationLogger struct {
-	*log.Logger
+	log *slog.Logger
+}
+
+func (l *migrationLogger) Printf(format string, v ...any) {
+	l.log.Info(strings.TrimSpace(fmt.Sprintf(format, v...)))
 }
 
 func (l *migrationLogger) Verbose() bool {
@@ -38,7 +38,7 @@ var tableNames = []string{
 func TestTablesExist(t *testing.T) {
 	withClientMigrated(t, func(client *Client) {
 		fnTables := func() []string {
-			rows, err := client.conn.Query(
+			rows, err := client.pool.Query(
 				context.Background(),
 				`SELECT table_name FROM information_schema.tables WHERE table_name = any($1);`,
 				tableNames,
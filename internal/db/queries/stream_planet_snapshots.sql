@@ -0,0 +1,29 @@
+-- Queries in this directory are run by package db itself instead of being generated by sqlc,
+-- which reads all rows of :many queries into memory. Their arguments are bound by name using pgx.NamedArgs.
+
+-- name: StreamPlanetSnapshots :many
+SELECT
+    s.create_time, ps.war_id, ps.planet_id, p.name AS planet_name, p.sector, p.max_health, ps.current_owner, ps.health, ps.regen_per_second,
+    ps.attacking_planet_ids,
+    (
+        SELECT COALESCE(jsonb_agg(jsonb_build_object('id', ap.id, 'name', ap.name, 'sector', ap.sector) ORDER BY ap.id), '[]')
+        FROM planets ap
+        WHERE ap.war_id = ps.war_id AND ap.id = ANY(ps.attacking_planet_ids)
+    )::jsonb AS attacking_planets,
+    es.event_id, e.faction AS event_faction, es.health AS event_health, e.max_health AS event_max_health, e.end_time AS event_end_time,
+    st.missions_won::bigint AS missions_won, st.missions_lost::bigint AS missions_lost, st.mission_time::bigint AS mission_time,
+    st.terminid_kills::bigint AS terminid_kills, st.automaton_kills::bigint AS automaton_kills, st.illuminate_kills::bigint AS illuminate_kills,
+    st.bullets_fired::bigint AS bullets_fired, st.bullets_hit::bigint AS bullets_hit, st.time_played::bigint AS time_played,
+    st.deaths::bigint AS deaths, st.revives::bigint AS revives, st.friendlies::bigint AS friendlies, st.player_count::bigint AS player_count
+FROM snapshots s
+JOIN planet_snapshots ps ON ps.id = ANY(s.planet_snapshot_ids)
+JOIN planets p ON p.war_id = ps.war_id AND p.id = ps.planet_id
+JOIN snapshot_statistics st ON st.id = ps.statistics_id
+LEFT JOIN event_snapshots es ON es.id = ps.event_snapshot_id
+LEFT JOIN events e ON e.war_id = es.war_id AND e.id = es.event_id
+WHERE (@since::timestamp IS NULL OR s.create_time >= @since)
+    AND (@until::timestamp IS NULL OR s.create_time < @until)
+    AND (@planet_id::integer IS NULL OR ps.planet_id = @planet_id)
+    AND (@sector::text IS NULL OR p.sector = @sector)
+    AND (@faction::text IS NULL OR ps.current_owner = @faction)
+ORDER BY s.create_time, ps.planet_id;
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+)
+
+// compile-time implementation check
+var _ EntityMerger = (*SnapshotGaps)(nil)
+
+// SnapshotGaps implements EntityMerger.
+//
+// It replaces all gaps starting at or after `Since` with `Gaps`.
+type SnapshotGaps struct {
+	Since pgtype.Timestamp
+	Gaps  []gen.SnapshotGap
+}
+
+// Merge implements EntityMerger.
+func (g *SnapshotGaps) Merge(ctx context.Context, tx *gen.Queries, onMerge onMergeFunc) error {
+	keep := make([]pgtype.Timestamp, len(g.Gaps))
+	for i, gap := range g.Gaps {
+		keep[i] = gap.StartTime
+	}
+	// gaps which have been filled in the meantime
+	if _, err := tx.DeleteSnapshotGapsExcept(ctx, gen.DeleteSnapshotGapsExceptParams{
+		Since: g.Since,
+		Keep:  keep,
+	}); err != nil {
+		return fmt.Errorf("failed to delete obsolete snapshot gaps: %v", err)
+	}
+
+	for _, gap := range g.Gaps {
+		if err := upsert(ctx, gen.TableSnapshotGaps, tx.MergeSnapshotGap, gen.MergeSnapshotGapParams(gap), onMerge); err != nil {
+			return fmt.Errorf("failed to merge snapshot gap at %v: %v", gap.StartTime.Time, err)
+		}
+	}
+	return nil
+}
+
+// SnapshotTimes returns the times of all snapshots since `since` in ascending order.
+func (c *Client) SnapshotTimes(ctx context.Context, since time.Time) ([]time.Time, error) {
+	createTimes, err := c.queries.GetSnapshotTimes(ctx, PGTimestamp(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query snapshot times: %v", err)
+	}
+	times := make([]time.Time, len(createTimes))
+	for i, createTime := range createTimes {
+		times[i] = createTime.Time
+	}
+	return times, nil
+}
@@ -0,0 +1,28 @@
+//go:build integration
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSnapshotTimes(t *testing.T) {
+	base := time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return base.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	withClientMigrated(t, func(client *Client) {
+		mergeSnapshotsAt(t, client, at(0), at(5), at(30), at(60))
+
+		times, err := client.SnapshotTimes(context.Background(), at(1))
+		if err != nil {
+			t.Fatalf("SnapshotTimes() error = %v", err)
+		}
+		if len(times) != 3 || !times[0].Equal(at(5)) || !times[2].Equal(at(60)) {
+			t.Errorf("SnapshotTimes() = %v, want times from %v to %v", times, at(5), at(60))
+		}
+	})
+}
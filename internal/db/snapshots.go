@@ -2,7 +2,11 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 
 	"github.com/stnokott/helldivers-client/internal/db/gen"
 )
@@ -27,18 +31,22 @@ type PlanetSnapshot struct {
 }
 
 // Merge implements EntityMerger.
+//
+// All entities captured by the snapshot are assumed to be part of the war referenced by its war snapshot.
 func (s *Snapshot) Merge(ctx context.Context, tx *gen.Queries, onMerge onMergeFunc) error {
 	warSnapID, err := insertWarSnapshot(ctx, tx, s.WarSnapshot, onMerge)
 	if err != nil {
 		return err
 	}
 
-	assignmentSnapIDs, err := insertAssignmentSnapshots(ctx, tx, s.AssignmentSnapshots, onMerge)
+	warID := s.WarSnapshot.WarID
+
+	assignmentSnapIDs, err := insertAssignmentSnapshots(ctx, tx, warID, s.AssignmentSnapshots, onMerge)
 	if err != nil {
 		return err
 	}
 
-	planetSnapIDs, err := insertPlanetSnapshots(ctx, tx, s.PlanetSnapshots, onMerge)
+	planetSnapIDs, err := insertPlanetSnapshots(ctx, tx, warID, s.PlanetSnapshots, onMerge)
 	if err != nil {
 		return err
 	}
@@ -50,12 +58,14 @@ func (s *Snapshot) Merge(ctx context.Context, tx *gen.Queries, onMerge onMergeFu
 
 	// perform INSERT
 	if _, err = tx.InsertSnapshot(ctx, gen.InsertSnapshotParams{
+		CreateTime:            s.CreateTime,
 		WarSnapshotID:         warSnapID,
 		AssignmentSnapshotIds: assignmentSnapIDs,
 		CampaignIds:           s.CampaignIds,
 		DispatchIds:           s.DispatchIds,
 		PlanetSnapshotIds:     planetSnapIDs,
 		StatisticsID:          statsID,
+		Upstream:              s.Upstream,
 	}); err != nil {
 		return fmt.Errorf("failed to insert snapshot: %v", err)
 	}
@@ -63,6 +73,20 @@ func (s *Snapshot) Merge(ctx context.Context, tx *gen.Queries, onMerge onMergeFu
 	return nil
 }
 
+// LatestSnapshotTime returns the time of the most recent snapshot.
+//
+// `ok` is false if no snapshot exists yet.
+func (c *Client) LatestSnapshotTime(ctx context.Context) (t time.Time, ok bool, err error) {
+	createTime, err := c.queries.GetLatestSnapshotTime(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to query latest snapshot time: %v", err)
+	}
+	return createTime.Time, true, nil
+}
+
 func insertWarSnapshot(ctx context.Context, tx *gen.Queries, warSnap gen.WarSnapshot, onMerge onMergeFunc) (int64, error) {
 	id, err := tx.InsertWarSnapshot(ctx, gen.InsertWarSnapshotParams{
 		WarID:            warSnap.WarID,
@@ -75,12 +99,13 @@ func insertWarSnapshot(ctx context.Context, tx *gen.Queries, warSnap gen.WarSnap
 	return id, nil
 }
 
-func insertAssignmentSnapshots(ctx context.Context, tx *gen.Queries, assignmentSnaps []gen.AssignmentSnapshot, onMerge onMergeFunc) ([]int64, error) {
+func insertAssignmentSnapshots(ctx context.Context, tx *gen.Queries, warID int32, assignmentSnaps []gen.AssignmentSnapshot, onMerge onMergeFunc) ([]int64, error) {
 	ids := make([]int64, len(assignmentSnaps))
 	for i, snap := range assignmentSnaps {
 		id, err := tx.InsertAssignmentSnapshot(ctx, gen.InsertAssignmentSnapshotParams{
 			AssignmentID: snap.AssignmentID,
 			Progress:     snap.Progress,
+			WarID:        warID,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert assignment snapshot: %v", err)
@@ -91,10 +116,10 @@ func insertAssignmentSnapshots(ctx context.Context, tx *gen.Queries, assignmentS
 	return ids, nil
 }
 
-func insertPlanetSnapshots(ctx context.Context, tx *gen.Queries, planetSnaps []PlanetSnapshot, onMerge onMergeFunc) ([]int64, error) {
+func insertPlanetSnapshots(ctx context.Context, tx *gen.Queries, warID int32, planetSnaps []PlanetSnapshot, onMerge onMergeFunc) ([]int64, error) {
 	ids := make([]int64, len(planetSnaps))
 	for i, snap := range planetSnaps {
-		eventSnapID, err := insertEventSnapshot(ctx, tx, snap.Event, onMerge)
+		eventSnapID, err := insertEventSnapshot(ctx, tx, warID, snap.Event, onMerge)
 		if err != nil {
 			return nil, err
 		}
@@ -111,6 +136,7 @@ func insertPlanetSnapshots(ctx context.Context, tx *gen.Queries, planetSnaps []P
 			AttackingPlanetIds: snap.AttackingPlanetIds,
 			RegenPerSecond:     snap.RegenPerSecond,
 			StatisticsID:       statsID,
+			WarID:              warID,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("insert planet snapshot: %w", err)
@@ -121,7 +147,7 @@ func insertPlanetSnapshots(ctx context.Context, tx *gen.Queries, planetSnaps []P
 	return ids, nil
 }
 
-func insertEventSnapshot(ctx context.Context, tx *gen.Queries, eventSnap *gen.EventSnapshot, onMerge onMergeFunc) (*int64, error) {
+func insertEventSnapshot(ctx context.Context, tx *gen.Queries, warID int32, eventSnap *gen.EventSnapshot, onMerge onMergeFunc) (*int64, error) {
 	if eventSnap == nil {
 		// event is optional
 		return nil, nil
@@ -129,6 +155,7 @@ func insertEventSnapshot(ctx context.Context, tx *gen.Queries, eventSnap *gen.Ev
 	id, err := tx.InsertEventSnapshot(ctx, gen.InsertEventSnapshotParams{
 		EventID: eventSnap.EventID,
 		Health:  eventSnap.Health,
+		WarID:   warID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("insert event snapshot: %w", err)
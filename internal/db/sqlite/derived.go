@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	pggen "github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/sqlite/gen"
+)
+
+// mergeCampaignLifecycles starts or extends the lifecycles of all active campaigns and ends all others.
+//
+// Outcomes of ended campaigns are resolved once the planet of the campaign has been captured in a later snapshot.
+func mergeCampaignLifecycles(ctx context.Context, tx *gen.Queries, l *db.CampaignLifecycles, onMerge onMergeFunc) error {
+	warID := int64(l.WarID)
+	seen := timestamp(l.Seen)
+
+	for _, id := range l.CampaignIDs {
+		exists := func(ctx context.Context) (int64, error) {
+			return tx.CampaignLifecycleExists(ctx, gen.CampaignLifecycleExistsParams{WarID: warID, CampaignID: int64(id)})
+		}
+		if err := upsert(ctx, pggen.TableCampaignLifecycles, exists, tx.MergeCampaignLifecycle, gen.MergeCampaignLifecycleParams{
+			WarID:      warID,
+			CampaignID: int64(id),
+			FirstSeen:  seen,
+			LastSeen:   seen,
+		}, onMerge); err != nil {
+			return fmt.Errorf("failed to merge lifecycle of campaign ID=%d: %v", id, err)
+		}
+	}
+
+	activeIDs := l.CampaignIDs
+	if activeIDs == nil {
+		// no campaign is active
+		activeIDs = []int32{}
+	}
+	activeIDsJSON, err := jsonArray(activeIDs)
+	if err != nil {
+		return fmt.Errorf("failed to encode active campaigns: %v", err)
+	}
+	rows, err := tx.EndCampaignLifecycles(ctx, gen.EndCampaignLifecyclesParams{WarID: warID, ActiveIds: activeIDsJSON})
+	if err != nil {
+		return fmt.Errorf("failed to end campaign lifecycles: %v", err)
+	}
+	// bulk updates are only reported if they changed anything, they do not correspond to a single entity
+	if rows > 0 {
+		onMerge(pggen.TableCampaignLifecycles, true, rows)
+	}
+
+	owners := l.PlanetOwners
+	if owners == nil {
+		owners = map[int32]string{}
+	}
+	ownersJSON, err := json.Marshal(owners)
+	if err != nil {
+		return fmt.Errorf("failed to encode planet owners: %v", err)
+	}
+	rows, err = tx.ResolveCampaignOutcomes(ctx, gen.ResolveCampaignOutcomesParams{Owners: string(ownersJSON), WarID: warID})
+	if err != nil {
+		return fmt.Errorf("failed to resolve campaign outcomes: %v", err)
+	}
+	if rows > 0 {
+		onMerge(pggen.TableCampaignLifecycles, true, rows)
+	}
+	return nil
+}
+
+// mergeWarClosure summarizes the snapshots of a war which has ended and stores the final planet ownership.
+func mergeWarClosure(ctx context.Context, tx *gen.Queries, w *db.WarClosure, onMerge onMergeFunc) error {
+	warID := int64(w.WarID)
+
+	// no row is affected if the war does not have any snapshots, nothing to summarize then
+	exists := func(ctx context.Context) (int64, error) {
+		return tx.WarSummaryExists(ctx, warID)
+	}
+	if err := upsert(ctx, pggen.TableWarSummaries, exists, tx.MergeWarSummary, gen.MergeWarSummaryParams{
+		CloseTime: timestamp(w.CloseTime),
+		WarID:     warID,
+	}, onMerge); err != nil {
+		return fmt.Errorf("failed to merge summary of war ID=%d: %v", w.WarID, err)
+	}
+
+	// SQLite cannot return whether an upserted row was inserted, so existing planets are updated separately
+	updated, err := tx.UpdateWarFinalPlanets(ctx, warID)
+	if err != nil {
+		return fmt.Errorf("failed to update final planets of war ID=%d: %v", w.WarID, err)
+	}
+	inserted, err := tx.InsertWarFinalPlanets(ctx, warID)
+	if err != nil {
+		return fmt.Errorf("failed to insert final planets of war ID=%d: %v", w.WarID, err)
+	}
+	if updated == 0 && inserted == 0 {
+		onMerge(pggen.TableWarFinalPlanets, true, 0)
+	}
+	if updated > 0 {
+		onMerge(pggen.TableWarFinalPlanets, true, updated)
+	}
+	if inserted > 0 {
+		onMerge(pggen.TableWarFinalPlanets, false, inserted)
+	}
+	return nil
+}
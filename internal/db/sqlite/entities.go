@@ -0,0 +1,220 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	pggen "github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/sqlite/gen"
+)
+
+func mergeWar(ctx context.Context, tx *gen.Queries, w *db.War, onMerge onMergeFunc) error {
+	factions, err := jsonArray(w.Factions)
+	if err != nil {
+		return fmt.Errorf("failed to encode factions of war (ID=%d): %v", w.ID, err)
+	}
+	exists := func(ctx context.Context) (int64, error) {
+		return tx.WarExists(ctx, int64(w.ID))
+	}
+	if err = upsert(ctx, pggen.TableWars, exists, tx.MergeWar, gen.MergeWarParams{
+		ID:        int64(w.ID),
+		StartTime: timestamp(w.StartTime),
+		EndTime:   timestamp(w.EndTime),
+		Factions:  factions,
+	}, onMerge); err != nil {
+		return fmt.Errorf("failed to merge war (ID=%d): %v", w.ID, err)
+	}
+	return nil
+}
+
+func mergeCampaign(ctx context.Context, tx *gen.Queries, c *db.Campaign, onMerge onMergeFunc) error {
+	count, err := integer(c.Count)
+	if err != nil {
+		return fmt.Errorf("invalid count of campaign (ID=%d): %v", c.ID, err)
+	}
+	exists := func(ctx context.Context) (int64, error) {
+		return tx.CampaignExists(ctx, gen.CampaignExistsParams{WarID: int64(c.WarID), ID: int64(c.ID)})
+	}
+	if err = upsert(ctx, pggen.TableCampaigns, exists, tx.MergeCampaign, gen.MergeCampaignParams{
+		WarID:    int64(c.WarID),
+		ID:       int64(c.ID),
+		Type:     int64(c.Type),
+		Count:    count,
+		PlanetID: optionalID(c.PlanetID),
+	}, onMerge); err != nil {
+		return fmt.Errorf("failed to merge campaign (ID=%d): %v", c.ID, err)
+	}
+	return nil
+}
+
+func mergeEvent(ctx context.Context, tx *gen.Queries, e *db.Event, onMerge onMergeFunc) error {
+	exists := func(ctx context.Context) (int64, error) {
+		return tx.EventExists(ctx, gen.EventExistsParams{WarID: int64(e.WarID), ID: int64(e.ID)})
+	}
+	if err := upsert(ctx, pggen.TableEvents, exists, tx.MergeEvent, gen.MergeEventParams{
+		WarID:      int64(e.WarID),
+		ID:         int64(e.ID),
+		CampaignID: int64(e.CampaignID),
+		Type:       int64(e.Type),
+		Faction:    e.Faction,
+		MaxHealth:  e.MaxHealth,
+		StartTime:  timestamp(e.StartTime),
+		EndTime:    timestamp(e.EndTime),
+	}, onMerge); err != nil {
+		return fmt.Errorf("failed to merge event (ID=%d): %v", e.ID, err)
+	}
+	return nil
+}
+
+func mergePlanet(ctx context.Context, tx *gen.Queries, p *db.Planet, onMerge onMergeFunc) error {
+	biomeExists := func(ctx context.Context) (int64, error) {
+		return tx.BiomeExists(ctx, p.Biome.Name)
+	}
+	if err := upsert(ctx, pggen.TableBiomes, biomeExists, tx.MergeBiome, gen.MergeBiomeParams(p.Biome), onMerge); err != nil {
+		return fmt.Errorf("failed to merge biome '%s': %v", p.Biome.Name, err)
+	}
+	for _, hazard := range p.Hazards {
+		hazardExists := func(ctx context.Context) (int64, error) {
+			return tx.HazardExists(ctx, hazard.Name)
+		}
+		if err := upsert(ctx, pggen.TableHazards, hazardExists, tx.MergeHazard, gen.MergeHazardParams(hazard), onMerge); err != nil {
+			return fmt.Errorf("failed to merge hazard '%s': %v", hazard.Name, err)
+		}
+	}
+
+	position, err := jsonArray(p.Position)
+	if err != nil {
+		return fmt.Errorf("failed to encode position of planet '%s': %v", p.Name, err)
+	}
+	waypointIDs, err := jsonArray(p.WaypointIds)
+	if err != nil {
+		return fmt.Errorf("failed to encode waypoints of planet '%s': %v", p.Name, err)
+	}
+	planetExists := func(ctx context.Context) (int64, error) {
+		return tx.PlanetExists(ctx, gen.PlanetExistsParams{WarID: int64(p.WarID), ID: int64(p.ID)})
+	}
+	if err = upsert(ctx, pggen.TablePlanets, planetExists, tx.MergePlanet, gen.MergePlanetParams{
+		WarID:        int64(p.WarID),
+		ID:           int64(p.ID),
+		Name:         p.Name,
+		Sector:       p.Sector,
+		Position:     position,
+		WaypointIds:  waypointIDs,
+		Disabled:     p.Disabled,
+		BiomeName:    p.Biome.Name,
+		MaxHealth:    p.MaxHealth,
+		InitialOwner: p.InitialOwner,
+	}, onMerge); err != nil {
+		return fmt.Errorf("failed to merge planet '%s': %v", p.Name, err)
+	}
+
+	// hazards are stored in a join table instead of an array, which is replaced as a whole
+	if err = tx.DeletePlanetHazards(ctx, gen.DeletePlanetHazardsParams{WarID: int64(p.WarID), PlanetID: int64(p.ID)}); err != nil {
+		return fmt.Errorf("failed to delete hazards of planet '%s': %v", p.Name, err)
+	}
+	for _, hazard := range p.Hazards {
+		if err = tx.InsertPlanetHazard(ctx, gen.InsertPlanetHazardParams{
+			WarID:      int64(p.WarID),
+			PlanetID:   int64(p.ID),
+			HazardName: hazard.Name,
+		}); err != nil {
+			return fmt.Errorf("failed to insert hazard '%s' of planet '%s': %v", hazard.Name, p.Name, err)
+		}
+	}
+	return nil
+}
+
+func mergeAssignment(ctx context.Context, tx *gen.Queries, a *db.Assignment, onMerge onMergeFunc) error {
+	rewardAmount, err := integer(a.RewardAmount)
+	if err != nil {
+		return fmt.Errorf("invalid reward amount of assignment (ID=%d): %v", a.ID, err)
+	}
+	// existence is also required for the task statistics
+	exists, err := tx.AssignmentExists(ctx, gen.AssignmentExistsParams{WarID: int64(a.WarID), ID: a.ID})
+	if err != nil {
+		return fmt.Errorf("check if assignment (ID=%d) exists: %w", a.ID, err)
+	}
+	assignmentExists := func(context.Context) (int64, error) {
+		return exists, nil
+	}
+	if err = upsert(ctx, pggen.TableAssignments, assignmentExists, tx.MergeAssignment, gen.MergeAssignmentParams{
+		WarID:        int64(a.WarID),
+		ID:           a.ID,
+		Title:        a.Title,
+		Briefing:     a.Briefing,
+		Description:  a.Description,
+		Expiration:   timestamp(a.Expiration),
+		RewardType:   int64(a.RewardType),
+		RewardAmount: rewardAmount,
+	}, onMerge); err != nil {
+		return fmt.Errorf("insert assignment '%s': %v", a.Title, err)
+	}
+
+	// tasks are identified by their position, they are replaced as a whole like in PostgreSQL
+	tasks := make([]gen.InsertAssignmentTaskParams, len(a.Tasks))
+	for i, task := range a.Tasks {
+		values, err := jsonArray(task.Values)
+		if err != nil {
+			return fmt.Errorf("failed to encode values of assignment task %d: %v", i, err)
+		}
+		valueTypes, err := jsonArray(task.ValueTypes)
+		if err != nil {
+			return fmt.Errorf("failed to encode value types of assignment task %d: %v", i, err)
+		}
+		tasks[i] = gen.InsertAssignmentTaskParams{
+			WarID:        int64(a.WarID),
+			AssignmentID: a.ID,
+			Position:     int64(i),
+			TaskType:     int64(task.TaskType),
+			TaskValues:   values,
+			ValueTypes:   valueTypes,
+		}
+	}
+	stored, err := tx.GetAssignmentTasks(ctx, gen.GetAssignmentTasksParams{WarID: int64(a.WarID), AssignmentID: a.ID})
+	if err != nil {
+		return fmt.Errorf("get tasks of assignment (ID=%d): %w", a.ID, err)
+	}
+	if assignmentTasksEqual(stored, tasks) {
+		onMerge(pggen.TableAssignmentTasks, true, 0)
+		return nil
+	}
+	if err = tx.DeleteAssignmentTasks(ctx, gen.DeleteAssignmentTasksParams{WarID: int64(a.WarID), AssignmentID: a.ID}); err != nil {
+		return fmt.Errorf("delete assignment tasks: %w", err)
+	}
+	for i, task := range tasks {
+		if err = tx.InsertAssignmentTask(ctx, task); err != nil {
+			return fmt.Errorf("failed to insert assignment task %d: %v", i, err)
+		}
+	}
+	onMerge(pggen.TableAssignmentTasks, exists != 0, int64(len(tasks)))
+	return nil
+}
+
+// assignmentTasksEqual reports whether the stored tasks of an assignment equal `tasks`, in order.
+func assignmentTasksEqual(stored []gen.AssignmentTask, tasks []gen.InsertAssignmentTaskParams) bool {
+	if len(stored) != len(tasks) {
+		return false
+	}
+	for i, task := range tasks {
+		if gen.InsertAssignmentTaskParams(stored[i]) != task {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeDispatch(ctx context.Context, tx *gen.Queries, d *db.Dispatch, onMerge onMergeFunc) error {
+	exists := func(ctx context.Context) (int64, error) {
+		return tx.DispatchExists(ctx, int64(d.ID))
+	}
+	if err := upsert(ctx, pggen.TableDispatches, exists, tx.MergeDispatch, gen.MergeDispatchParams{
+		ID:         int64(d.ID),
+		CreateTime: timestamp(d.CreateTime),
+		Type:       int64(d.Type),
+		Message:    d.Message,
+	}, onMerge); err != nil {
+		return fmt.Errorf("failed to merge dispatch (ID=%d): %v", d.ID, err)
+	}
+	return nil
+}
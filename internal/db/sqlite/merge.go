@@ -0,0 +1,206 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	pggen "github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/sqlite/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+	"github.com/stnokott/helldivers-client/internal/logging"
+)
+
+type onMergeFunc func(table pggen.Table, exists bool, affectedRows int64)
+
+// existsFunc checks whether the row to be merged already exists.
+type existsFunc func(ctx context.Context) (int64, error)
+
+// upsertFunc is a query which inserts or updates a single row, returning the number of affected rows.
+//
+// If the existing row is left unchanged, no row is affected.
+type upsertFunc[P any] func(ctx context.Context, arg P) (int64, error)
+
+// upsert runs `query` with `arg` and reports the result for `table` to `onMerge`.
+//
+// Unlike PostgreSQL, SQLite cannot tell whether an upsert inserted or updated the row,
+// so `exists` is queried beforehand.
+// This is cheap since the database is embedded.
+func upsert[P any](ctx context.Context, table pggen.Table, exists existsFunc, query upsertFunc[P], arg P, onMerge onMergeFunc) error {
+	existing, err := exists(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := query(ctx, arg)
+	if err != nil {
+		return err
+	}
+	onMerge(table, existing != 0, rows)
+	return nil
+}
+
+// Merge attempts to merge each `Entity` to the database.
+//
+// Insert/update statistics are added to `collector`.
+func (c *Client) Merge(ctx context.Context, collector stats.Collector, entities ...[]db.Entity) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	onMerge := func(table pggen.Table, exists bool, affectedRows int64) {
+		collectAfterMerge(collector, table, exists, affectedRows)
+	}
+
+	v := txMerger{tx: c.queries.WithTx(tx), onMerge: onMerge}
+
+	for _, eSlice := range entities {
+		if len(eSlice) == 0 {
+			c.log.WarnContext(ctx, "got 0 entities to merge")
+		}
+		for _, entity := range eSlice {
+			if err = entity.Accept(ctx, v); err != nil {
+				c.rollback(ctx, tx)
+				return err
+			}
+		}
+	}
+	if errComm := tx.Commit(); errComm != nil {
+		return fmt.Errorf("failed to commit: %w", errComm)
+	}
+	c.log.InfoContext(ctx, "changes committed")
+	return nil
+}
+
+func (c *Client) rollback(ctx context.Context, tx *sql.Tx) {
+	c.log.WarnContext(ctx, "error occured during merge, rolling back changes")
+	if errRb := tx.Rollback(); errRb != nil {
+		c.log.ErrorContext(ctx, "failed to rollback", logging.Err(errRb))
+	}
+}
+
+// compile-time implementation check
+var _ db.EntityVisitor = txMerger{}
+
+// txMerger merges every type of entity within the transaction `tx`.
+//
+// The entities of package db only know how to merge themselves into PostgreSQL,
+// so they are converted to the SQLite schema here.
+type txMerger struct {
+	tx      *gen.Queries
+	onMerge onMergeFunc
+}
+
+// VisitWar implements db.EntityVisitor.
+func (m txMerger) VisitWar(ctx context.Context, w *db.War) error {
+	return mergeWar(ctx, m.tx, w, m.onMerge)
+}
+
+// VisitCampaign implements db.EntityVisitor.
+func (m txMerger) VisitCampaign(ctx context.Context, c *db.Campaign) error {
+	return mergeCampaign(ctx, m.tx, c, m.onMerge)
+}
+
+// VisitEvent implements db.EntityVisitor.
+func (m txMerger) VisitEvent(ctx context.Context, e *db.Event) error {
+	return mergeEvent(ctx, m.tx, e, m.onMerge)
+}
+
+// VisitPlanet implements db.EntityVisitor.
+func (m txMerger) VisitPlanet(ctx context.Context, p *db.Planet) error {
+	return mergePlanet(ctx, m.tx, p, m.onMerge)
+}
+
+// VisitAssignment implements db.EntityVisitor.
+func (m txMerger) VisitAssignment(ctx context.Context, a *db.Assignment) error {
+	return mergeAssignment(ctx, m.tx, a, m.onMerge)
+}
+
+// VisitDispatch implements db.EntityVisitor.
+func (m txMerger) VisitDispatch(ctx context.Context, d *db.Dispatch) error {
+	return mergeDispatch(ctx, m.tx, d, m.onMerge)
+}
+
+// VisitSnapshot implements db.EntityVisitor.
+func (m txMerger) VisitSnapshot(ctx context.Context, s *db.Snapshot) error {
+	return insertSnapshot(ctx, m.tx, s, m.onMerge)
+}
+
+// VisitSnapshotGaps implements db.EntityVisitor.
+func (m txMerger) VisitSnapshotGaps(ctx context.Context, g *db.SnapshotGaps) error {
+	return mergeSnapshotGaps(ctx, m.tx, g, m.onMerge)
+}
+
+// VisitCampaignLifecycles implements db.EntityVisitor.
+func (m txMerger) VisitCampaignLifecycles(ctx context.Context, l *db.CampaignLifecycles) error {
+	return mergeCampaignLifecycles(ctx, m.tx, l, m.onMerge)
+}
+
+// VisitWarClosure implements db.EntityVisitor.
+func (m txMerger) VisitWarClosure(ctx context.Context, w *db.WarClosure) error {
+	return mergeWarClosure(ctx, m.tx, w, m.onMerge)
+}
+
+// VisitSyncRun implements db.EntityVisitor.
+func (m txMerger) VisitSyncRun(ctx context.Context, r *db.SyncRun) error {
+	return mergeSyncRun(ctx, m.tx, r, m.onMerge)
+}
+
+func collectAfterMerge(s stats.Collector, table pggen.Table, exists bool, affectedRows int64) {
+	if affectedRows == 0 {
+		s.Noop(table, 1)
+		return
+	}
+	if exists {
+		s.Updated(table, affectedRows)
+	} else {
+		s.Inserted(table, affectedRows)
+	}
+}
+
+// timestamp converts `t` to UTC, so that timestamps stored as text compare in chronological order.
+func timestamp(t pgtype.Timestamp) time.Time {
+	return t.Time.UTC()
+}
+
+// timestampText formats `t` the same way the driver stores timestamps, for comparison inside of JSON arrays.
+func timestampText(t pgtype.Timestamp) string {
+	return timestamp(t).Format(timestampFormat)
+}
+
+// integer converts a numeric to an integer, failing if it does not fit into SQLite's 64-bit integers.
+func integer(n pgtype.Numeric) (int64, error) {
+	i, err := n.Int64Value()
+	if err != nil {
+		return 0, err
+	}
+	if !i.Valid {
+		return 0, fmt.Errorf("numeric %v is not a valid integer", n)
+	}
+	return i.Int64, nil
+}
+
+// jsonArray encodes `values` as JSON array, which is how SQLite stores arrays of values.
+//
+// Like PostgreSQL stores a nil slice as NULL, it is encoded as null, which the schema rejects for required arrays.
+func jsonArray[T any](values []T) (string, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// optionalID converts an optional ID to SQLite's integer type.
+func optionalID(id *int32) *int64 {
+	if id == nil {
+		return nil
+	}
+	i := int64(*id)
+	return &i
+}
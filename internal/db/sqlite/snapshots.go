@@ -0,0 +1,207 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	pggen "github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/sqlite/gen"
+)
+
+// insertSnapshot inserts a snapshot with all of its parts.
+//
+// All entities captured by the snapshot are assumed to be part of the war referenced by its war snapshot.
+func insertSnapshot(ctx context.Context, tx *gen.Queries, s *db.Snapshot, onMerge onMergeFunc) error {
+	createTime := timestamp(s.CreateTime)
+	warID := int64(s.WarSnapshot.WarID)
+
+	warSnapID, err := tx.InsertWarSnapshot(ctx, gen.InsertWarSnapshotParams{
+		WarID:            warID,
+		ImpactMultiplier: s.WarSnapshot.ImpactMultiplier,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert war snapshot: %v", err)
+	}
+	onMerge(pggen.TableWarSnapshots, false, 1)
+
+	statsID, err := insertSnapshotStatistics(ctx, tx, s.Statistics, onMerge)
+	if err != nil {
+		return err
+	}
+
+	// the snapshot is inserted first, since the join tables reference it
+	if err = tx.InsertSnapshot(ctx, gen.InsertSnapshotParams{
+		CreateTime:    createTime,
+		WarSnapshotID: warSnapID,
+		StatisticsID:  statsID,
+		Upstream:      s.Upstream,
+	}); err != nil {
+		return fmt.Errorf("failed to insert snapshot: %v", err)
+	}
+
+	for _, snap := range s.AssignmentSnapshots {
+		progress, err := jsonArray(snap.Progress)
+		if err != nil {
+			return fmt.Errorf("failed to encode progress of assignment (ID=%d): %v", snap.AssignmentID, err)
+		}
+		id, err := tx.InsertAssignmentSnapshot(ctx, gen.InsertAssignmentSnapshotParams{
+			WarID:        warID,
+			AssignmentID: snap.AssignmentID,
+			Progress:     progress,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert assignment snapshot: %v", err)
+		}
+		onMerge(pggen.TableAssignmentSnapshots, false, 1)
+		if err = tx.InsertSnapshotAssignment(ctx, gen.InsertSnapshotAssignmentParams{CreateTime: createTime, AssignmentSnapshotID: id}); err != nil {
+			return fmt.Errorf("failed to link assignment snapshot: %v", err)
+		}
+	}
+
+	for _, snap := range s.PlanetSnapshots {
+		id, err := insertPlanetSnapshot(ctx, tx, warID, snap, onMerge)
+		if err != nil {
+			return err
+		}
+		if err = tx.InsertSnapshotPlanet(ctx, gen.InsertSnapshotPlanetParams{CreateTime: createTime, PlanetSnapshotID: id}); err != nil {
+			return fmt.Errorf("failed to link planet snapshot: %v", err)
+		}
+	}
+
+	for _, campaignID := range s.CampaignIds {
+		if err = tx.InsertSnapshotCampaign(ctx, gen.InsertSnapshotCampaignParams{
+			CreateTime: createTime,
+			WarID:      warID,
+			CampaignID: int64(campaignID),
+		}); err != nil {
+			return fmt.Errorf("failed to link campaign (ID=%d): %v", campaignID, err)
+		}
+	}
+
+	for _, dispatchID := range s.DispatchIds {
+		if err = tx.InsertSnapshotDispatch(ctx, gen.InsertSnapshotDispatchParams{CreateTime: createTime, DispatchID: int64(dispatchID)}); err != nil {
+			return fmt.Errorf("failed to link dispatch (ID=%d): %v", dispatchID, err)
+		}
+	}
+
+	onMerge(pggen.TableSnapshots, false, 1)
+	return nil
+}
+
+func insertPlanetSnapshot(ctx context.Context, tx *gen.Queries, warID int64, snap db.PlanetSnapshot, onMerge onMergeFunc) (int64, error) {
+	var eventSnapID *int64
+	if snap.Event != nil {
+		id, err := tx.InsertEventSnapshot(ctx, gen.InsertEventSnapshotParams{
+			WarID:   warID,
+			EventID: int64(snap.Event.EventID),
+			Health:  snap.Event.Health,
+		})
+		if err != nil {
+			return -1, fmt.Errorf("insert event snapshot: %w", err)
+		}
+		onMerge(pggen.TableEventSnapshots, false, 1)
+		eventSnapID = &id
+	}
+
+	statsID, err := insertSnapshotStatistics(ctx, tx, snap.Statistics, onMerge)
+	if err != nil {
+		return -1, err
+	}
+
+	attackingPlanetIDs, err := jsonArray(snap.AttackingPlanetIds)
+	if err != nil {
+		return -1, fmt.Errorf("failed to encode attacking planets of planet (ID=%d): %v", snap.PlanetID, err)
+	}
+	id, err := tx.InsertPlanetSnapshot(ctx, gen.InsertPlanetSnapshotParams{
+		WarID:              warID,
+		PlanetID:           int64(snap.PlanetID),
+		Health:             snap.Health,
+		CurrentOwner:       snap.CurrentOwner,
+		EventSnapshotID:    eventSnapID,
+		AttackingPlanetIds: attackingPlanetIDs,
+		RegenPerSecond:     snap.RegenPerSecond,
+		StatisticsID:       statsID,
+	})
+	if err != nil {
+		return -1, fmt.Errorf("insert planet snapshot: %w", err)
+	}
+	onMerge(pggen.TablePlanetSnapshots, false, 1)
+	return id, nil
+}
+
+func insertSnapshotStatistics(ctx context.Context, tx *gen.Queries, snapshotStats pggen.SnapshotStatistic, onMerge onMergeFunc) (int64, error) {
+	var conv integerConverter
+	params := gen.InsertSnapshotStatisticsParams{
+		MissionsWon:     conv.integer(snapshotStats.MissionsWon),
+		MissionsLost:    conv.integer(snapshotStats.MissionsLost),
+		MissionTime:     conv.integer(snapshotStats.MissionTime),
+		TerminidKills:   conv.integer(snapshotStats.TerminidKills),
+		AutomatonKills:  conv.integer(snapshotStats.AutomatonKills),
+		IlluminateKills: conv.integer(snapshotStats.IlluminateKills),
+		BulletsFired:    conv.integer(snapshotStats.BulletsFired),
+		BulletsHit:      conv.integer(snapshotStats.BulletsHit),
+		TimePlayed:      conv.integer(snapshotStats.TimePlayed),
+		Deaths:          conv.integer(snapshotStats.Deaths),
+		Revives:         conv.integer(snapshotStats.Revives),
+		Friendlies:      conv.integer(snapshotStats.Friendlies),
+		PlayerCount:     conv.integer(snapshotStats.PlayerCount),
+	}
+	if conv.err != nil {
+		return -1, fmt.Errorf("invalid snapshot statistics: %w", conv.err)
+	}
+	id, err := tx.InsertSnapshotStatistics(ctx, params)
+	if err != nil {
+		return -1, fmt.Errorf("insert snapshot statistics: %w", err)
+	}
+	onMerge(pggen.TableSnapshotStatistics, false, 1)
+	return id, nil
+}
+
+// integerConverter converts multiple numerics to integers, keeping the first error.
+type integerConverter struct {
+	err error
+}
+
+func (c *integerConverter) integer(n pgtype.Numeric) int64 {
+	i, err := integer(n)
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	return i
+}
+
+// mergeSnapshotGaps replaces all gaps starting at or after `g.Since` with `g.Gaps`.
+func mergeSnapshotGaps(ctx context.Context, tx *gen.Queries, g *db.SnapshotGaps, onMerge onMergeFunc) error {
+	keep := make([]string, len(g.Gaps))
+	for i, gap := range g.Gaps {
+		keep[i] = timestampText(gap.StartTime)
+	}
+	keepJSON, err := jsonArray(keep)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot gaps: %v", err)
+	}
+	// gaps which have been filled in the meantime
+	if _, err = tx.DeleteSnapshotGapsExcept(ctx, gen.DeleteSnapshotGapsExceptParams{
+		Since: timestamp(g.Since),
+		Keep:  keepJSON,
+	}); err != nil {
+		return fmt.Errorf("failed to delete obsolete snapshot gaps: %v", err)
+	}
+
+	for _, gap := range g.Gaps {
+		exists := func(ctx context.Context) (int64, error) {
+			return tx.SnapshotGapExists(ctx, timestamp(gap.StartTime))
+		}
+		if err = upsert(ctx, pggen.TableSnapshotGaps, exists, tx.MergeSnapshotGap, gen.MergeSnapshotGapParams{
+			StartTime:  timestamp(gap.StartTime),
+			EndTime:    timestamp(gap.EndTime),
+			MissedRuns: int64(gap.MissedRuns),
+		}, onMerge); err != nil {
+			return fmt.Errorf("failed to merge snapshot gap at %v: %v", gap.StartTime.Time, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Package sqlite stores the synced data in a single SQLite file, for deployments without a PostgreSQL server.
+//
+// It accepts the same entities as package db and mirrors its schema as closely as SQLite allows,
+// see scripts/sqlite/migrations for the differences.
+// Slowly changing entities are not historized.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file" // load migrations from file
+	"github.com/jackc/pgx/v5/pgtype"
+	_ "modernc.org/sqlite" // register driver, implemented in pure Go so that no cgo is required
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	pggen "github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/sqlite/gen"
+)
+
+// Client is the abstraction layer for the SQLite database.
+type Client struct {
+	db      *sql.DB
+	queries *gen.Queries
+	log     *slog.Logger
+}
+
+// timestampFormat is the format timestamps are stored in, as selected by "_time_format=sqlite".
+//
+// Unlike the default format of the driver, it is understood by the date and time functions of SQLite
+// and sorts in chronological order for timestamps in UTC.
+const timestampFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// New opens the SQLite database at `dsn`, creating it if it does not exist.
+//
+// `dsn` is either a file path or a URI as accepted by modernc.org/sqlite, e.g. "file:helldivers2.db?_pragma=journal_mode(WAL)".
+// Foreign keys are always enforced and timestamps are always written in timestampFormat.
+func New(dsn string, logger *slog.Logger) (*Client, error) {
+	for _, param := range []string{"_pragma=foreign_keys(1)", "_time_format=sqlite"} {
+		if strings.Contains(dsn, param) {
+			continue
+		}
+		if strings.Contains(dsn, "?") {
+			dsn += "&" + param
+		} else {
+			dsn += "?" + param
+		}
+	}
+	database, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	// SQLite only allows a single writer, concurrent connections would fail with SQLITE_BUSY
+	database.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err = database.PingContext(ctx); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	logger.Info("opened database", "dsn", dsn)
+
+	return &Client{
+		db:      database,
+		queries: gen.New(database),
+		log:     logger,
+	}, nil
+}
+
+// Disconnect closes the database.
+func (c *Client) Disconnect() error {
+	if err := c.db.Close(); err != nil {
+		return err
+	}
+	c.log.Info("disconnected")
+	return nil
+}
+
+// MigrateUp runs required migrations to get to the latest version.
+//
+// This should be run before any other operations.
+func (c *Client) MigrateUp(migrationsFolder string) error {
+	// closing the driver would close c.db, so it is left open
+	driver, err := migratesqlite.WithInstance(c.db, &migratesqlite.Config{})
+	if err != nil {
+		return err
+	}
+	migration, err := migrate.NewWithDatabaseInstance("file://"+migrationsFolder, "sqlite", driver)
+	if err != nil {
+		return err
+	}
+	migration.Log = &migrationLogger{c.log}
+	if err = migration.Up(); !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// migrationLogger wraps slog.Logger for usage with migrate package
+type migrationLogger struct {
+	log *slog.Logger
+}
+
+func (l *migrationLogger) Printf(format string, v ...any) {
+	l.log.Info(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (l *migrationLogger) Verbose() bool {
+	return false
+}
+
+// LatestSnapshotTime returns the time of the most recent snapshot.
+//
+// `ok` is false if no snapshot exists yet.
+func (c *Client) LatestSnapshotTime(ctx context.Context) (t time.Time, ok bool, err error) {
+	latest, err := c.queries.GetLatestSnapshot(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to query latest snapshot time: %v", err)
+	}
+	return latest.CreateTime, true, nil
+}
+
+// LatestWarID returns the ID of the war of the most recent snapshot.
+//
+// `ok` is false if no snapshot exists yet.
+func (c *Client) LatestWarID(ctx context.Context) (id int32, ok bool, err error) {
+	latest, err := c.queries.GetLatestSnapshot(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to query war of latest snapshot: %v", err)
+	}
+	return int32(latest.WarID), true, nil
+}
+
+// WarSummary returns the summary of a closed war.
+//
+// `ok` is false if the war has not been closed or had no snapshots.
+func (c *Client) WarSummary(ctx context.Context, warID int32) (summary pggen.WarSummary, ok bool, err error) {
+	s, err := c.queries.GetWarSummary(ctx, int64(warID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return pggen.WarSummary{}, false, nil
+	}
+	if err != nil {
+		return pggen.WarSummary{}, false, fmt.Errorf("failed to query summary of war ID=%d: %v", warID, err)
+	}
+	return pggen.WarSummary{
+		WarID:             int32(s.WarID),
+		CloseTime:         db.PGTimestamp(s.CloseTime),
+		FirstSnapshotTime: db.PGTimestamp(s.FirstSnapshotTime),
+		FinalSnapshotTime: db.PGTimestamp(s.FinalSnapshotTime),
+		SnapshotCount:     s.SnapshotCount,
+		FinalStatisticsID: s.FinalStatisticsID,
+		PeakPlayerCount:   pgtype.Numeric{Int: big.NewInt(s.PeakPlayerCount), Valid: true},
+	}, true, nil
+}
+
+// CampaignLifecycles returns the lifecycles of all campaigns of war `warID` in the order they started,
+// together with the planets of the campaigns.
+func (c *Client) CampaignLifecycles(ctx context.Context, warID int32) ([]pggen.GetCampaignLifecyclesRow, error) {
+	rows, err := c.queries.GetCampaignLifecycles(ctx, int64(warID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query campaign lifecycles: %v", err)
+	}
+	lifecycles := make([]pggen.GetCampaignLifecyclesRow, len(rows))
+	for i, row := range rows {
+		lifecycles[i] = pggen.GetCampaignLifecyclesRow{
+			WarID:      int32(row.WarID),
+			CampaignID: int32(row.CampaignID),
+			FirstSeen:  db.PGTimestamp(row.FirstSeen),
+			LastSeen:   db.PGTimestamp(row.LastSeen),
+			Ended:      row.Ended,
+			Outcome:    row.Outcome,
+		}
+		if row.PlanetID != nil {
+			planetID := int32(*row.PlanetID)
+			lifecycles[i].PlanetID = &planetID
+		}
+	}
+	return lifecycles, nil
+}
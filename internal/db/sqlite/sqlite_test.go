@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/dbtest"
+	pggen "github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/logging"
+)
+
+// newMigratedClient opens a new database in a temporary directory and migrates it to the latest version.
+func newMigratedClient(t *testing.T) *Client {
+	t.Helper()
+	client, err := New(filepath.Join(t.TempDir(), "test.db"), logging.Discard())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := client.Disconnect(); err != nil {
+			t.Logf("failed to disconnect: %v", err)
+		}
+	})
+	if err = client.MigrateUp("../../../scripts/sqlite/migrations"); err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+	return client
+}
+
+// The readers below complete dbtest.Store for the tables without client methods.
+
+// SnapshotGaps returns the gaps starting at or after `since`.
+func (c *Client) SnapshotGaps(ctx context.Context, since time.Time) ([]pggen.SnapshotGap, error) {
+	rows, err := c.queries.GetSnapshotGaps(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	gaps := make([]pggen.SnapshotGap, len(rows))
+	for i, row := range rows {
+		gaps[i] = pggen.SnapshotGap{
+			StartTime:  db.PGTimestamp(row.StartTime),
+			EndTime:    db.PGTimestamp(row.EndTime),
+			MissedRuns: int32(row.MissedRuns),
+		}
+	}
+	return gaps, nil
+}
+
+// WarFinalPlanets returns the final owners of the planets of a closed war.
+func (c *Client) WarFinalPlanets(ctx context.Context, warID int32) ([]pggen.WarFinalPlanet, error) {
+	rows, err := c.queries.GetWarFinalPlanets(ctx, int64(warID))
+	if err != nil {
+		return nil, err
+	}
+	planets := make([]pggen.WarFinalPlanet, len(rows))
+	for i, row := range rows {
+		planets[i] = pggen.WarFinalPlanet{WarID: int32(row.WarID), PlanetID: int32(row.PlanetID), Owner: row.Owner}
+	}
+	return planets, nil
+}
+
+// SyncRun returns the sync run with ID `id`.
+func (c *Client) SyncRun(ctx context.Context, id pgtype.UUID) (pggen.SyncRun, error) {
+	row, err := c.queries.GetSyncRun(ctx, uuid.UUID(id.Bytes).String())
+	if err != nil {
+		return pggen.SyncRun{}, err
+	}
+	run := pggen.SyncRun{
+		ID:          id,
+		Job:         row.Job,
+		StartTime:   db.PGTimestamp(row.StartTime),
+		EndTime:     db.PGTimestamp(row.EndTime),
+		Outcome:     row.Outcome,
+		Endpoints:   []byte(row.Endpoints),
+		MergeCounts: []byte(row.MergeCounts),
+		Error:       row.Error,
+	}
+	if row.SnapshotCreateTime != nil {
+		run.SnapshotCreateTime = db.PGTimestamp(*row.SnapshotCreateTime)
+	}
+	return run, nil
+}
+
+// backend runs the tests of package dbtest against SQLite.
+var backend = dbtest.Backend{
+	New: func(t *testing.T) dbtest.Store {
+		return newMigratedClient(t)
+	},
+	// integers are signed 64-bit
+	MaxCount: math.MaxInt64,
+}
+
+func TestMergeStats(t *testing.T) {
+	dbtest.MergeStats(t, backend)
+}
+
+func TestSchema(t *testing.T) {
+	dbtest.Schema(t, backend)
+}
+
+func TestWars(t *testing.T) {
+	dbtest.Wars(t, backend)
+}
+
+func TestPlanets(t *testing.T) {
+	dbtest.Planets(t, backend)
+}
+
+func TestCampaigns(t *testing.T) {
+	dbtest.Campaigns(t, backend)
+}
+
+func TestEvents(t *testing.T) {
+	dbtest.Events(t, backend)
+}
+
+func TestAssignments(t *testing.T) {
+	dbtest.Assignments(t, backend)
+}
+
+func TestDispatches(t *testing.T) {
+	dbtest.Dispatches(t, backend)
+}
+
+func TestSnapshots(t *testing.T) {
+	dbtest.Snapshots(t, backend)
+}
+
+func TestSnapshotGaps(t *testing.T) {
+	dbtest.SnapshotGaps(t, backend)
+}
+
+func TestCampaignLifecycles(t *testing.T) {
+	dbtest.CampaignLifecycles(t, backend)
+}
+
+func TestWarClosure(t *testing.T) {
+	dbtest.WarClosure(t, backend)
+}
+
+func TestSyncRuns(t *testing.T) {
+	dbtest.SyncRuns(t, backend)
+}
@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	pggen "github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/sqlite/gen"
+)
+
+// mergeSyncRun records a sync run in the ledger, the JSON columns are stored as text.
+func mergeSyncRun(ctx context.Context, tx *gen.Queries, r *db.SyncRun, onMerge onMergeFunc) error {
+	id := uuid.UUID(r.ID.Bytes).String()
+	endpoints, mergeCounts, err := r.EncodeJSON()
+	if err != nil {
+		return fmt.Errorf("failed to merge sync run (ID=%s): %v", id, err)
+	}
+	var snapshotTime *time.Time
+	if r.SnapshotCreateTime.Valid {
+		t := timestamp(r.SnapshotCreateTime)
+		snapshotTime = &t
+	}
+
+	exists := func(ctx context.Context) (int64, error) {
+		return tx.SyncRunExists(ctx, id)
+	}
+	if err = upsert(ctx, pggen.TableSyncRuns, exists, tx.MergeSyncRun, gen.MergeSyncRunParams{
+		ID:                 id,
+		Job:                r.Job,
+		StartTime:          timestamp(r.StartTime),
+		EndTime:            timestamp(r.EndTime),
+		Outcome:            r.Outcome,
+		Endpoints:          string(endpoints),
+		MergeCounts:        string(mergeCounts),
+		Error:              r.Error,
+		SnapshotCreateTime: snapshotTime,
+	}, onMerge); err != nil {
+		return fmt.Errorf("failed to merge sync run (ID=%s): %v", id, err)
+	}
+	return nil
+}
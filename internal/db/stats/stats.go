@@ -2,11 +2,14 @@
 package stats
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"strconv"
-	"strings"
 
 	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/logging"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 )
@@ -18,6 +21,8 @@ type tblMergeStats struct {
 	Inserted int64
 	Updated  int64
 	Noop     int64
+	// SourceUnchanged is true if merging was skipped since the API data did not change
+	SourceUnchanged bool
 }
 
 // NewCollector creates a new mergeStats instance.
@@ -44,6 +49,11 @@ func (c Collector) Noop(table gen.Table, n int64) {
 	c[table].Noop += n
 }
 
+// SourceUnchanged marks `table` as skipped since its source data did not change.
+func (c Collector) SourceUnchanged(table gen.Table) {
+	c[table].SourceUnchanged = true
+}
+
 func statOrNan(x int64) string {
 	if x > 0 {
 		return strconv.FormatInt(x, 10)
@@ -54,22 +64,27 @@ func statOrNan(x int64) string {
 func (c Collector) renderTable() string {
 	w := table.NewWriter()
 
-	w.AppendHeader(table.Row{"Table Name", "Inserted", "Updated", "Unchanged"})
+	w.AppendHeader(table.Row{"Table Name", "Inserted", "Updated", "Unchanged", "Source"})
 
 	total := tblMergeStats{}
 	for tableName, stats := range c {
+		source := "-"
+		if stats.SourceUnchanged {
+			source = "unchanged"
+		}
 		w.AppendRow(table.Row{
 			tableName,
 			statOrNan(stats.Inserted),
 			statOrNan(stats.Updated),
 			statOrNan(stats.Noop),
+			source,
 		})
 		total.Inserted += stats.Inserted
 		total.Updated += stats.Updated
 		total.Noop += stats.Noop
 	}
 	w.AppendSeparator()
-	w.AppendFooter(table.Row{"Total", total.Inserted, total.Updated, total.Noop})
+	w.AppendFooter(table.Row{"Total", total.Inserted, total.Updated, total.Noop, ""})
 	w.SetStyle(table.StyleLight)
 	w.SortBy([]table.SortBy{
 		{Name: "Inserted", Mode: table.DscNumericAlpha},
@@ -79,12 +94,55 @@ func (c Collector) renderTable() string {
 	return w.Render()
 }
 
-// Print prints the collected statistics to `logger` per line, retaining
-// potential logging prefixes.
-func (c Collector) Print(logger *log.Logger) {
-	rendered := c.renderTable()
-	lines := strings.Split(rendered, "\n")
-	for _, line := range lines {
-		logger.Println(line)
+// Print writes the collected statistics to `w` as a human-readable table.
+func (c Collector) Print(w io.Writer) error {
+	_, err := fmt.Fprintln(w, c.renderTable())
+	return err
+}
+
+// touched reports whether the table was merged or skipped.
+func (s *tblMergeStats) touched() bool {
+	return s.Inserted > 0 || s.Updated > 0 || s.Noop > 0 || s.SourceUnchanged
+}
+
+// Log logs the collected statistics with one record per table which was merged or skipped.
+func (c Collector) Log(ctx context.Context, logger *slog.Logger) {
+	for _, tbl := range gen.AllTables {
+		stats, ok := c[tbl]
+		if !ok || !stats.touched() {
+			continue
+		}
+		logger.LogAttrs(ctx, slog.LevelInfo, "merged table",
+			slog.String(logging.KeyTable, tbl.String()),
+			slog.Int64("inserted", stats.Inserted),
+			slog.Int64("updated", stats.Updated),
+			slog.Int64("noop", stats.Noop),
+			slog.Bool("source_unchanged", stats.SourceUnchanged),
+		)
+	}
+}
+
+// TableCounts are the statistics of a single table in a serializable form.
+type TableCounts struct {
+	Inserted        int64 `json:"inserted"`
+	Updated         int64 `json:"updated"`
+	Noop            int64 `json:"noop"`
+	SourceUnchanged bool  `json:"source_unchanged"`
+}
+
+// Counts returns the statistics of all tables which were merged or skipped, keyed by table name.
+func (c Collector) Counts() map[string]TableCounts {
+	counts := map[string]TableCounts{}
+	for tbl, stats := range c {
+		if !stats.touched() {
+			continue
+		}
+		counts[tbl.String()] = TableCounts{
+			Inserted:        stats.Inserted,
+			Updated:         stats.Updated,
+			Noop:            stats.Noop,
+			SourceUnchanged: stats.SourceUnchanged,
+		}
 	}
+	return counts
 }
@@ -0,0 +1,29 @@
+//go:build integration
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+)
+
+// The readers below complete dbtest.Store for the tables without client methods.
+
+// SnapshotGaps returns the gaps starting at or after `since`.
+func (c *Client) SnapshotGaps(ctx context.Context, since time.Time) ([]gen.SnapshotGap, error) {
+	return c.queries.GetSnapshotGaps(ctx, PGTimestamp(since))
+}
+
+// WarFinalPlanets returns the final owners of the planets of a closed war.
+func (c *Client) WarFinalPlanets(ctx context.Context, warID int32) ([]gen.WarFinalPlanet, error) {
+	return c.queries.GetWarFinalPlanets(ctx, warID)
+}
+
+// SyncRun returns the sync run with ID `id`.
+func (c *Client) SyncRun(ctx context.Context, id pgtype.UUID) (gen.SyncRun, error) {
+	return c.queries.GetSyncRun(ctx, id)
+}
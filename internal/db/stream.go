@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	_ "embed" // embed streaming queries
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+//go:embed queries/stream_planet_snapshots.sql
+var streamPlanetSnapshots string
+
+// PlanetSnapshotParams selects the planet snapshots streamed by ForEachPlanetSnapshot.
+//
+// Invalid timestamps and nil fields do not restrict the selection.
+type PlanetSnapshotParams struct {
+	// Since is the earliest snapshot time (inclusive)
+	Since pgtype.Timestamp
+	// Until is the latest snapshot time (exclusive)
+	Until    pgtype.Timestamp
+	PlanetID *int32
+	Sector   *string
+	// Faction is the current owner of the planet
+	Faction *string
+}
+
+// namedArgs binds the parameters to the arguments of the streaming query.
+func (p PlanetSnapshotParams) namedArgs() pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"since":     p.Since,
+		"until":     p.Until,
+		"planet_id": p.PlanetID,
+		"sector":    p.Sector,
+		"faction":   p.Faction,
+	}
+}
+
+// PlanetSnapshotRow is a planet snapshot joined with its planet, event and statistics.
+//
+// Fields are matched to the selected columns by name.
+type PlanetSnapshotRow struct {
+	CreateTime         pgtype.Timestamp
+	WarID              int32
+	PlanetID           int32
+	PlanetName         string
+	Sector             string
+	MaxHealth          int64
+	CurrentOwner       string
+	Health             int64
+	RegenPerSecond     float64
+	AttackingPlanetIds []int32
+	// AttackingPlanets is a JSON array of the ID, name and sector of each attacked planet
+	AttackingPlanets []byte
+	EventID          *int32
+	EventFaction     *string
+	EventHealth      *int64
+	EventMaxHealth   *int64
+	EventEndTime     pgtype.Timestamp
+	MissionsWon      int64
+	MissionsLost     int64
+	MissionTime      int64
+	TerminidKills    int64
+	AutomatonKills   int64
+	IlluminateKills  int64
+	BulletsFired     int64
+	BulletsHit       int64
+	TimePlayed       int64
+	Deaths           int64
+	Revives          int64
+	Friendlies       int64
+	PlayerCount      int64
+}
+
+// ForEachPlanetSnapshot calls `fn` for each planet snapshot matching `params` in order of their snapshot time,
+// without reading all of them into memory first.
+//
+// Iteration stops at the first error returned by `fn`.
+func (c *Client) ForEachPlanetSnapshot(ctx context.Context, params PlanetSnapshotParams, fn func(PlanetSnapshotRow) error) error {
+	rows, err := c.pool.Query(ctx, streamPlanetSnapshots, params.namedArgs())
+	if err != nil {
+		return fmt.Errorf("failed to stream planet snapshots: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		row, err := pgx.RowToStructByName[PlanetSnapshotRow](rows)
+		if err != nil {
+			return fmt.Errorf("failed to stream planet snapshots: %w", err)
+		}
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to stream planet snapshots: %w", err)
+	}
+	return nil
+}
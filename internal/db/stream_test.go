@@ -0,0 +1,42 @@
+package db
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// queryArgs returns the sorted names of the named arguments of `query`, ignoring comments.
+func queryArgs(query string) []string {
+	var names []string
+	for _, line := range strings.Split(query, "\n") {
+		line, _, _ = strings.Cut(line, "--")
+		for _, match := range regexp.MustCompile(`@(\w+)`).FindAllStringSubmatch(line, -1) {
+			if !slices.Contains(names, match[1]) {
+				names = append(names, match[1])
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func boundArgs(args pgx.NamedArgs) []string {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestStreamPlanetSnapshotsArgs(t *testing.T) {
+	got := boundArgs(PlanetSnapshotParams{}.namedArgs())
+	want := queryArgs(streamPlanetSnapshots)
+	if !slices.Equal(got, want) {
+		t.Errorf("bound arguments = %v, want the arguments of the query %v", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+)
+
+// outcomes of a sync run
+const (
+	SyncOutcomeSuccess = "success"
+	SyncOutcomeFailure = "failure"
+)
+
+// compile-time implementation check
+var _ EntityMerger = (*SyncRun)(nil)
+
+// SyncRun implements EntityMerger.
+//
+// It records a single run of a sync job in the sync_runs ledger.
+// Runs are never updated, merging a run with an existing ID is a no-op.
+type SyncRun struct {
+	ID        pgtype.UUID
+	Job       string
+	StartTime pgtype.Timestamp
+	EndTime   pgtype.Timestamp
+	// Outcome is either SyncOutcomeSuccess or SyncOutcomeFailure
+	Outcome     string
+	Endpoints   []SyncRunEndpoint
+	MergeCounts map[string]stats.TableCounts
+	// Error is nil if the run succeeded
+	Error *string
+	// SnapshotCreateTime is invalid if the run did not create a snapshot
+	SnapshotCreateTime pgtype.Timestamp
+}
+
+// SyncRunEndpoint is the result of querying a single API endpoint during a sync run.
+type SyncRunEndpoint struct {
+	Endpoint string `json:"endpoint"`
+	// Status is one of "ok", "unchanged" or "failed"
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Upstream  string `json:"upstream,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// EncodeJSON returns the endpoints and merge counts of the run as JSON, as stored in the ledger.
+func (r *SyncRun) EncodeJSON() (endpoints []byte, mergeCounts []byte, err error) {
+	eps := r.Endpoints
+	if eps == nil {
+		eps = []SyncRunEndpoint{}
+	}
+	if endpoints, err = json.Marshal(eps); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode endpoints: %v", err)
+	}
+	counts := r.MergeCounts
+	if counts == nil {
+		counts = map[string]stats.TableCounts{}
+	}
+	if mergeCounts, err = json.Marshal(counts); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode merge counts: %v", err)
+	}
+	return endpoints, mergeCounts, nil
+}
+
+// Merge implements EntityMerger.
+func (r *SyncRun) Merge(ctx context.Context, tx *gen.Queries, onMerge onMergeFunc) error {
+	endpoints, mergeCounts, err := r.EncodeJSON()
+	if err != nil {
+		return fmt.Errorf("failed to merge sync run (ID=%s): %v", uuid.UUID(r.ID.Bytes), err)
+	}
+	if err = upsert(ctx, gen.TableSyncRuns, tx.MergeSyncRun, gen.MergeSyncRunParams{
+		ID:                 r.ID,
+		Job:                r.Job,
+		StartTime:          r.StartTime,
+		EndTime:            r.EndTime,
+		Outcome:            r.Outcome,
+		Endpoints:          endpoints,
+		MergeCounts:        mergeCounts,
+		Error:              r.Error,
+		SnapshotCreateTime: r.SnapshotCreateTime,
+	}, onMerge); err != nil {
+		return fmt.Errorf("failed to merge sync run (ID=%s): %v", uuid.UUID(r.ID.Bytes), err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+)
+
+// compile-time implementation check
+var _ EntityMerger = (*WarClosure)(nil)
+
+// WarClosure implements EntityMerger.
+//
+// It closes out a war which has ended by summarizing its snapshots and
+// storing the final planet ownership.
+// Merging the same war again refreshes the summary, e.g. after archived
+// snapshots have been filled in.
+type WarClosure struct {
+	WarID     int32
+	CloseTime pgtype.Timestamp
+}
+
+// Merge implements EntityMerger.
+func (w *WarClosure) Merge(ctx context.Context, tx *gen.Queries, onMerge onMergeFunc) error {
+	// no row is returned if the war does not have any snapshots, nothing to summarize then
+	if err := upsert(ctx, gen.TableWarSummaries, tx.MergeWarSummary, gen.MergeWarSummaryParams{
+		CloseTime: w.CloseTime,
+		WarID:     w.WarID,
+	}, onMerge); err != nil {
+		return fmt.Errorf("failed to merge summary of war ID=%d: %v", w.WarID, err)
+	}
+
+	// one row is returned for each inserted or updated planet
+	inserted, err := tx.MergeWarFinalPlanets(ctx, w.WarID)
+	if err != nil {
+		return fmt.Errorf("failed to merge final planets of war ID=%d: %v", w.WarID, err)
+	}
+	if len(inserted) == 0 {
+		onMerge(gen.TableWarFinalPlanets, true, 0)
+	}
+	for _, ins := range inserted {
+		onMerge(gen.TableWarFinalPlanets, !ins, 1)
+	}
+	return nil
+}
+
+// WarSummary returns the summary of a closed war.
+//
+// `ok` is false if the war has not been closed or had no snapshots.
+func (c *Client) WarSummary(ctx context.Context, warID int32) (summary gen.WarSummary, ok bool, err error) {
+	summary, err = c.queries.GetWarSummary(ctx, warID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return gen.WarSummary{}, false, nil
+	}
+	if err != nil {
+		return gen.WarSummary{}, false, fmt.Errorf("failed to query summary of war ID=%d: %v", warID, err)
+	}
+	return summary, true, nil
+}
+
+// LatestWarID returns the ID of the war of the most recent snapshot.
+//
+// `ok` is false if no snapshot exists yet.
+func (c *Client) LatestWarID(ctx context.Context) (id int32, ok bool, err error) {
+	id, err = c.queries.GetLatestSnapshotWarID(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to query war of latest snapshot: %v", err)
+	}
+	return id, true, nil
+}
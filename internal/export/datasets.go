@@ -0,0 +1,145 @@
+package export
+
+import (
+	"context"
+	"time"
+)
+
+// dataset is a flattened view of the snapshots which is exported into its own directory.
+type dataset struct {
+	name string
+	// columns are the schema of the exported files, they must not change between runs
+	columns []column
+	// write appends the rows of all snapshots taken after `after` until `until` to `w`
+	write func(ctx context.Context, src Source, after, until time.Time, w *parquetWriter) error
+}
+
+var statsColumns = []column{
+	{name: "missions_won", kind: kindInt64},
+	{name: "missions_lost", kind: kindInt64},
+	{name: "mission_time", kind: kindInt64},
+	{name: "terminid_kills", kind: kindInt64},
+	{name: "automaton_kills", kind: kindInt64},
+	{name: "illuminate_kills", kind: kindInt64},
+	{name: "bullets_fired", kind: kindInt64},
+	{name: "bullets_hit", kind: kindInt64},
+	{name: "time_played", kind: kindInt64},
+	{name: "deaths", kind: kindInt64},
+	{name: "revives", kind: kindInt64},
+	{name: "friendlies", kind: kindInt64},
+	{name: "player_count", kind: kindInt64},
+}
+
+var datasets = []dataset{
+	{
+		name: "planet_snapshots",
+		columns: append([]column{
+			{name: "create_time", kind: kindTimestamp},
+			{name: "war_id", kind: kindInt32},
+			{name: "planet_id", kind: kindInt32},
+			{name: "planet_name", kind: kindString},
+			{name: "sector", kind: kindString},
+			{name: "current_owner", kind: kindString},
+			{name: "health", kind: kindInt64},
+			{name: "max_health", kind: kindInt64},
+			{name: "regen_per_second", kind: kindDouble},
+			{name: "event_id", kind: kindInt32, optional: true},
+			{name: "event_health", kind: kindInt64, optional: true},
+		}, statsColumns...),
+		write: func(ctx context.Context, src Source, after, until time.Time, w *parquetWriter) error {
+			rows, err := src.ExportPlanetSnapshots(ctx, after, until)
+			if err != nil {
+				return err
+			}
+			for _, r := range rows {
+				if err = w.append(
+					r.CreateTime, r.WarID, r.PlanetID, r.PlanetName, r.Sector, r.CurrentOwner, r.Health, r.MaxHealth, r.RegenPerSecond,
+					r.EventID, r.EventHealth,
+					r.MissionsWon, r.MissionsLost, r.MissionTime, r.TerminidKills, r.AutomatonKills, r.IlluminateKills,
+					r.BulletsFired, r.BulletsHit, r.TimePlayed, r.Deaths, r.Revives, r.Friendlies, r.PlayerCount,
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		name: "war_stats",
+		columns: append([]column{
+			{name: "create_time", kind: kindTimestamp},
+			{name: "war_id", kind: kindInt32},
+			{name: "impact_multiplier", kind: kindDouble},
+		}, statsColumns...),
+		write: func(ctx context.Context, src Source, after, until time.Time, w *parquetWriter) error {
+			rows, err := src.ExportWarStats(ctx, after, until)
+			if err != nil {
+				return err
+			}
+			for _, r := range rows {
+				if err = w.append(
+					r.CreateTime, r.WarID, r.ImpactMultiplier,
+					r.MissionsWon, r.MissionsLost, r.MissionTime, r.TerminidKills, r.AutomatonKills, r.IlluminateKills,
+					r.BulletsFired, r.BulletsHit, r.TimePlayed, r.Deaths, r.Revives, r.Friendlies, r.PlayerCount,
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		name: "assignment_progress",
+		columns: []column{
+			{name: "create_time", kind: kindTimestamp},
+			{name: "war_id", kind: kindInt32},
+			{name: "assignment_id", kind: kindInt64},
+			{name: "title", kind: kindString},
+			{name: "task_index", kind: kindInt32},
+			{name: "task_type", kind: kindInt32, optional: true},
+			{name: "progress", kind: kindInt64},
+		},
+		write: func(ctx context.Context, src Source, after, until time.Time, w *parquetWriter) error {
+			rows, err := src.ExportAssignmentProgress(ctx, after, until)
+			if err != nil {
+				return err
+			}
+			for _, r := range rows {
+				if err = w.append(r.CreateTime, r.WarID, r.AssignmentID, r.Title, r.TaskIndex, r.TaskType, r.Progress); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		name: "events",
+		columns: []column{
+			{name: "create_time", kind: kindTimestamp},
+			{name: "war_id", kind: kindInt32},
+			{name: "event_id", kind: kindInt32},
+			{name: "planet_id", kind: kindInt32},
+			{name: "campaign_id", kind: kindInt32},
+			{name: "event_type", kind: kindInt32},
+			{name: "faction", kind: kindString},
+			{name: "health", kind: kindInt64},
+			{name: "max_health", kind: kindInt64},
+			{name: "start_time", kind: kindTimestamp},
+			{name: "end_time", kind: kindTimestamp},
+		},
+		write: func(ctx context.Context, src Source, after, until time.Time, w *parquetWriter) error {
+			rows, err := src.ExportEvents(ctx, after, until)
+			if err != nil {
+				return err
+			}
+			for _, r := range rows {
+				if err = w.append(
+					r.CreateTime, r.WarID, r.EventID, r.PlanetID, r.CampaignID, r.EventType, r.Faction, r.Health, r.MaxHealth, r.StartTime, r.EndTime,
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+}
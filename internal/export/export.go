@@ -0,0 +1,213 @@
+// Package export writes flattened views of the snapshots to files for analysis outside of the DB.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+)
+
+// watermarkFile stores the time of the latest exported snapshot in the output directory.
+const watermarkFile = "_watermark.json"
+
+// Source provides the rows of the exported datasets.
+type Source interface {
+	// SnapshotTimes returns the times of all snapshots since `since` in ascending order.
+	SnapshotTimes(ctx context.Context, since time.Time) ([]time.Time, error)
+	ExportPlanetSnapshots(ctx context.Context, after, until time.Time) ([]gen.ExportPlanetSnapshotsRow, error)
+	ExportWarStats(ctx context.Context, after, until time.Time) ([]gen.ExportWarStatsRow, error)
+	ExportAssignmentProgress(ctx context.Context, after, until time.Time) ([]gen.ExportAssignmentProgressRow, error)
+	ExportEvents(ctx context.Context, after, until time.Time) ([]gen.ExportEventsRow, error)
+}
+
+// Exporter writes each dataset as Parquet files, partitioned by the UTC day of the snapshots.
+//
+// Files are written to <dir>/<dataset>/date=<YYYY-MM-DD>/part-<first snapshot time>.parquet.
+// Each run only exports snapshots taken after the latest snapshot of the previous run,
+// so a partition may consist of multiple files.
+type Exporter struct {
+	src Source
+	dir string
+	log *slog.Logger
+}
+
+// New creates a new Exporter writing to `dir`.
+func New(src Source, dir string, logger *slog.Logger) *Exporter {
+	return &Exporter{
+		src: src,
+		dir: dir,
+		log: logger,
+	}
+}
+
+type watermark struct {
+	// LastSnapshotTime is the time of the latest exported snapshot
+	LastSnapshotTime time.Time `json:"last_snapshot_time"`
+}
+
+// Run exports all snapshots taken since the last run.
+//
+// If `full` is set, all snapshots are exported and existing partitions are replaced.
+func (e *Exporter) Run(ctx context.Context, full bool) error {
+	if err := os.MkdirAll(e.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	var last time.Time
+	if !full {
+		var err error
+		if last, err = e.readWatermark(); err != nil {
+			return err
+		}
+	}
+
+	times, err := e.src.SnapshotTimes(ctx, last)
+	if err != nil {
+		return err
+	}
+	// snapshot times are inclusive, but the watermark has already been exported
+	for len(times) > 0 && !times[0].After(last) {
+		times = times[1:]
+	}
+	if len(times) == 0 {
+		e.log.InfoContext(ctx, "no new snapshots to export")
+		return nil
+	}
+
+	days := groupByDay(times)
+	e.log.InfoContext(ctx, "exporting snapshots", "snapshots", len(times), "days", len(days))
+	for _, day := range days {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if err = e.exportDay(ctx, day, last, full); err != nil {
+			return err
+		}
+		last = day[len(day)-1]
+		if err = e.writeWatermark(last); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupByDay splits `times` (in ascending order) by their UTC day.
+func groupByDay(times []time.Time) [][]time.Time {
+	var days [][]time.Time
+	for i, t := range times {
+		if i == 0 || partitionName(t) != partitionName(times[i-1]) {
+			days = append(days, nil)
+		}
+		days[len(days)-1] = append(days[len(days)-1], t)
+	}
+	return days
+}
+
+func partitionName(t time.Time) string {
+	return "date=" + t.UTC().Format(time.DateOnly)
+}
+
+// exportDay writes a file for each dataset containing the snapshots at `times`, which were all taken on the same day after `after`.
+func (e *Exporter) exportDay(ctx context.Context, times []time.Time, after time.Time, replace bool) error {
+	first, until := times[0], times[len(times)-1]
+	partition := partitionName(first)
+	for _, ds := range datasets {
+		dir := filepath.Join(e.dir, ds.name, partition)
+		if replace {
+			if err := removeParts(dir); err != nil {
+				return err
+			}
+		}
+
+		w := newParquetWriter(ds.columns)
+		if err := ds.write(ctx, e.src, after, until, w); err != nil {
+			return fmt.Errorf("failed to export %s of %s: %v", ds.name, partition, err)
+		}
+		if len(w.rows) == 0 {
+			continue
+		}
+
+		name := "part-" + first.UTC().Format("20060102T150405.000000Z") + ".parquet"
+		if err := writeFile(dir, name, w); err != nil {
+			return fmt.Errorf("failed to write %s of %s: %v", ds.name, partition, err)
+		}
+		e.log.InfoContext(ctx, "exported rows", "rows", len(w.rows), "dataset", ds.name, "path", filepath.Join(dir, name))
+	}
+	return nil
+}
+
+// removeParts removes all previously exported files of a partition.
+func removeParts(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "part-") && filepath.Ext(entry.Name()) == ".parquet" {
+			if err = os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeFile writes the Parquet file to a temporary file first, so readers never see incomplete files.
+func writeFile(dir, name string, w *parquetWriter) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, "."+name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // no-op after successful rename
+
+	if err = w.writeTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(dir, name))
+}
+
+func (e *Exporter) readWatermark() (time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(e.dir, watermarkFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read watermark: %v", err)
+	}
+	var wm watermark
+	if err = json.Unmarshal(data, &wm); err != nil {
+		return time.Time{}, fmt.Errorf("invalid watermark: %v", err)
+	}
+	return wm.LastSnapshotTime, nil
+}
+
+func (e *Exporter) writeWatermark(t time.Time) error {
+	data, err := json.Marshal(watermark{LastSnapshotTime: t.UTC()})
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(e.dir, watermarkFile)
+	if err = os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return fmt.Errorf("failed to write watermark: %v", err)
+	}
+	if err = os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write watermark: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/logging"
+)
+
+// fakeSource returns a war stats row for each snapshot time and nothing for all other datasets.
+type fakeSource struct {
+	times []time.Time
+}
+
+func (s *fakeSource) SnapshotTimes(_ context.Context, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	for _, t := range s.times {
+		if !t.Before(since) {
+			times = append(times, t)
+		}
+	}
+	return times, nil
+}
+
+func (s *fakeSource) ExportPlanetSnapshots(context.Context, time.Time, time.Time) ([]gen.ExportPlanetSnapshotsRow, error) {
+	return nil, nil
+}
+
+func (s *fakeSource) ExportWarStats(_ context.Context, after, until time.Time) ([]gen.ExportWarStatsRow, error) {
+	var rows []gen.ExportWarStatsRow
+	for _, t := range s.times {
+		if t.After(after) && !t.After(until) {
+			rows = append(rows, gen.ExportWarStatsRow{
+				CreateTime:       pgtype.Timestamp{Time: t, Valid: true},
+				WarID:            801,
+				ImpactMultiplier: 0.5,
+			})
+		}
+	}
+	return rows, nil
+}
+
+func (s *fakeSource) ExportAssignmentProgress(context.Context, time.Time, time.Time) ([]gen.ExportAssignmentProgressRow, error) {
+	return nil, nil
+}
+
+func (s *fakeSource) ExportEvents(context.Context, time.Time, time.Time) ([]gen.ExportEventsRow, error) {
+	return nil, nil
+}
+
+// exportedTimes returns the create times of all exported war stats by partition and the number of files.
+func exportedTimes(t *testing.T, dir string) (map[string][]int64, int) {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "war_stats", "*", "*.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]int64{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, values := readParquet(t, data)
+		partition := filepath.Base(filepath.Dir(file))
+		for _, v := range values[0] {
+			got[partition] = append(got[partition], v.(int64))
+		}
+	}
+	return got, len(files)
+}
+
+func TestExporterRun(t *testing.T) {
+	day1 := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 5, 2, 1, 0, 0, 0, time.UTC)
+	src := &fakeSource{times: []time.Time{day1, day1.Add(30 * time.Minute), day2}}
+	dir := t.TempDir()
+	exporter := New(src, dir, logging.Discard())
+
+	run := func(full bool, want map[string][]int64, wantFiles int) {
+		t.Helper()
+		if err := exporter.Run(context.Background(), full); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		got, files := exportedTimes(t, dir)
+		if !reflect.DeepEqual(got, want) || files != wantFiles {
+			t.Errorf("Run() exported %v in %d files, want %v in %d files", got, files, want, wantFiles)
+		}
+	}
+
+	run(false, map[string][]int64{
+		"date=2024-05-01": {day1.UnixMicro(), day1.Add(30 * time.Minute).UnixMicro()},
+		"date=2024-05-02": {day2.UnixMicro()},
+	}, 2)
+	if _, err := os.Stat(filepath.Join(dir, "events")); !os.IsNotExist(err) {
+		t.Errorf("empty dataset has been written")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, watermarkFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wm watermark
+	if err = json.Unmarshal(data, &wm); err != nil || !wm.LastSnapshotTime.Equal(day2) {
+		t.Errorf("watermark = %s, want %v", data, day2)
+	}
+
+	// only the new snapshot is exported, into a new file of the same partition
+	day2Later := day2.Add(time.Hour)
+	src.times = append(src.times, day2Later)
+	want := map[string][]int64{
+		"date=2024-05-01": {day1.UnixMicro(), day1.Add(30 * time.Minute).UnixMicro()},
+		"date=2024-05-02": {day2.UnixMicro(), day2Later.UnixMicro()},
+	}
+	run(false, want, 3)
+
+	// nothing new
+	run(false, want, 3)
+
+	// a full export replaces the partitions instead of adding to them
+	run(true, want, 2)
+}
+
+func TestGroupByDay(t *testing.T) {
+	// partitions are determined in UTC
+	cet := time.FixedZone("CET", 3600)
+	times := []time.Time{
+		time.Date(2024, 5, 1, 0, 30, 0, 0, cet),
+		time.Date(2024, 5, 1, 1, 30, 0, 0, cet),
+		time.Date(2024, 5, 1, 2, 30, 0, 0, cet),
+	}
+	want := [][]time.Time{times[:1], times[1:]}
+	if got := groupByDay(times); !reflect.DeepEqual(got, want) {
+		t.Errorf("groupByDay() = %v, want %v", got, want)
+	}
+}
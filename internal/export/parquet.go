@@ -0,0 +1,213 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/parquet-go/parquet-go"
+)
+
+// columnKind is the type of the values of a column.
+type columnKind int
+
+const (
+	kindInt32 columnKind = iota
+	kindInt64
+	kindDouble
+	kindString
+	// kindTimestamp are UTC timestamps with microsecond precision
+	kindTimestamp
+)
+
+func (k columnKind) node() parquet.Node {
+	switch k {
+	case kindInt32:
+		return parquet.Leaf(parquet.Int32Type)
+	case kindInt64:
+		return parquet.Leaf(parquet.Int64Type)
+	case kindDouble:
+		return parquet.Leaf(parquet.DoubleType)
+	case kindString:
+		return parquet.String()
+	default:
+		return parquet.Timestamp(parquet.Microsecond)
+	}
+}
+
+// column describes a single column of a dataset.
+type column struct {
+	name string
+	kind columnKind
+	// optional columns accept nil values
+	optional bool
+}
+
+// parquetWriter buffers rows in memory and writes them as a Parquet file with a single row group.
+//
+// Column chunks are gzip-compressed.
+type parquetWriter struct {
+	columns []column
+	schema  *parquet.Schema
+	rows    []parquet.Row
+}
+
+func newParquetWriter(columns []column) *parquetWriter {
+	fields := make([]parquet.Field, len(columns))
+	for i, col := range columns {
+		node := col.kind.node()
+		if col.optional {
+			node = parquet.Optional(node)
+		}
+		fields[i] = schemaField{Node: node, name: col.name}
+	}
+	return &parquetWriter{
+		columns: columns,
+		schema:  parquet.NewSchema("schema", schemaGroup{fields: fields}),
+	}
+}
+
+// append adds a row with one value for each column.
+//
+// Values may be pointers, nil pointers are written as null.
+// The writer must not be used anymore after an error.
+func (w *parquetWriter) append(values ...any) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("got %d values for %d columns", len(values), len(w.columns))
+	}
+	row := make(parquet.Row, len(w.columns))
+	for i, col := range w.columns {
+		v := deref(values[i])
+		if v == nil {
+			if !col.optional {
+				return fmt.Errorf("column %s is not optional", col.name)
+			}
+			row[i] = parquet.NullValue().Level(0, 0, i)
+			continue
+		}
+		value, err := parquetValue(col.kind, v)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", col.name, err)
+		}
+		definitionLevel := 0
+		if col.optional {
+			definitionLevel = 1
+		}
+		row[i] = value.Level(0, definitionLevel, i)
+	}
+	w.rows = append(w.rows, row)
+	return nil
+}
+
+// deref returns the value `v` points to, or nil if `v` is a nil pointer or an invalid timestamp.
+func deref(v any) any {
+	switch x := v.(type) {
+	case *int32:
+		if x != nil {
+			return *x
+		}
+	case *int64:
+		if x != nil {
+			return *x
+		}
+	case *float64:
+		if x != nil {
+			return *x
+		}
+	case *string:
+		if x != nil {
+			return *x
+		}
+	case pgtype.Timestamp:
+		if x.Valid {
+			return x.Time
+		}
+	default:
+		return v
+	}
+	return nil
+}
+
+func parquetValue(kind columnKind, v any) (parquet.Value, error) {
+	switch x := v.(type) {
+	case int32:
+		if kind == kindInt32 {
+			return parquet.Int32Value(x), nil
+		}
+	case int64:
+		if kind == kindInt64 {
+			return parquet.Int64Value(x), nil
+		}
+	case float64:
+		if kind == kindDouble {
+			return parquet.DoubleValue(x), nil
+		}
+	case string:
+		if kind == kindString {
+			return parquet.ByteArrayValue([]byte(x)), nil
+		}
+	case time.Time:
+		if kind == kindTimestamp {
+			return parquet.Int64Value(x.UnixMicro()), nil
+		}
+	}
+	return parquet.Value{}, fmt.Errorf("unexpected value of type %T", v)
+}
+
+// writeTo writes the Parquet file to `out`.
+func (w *parquetWriter) writeTo(out io.Writer) error {
+	pw := parquet.NewWriter(out, w.schema,
+		parquet.Compression(&parquet.Gzip),
+		parquet.CreatedBy("helldivers-client", "", ""),
+	)
+	if _, err := pw.WriteRows(w.rows); err != nil {
+		return err
+	}
+	return pw.Close()
+}
+
+// schemaGroup is the root of the schema.
+//
+// Unlike parquet.Group, which sorts its fields by name, it keeps the fields in the order of the columns.
+type schemaGroup struct {
+	parquet.Group
+	fields []parquet.Field
+}
+
+func (g schemaGroup) Fields() []parquet.Field {
+	return g.fields
+}
+
+func (g schemaGroup) String() string {
+	return parquet.NewSchema("schema", g).String()
+}
+
+// GoType returns a struct with the fields in the order of the columns.
+func (g schemaGroup) GoType() reflect.Type {
+	fields := make([]reflect.StructField, len(g.fields))
+	for i, f := range g.fields {
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Column%d", i),
+			Type: f.GoType(),
+			Tag:  reflect.StructTag(fmt.Sprintf(`parquet:%q`, f.Name())),
+		}
+	}
+	return reflect.StructOf(fields)
+}
+
+// schemaField is a column of schemaGroup.
+type schemaField struct {
+	parquet.Node
+	name string
+}
+
+func (f schemaField) Name() string {
+	return f.name
+}
+
+// Value is only required to write Go values, while rows are written directly.
+func (f schemaField) Value(reflect.Value) reflect.Value {
+	return reflect.Value{}
+}
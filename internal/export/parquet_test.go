@@ -0,0 +1,144 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// readParquet decodes a Parquet file into its column names and values, nulls are nil.
+//
+// Timestamps are returned as microseconds since the epoch.
+func readParquet(t *testing.T, data []byte) (names []string, values [][]any) {
+	t.Helper()
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	columns := f.Schema().Fields()
+	for _, col := range columns {
+		names = append(names, col.Name())
+	}
+
+	values = make([][]any, len(columns))
+	r := parquet.NewReader(f)
+	defer r.Close()
+	rows := make([]parquet.Row, f.NumRows())
+	if n, err := r.ReadRows(rows); err != nil && !errors.Is(err, io.EOF) {
+		t.Fatalf("ReadRows() error = %v", err)
+	} else if n != len(rows) {
+		t.Fatalf("ReadRows() = %d rows, want %d", n, len(rows))
+	}
+	for _, row := range rows {
+		for _, v := range row {
+			var value any
+			if !v.IsNull() {
+				switch columns[v.Column()].Type().Kind() {
+				case parquet.Int32:
+					value = v.Int32()
+				case parquet.Int64:
+					value = v.Int64()
+				case parquet.Double:
+					value = v.Double()
+				case parquet.ByteArray:
+					value = string(v.ByteArray())
+				default:
+					t.Fatalf("unexpected type of column %s", names[v.Column()])
+				}
+			}
+			values[v.Column()] = append(values[v.Column()], value)
+		}
+	}
+	return names, values
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 30, 0, 123000, time.UTC)
+	eventID := int32(7)
+	w := newParquetWriter([]column{
+		{name: "create_time", kind: kindTimestamp},
+		{name: "id", kind: kindInt32},
+		{name: "health", kind: kindInt64},
+		{name: "regen", kind: kindDouble},
+		{name: "name", kind: kindString},
+		{name: "event_id", kind: kindInt32, optional: true},
+	})
+	rows := [][]any{
+		{pgtype.Timestamp{Time: ts, Valid: true}, int32(1), int64(1_000_000), 0.5, "Mars", &eventID},
+		{ts, int32(2), int64(0), 0.0, "", (*int32)(nil)},
+		{ts, int32(3), int64(-1), -1.5, "Malevelon Creek", (*int32)(nil)},
+	}
+	for _, row := range rows {
+		if err := w.append(row...); err != nil {
+			t.Fatalf("append() error = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := w.writeTo(&buf); err != nil {
+		t.Fatalf("writeTo() error = %v", err)
+	}
+
+	names, values := readParquet(t, buf.Bytes())
+	wantNames := []string{"create_time", "id", "health", "regen", "name", "event_id"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("column names = %v, want %v", names, wantNames)
+	}
+	wantValues := [][]any{
+		{ts.UnixMicro(), ts.UnixMicro(), ts.UnixMicro()},
+		{int32(1), int32(2), int32(3)},
+		{int64(1_000_000), int64(0), int64(-1)},
+		{0.5, 0.0, -1.5},
+		{"Mars", "", "Malevelon Creek"},
+		{int32(7), nil, nil},
+	}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("values = %v, want %v", values, wantValues)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	schema := f.Metadata().Schema
+	if ts := schema[1].LogicalType.Timestamp; ts == nil || !ts.IsAdjustedToUTC || ts.Unit.Micros == nil {
+		t.Errorf("logical type of create_time = %+v, want UTC timestamp in microseconds", schema[1].LogicalType)
+	}
+	if schema[5].LogicalType.UTF8 == nil {
+		t.Errorf("logical type of name = %+v, want string", schema[5].LogicalType)
+	}
+	if rep := schema[6].RepetitionType; rep == nil || *rep != format.Optional {
+		t.Errorf("repetition of event_id = %v, want optional", rep)
+	}
+	for _, chunk := range f.Metadata().RowGroups[0].Columns {
+		if chunk.MetaData.Codec != format.Gzip {
+			t.Errorf("codec of column %v = %v, want gzip", chunk.MetaData.PathInSchema, chunk.MetaData.Codec)
+		}
+	}
+}
+
+func TestParquetAppendErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []any
+	}{
+		{name: "too many values", values: []any{int32(1), int32(2)}},
+		{name: "null in required column", values: []any{(*int32)(nil)}},
+		{name: "wrong type", values: []any{int64(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newParquetWriter([]column{{name: "id", kind: kindInt32}})
+			if err := w.append(tt.values...); err == nil {
+				t.Error("append() error = nil, want error")
+			}
+		})
+	}
+}
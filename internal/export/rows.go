@@ -0,0 +1,298 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+)
+
+// Format is the file format of rows written by WriteRows.
+type Format string
+
+const (
+	// FormatCSV writes a header and one line per row, references are written as space-separated IDs
+	FormatCSV Format = "csv"
+	// FormatJSONL writes one JSON object per line, references are resolved into nested objects
+	FormatJSONL Format = "jsonl"
+)
+
+// Filter selects the rows written by WriteRows.
+type Filter struct {
+	// From is the earliest snapshot time (inclusive), unbounded if zero
+	From time.Time
+	// To is the latest snapshot time (exclusive), unbounded if zero
+	To time.Time
+	// PlanetID only selects rows of this planet, if set
+	PlanetID *int32
+	// Sector only selects rows of planets in this sector, if not empty
+	Sector string
+	// Faction only selects rows of planets currently owned by this faction, if not empty
+	Faction string
+}
+
+// RowSource streams the rows of single entities.
+type RowSource interface {
+	ForEachPlanetSnapshot(ctx context.Context, params db.PlanetSnapshotParams, fn func(db.PlanetSnapshotRow) error) error
+}
+
+// record is a single exported row.
+type record interface {
+	csv() []string
+	json() any
+}
+
+// entity is a kind of row which can be exported with WriteRows.
+type entity struct {
+	header []string
+	// stream calls `write` for each row matching `f`
+	stream func(ctx context.Context, src RowSource, f Filter, write func(record) error) error
+}
+
+var entities = map[string]entity{
+	"planet_snapshots": {
+		header: planetSnapshotHeader(),
+		stream: func(ctx context.Context, src RowSource, f Filter, write func(record) error) error {
+			return src.ForEachPlanetSnapshot(ctx, f.planetSnapshotParams(), func(r db.PlanetSnapshotRow) error {
+				return write(planetSnapshot(r))
+			})
+		},
+	},
+}
+
+// Entities returns the names of all entities supported by WriteRows.
+func Entities() []string {
+	names := make([]string, 0, len(entities))
+	for name := range entities {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// WriteRows streams all rows of `entityName` matching `f` to `out` and returns how many were written.
+func WriteRows(ctx context.Context, src RowSource, entityName string, format Format, f Filter, out io.Writer) (int, error) {
+	ent, ok := entities[entityName]
+	if !ok {
+		return 0, fmt.Errorf("unsupported entity %q, must be one of %s", entityName, strings.Join(Entities(), ", "))
+	}
+
+	buf := bufio.NewWriter(out)
+	var write func(record) error
+	// flush writes records buffered by the format to `buf`
+	flush := func() error { return nil }
+	switch format {
+	case FormatCSV:
+		w := csv.NewWriter(buf)
+		if err := w.Write(ent.header); err != nil {
+			return 0, err
+		}
+		write = func(r record) error {
+			return w.Write(r.csv())
+		}
+		flush = func() error {
+			w.Flush()
+			return w.Error()
+		}
+	case FormatJSONL:
+		enc := json.NewEncoder(buf)
+		write = func(r record) error {
+			return enc.Encode(r.json())
+		}
+	default:
+		return 0, fmt.Errorf("unsupported format %q", format)
+	}
+
+	n := 0
+	if err := ent.stream(ctx, src, f, func(r record) error {
+		n++
+		return write(r)
+	}); err != nil {
+		return n, err
+	}
+	if err := flush(); err != nil {
+		return n, err
+	}
+	return n, buf.Flush()
+}
+
+func (f Filter) planetSnapshotParams() db.PlanetSnapshotParams {
+	params := db.PlanetSnapshotParams{PlanetID: f.PlanetID}
+	if !f.From.IsZero() {
+		params.Since = db.PGTimestamp(f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		params.Until = db.PGTimestamp(f.To.UTC())
+	}
+	if f.Sector != "" {
+		params.Sector = &f.Sector
+	}
+	if f.Faction != "" {
+		params.Faction = &f.Faction
+	}
+	return params
+}
+
+type planetSnapshot db.PlanetSnapshotRow
+
+func planetSnapshotHeader() []string {
+	header := []string{
+		"create_time", "war_id", "planet_id", "planet_name", "sector", "max_health", "current_owner", "health", "regen_per_second",
+		"attacking_planet_ids", "event_id", "event_faction", "event_health", "event_max_health", "event_end_time",
+	}
+	for _, col := range statsColumns {
+		header = append(header, col.name)
+	}
+	return header
+}
+
+func (r planetSnapshot) csv() []string {
+	attacking := make([]string, len(r.AttackingPlanetIds))
+	for i, id := range r.AttackingPlanetIds {
+		attacking[i] = strconv.Itoa(int(id))
+	}
+	return []string{
+		csvTime(r.CreateTime),
+		strconv.Itoa(int(r.WarID)),
+		strconv.Itoa(int(r.PlanetID)),
+		r.PlanetName,
+		r.Sector,
+		strconv.FormatInt(r.MaxHealth, 10),
+		r.CurrentOwner,
+		strconv.FormatInt(r.Health, 10),
+		strconv.FormatFloat(r.RegenPerSecond, 'g', -1, 64),
+		strings.Join(attacking, " "),
+		csvOptional(r.EventID, func(x int32) string { return strconv.Itoa(int(x)) }),
+		csvOptional(r.EventFaction, func(x string) string { return x }),
+		csvOptional(r.EventHealth, func(x int64) string { return strconv.FormatInt(x, 10) }),
+		csvOptional(r.EventMaxHealth, func(x int64) string { return strconv.FormatInt(x, 10) }),
+		csvTime(r.EventEndTime),
+		strconv.FormatInt(r.MissionsWon, 10),
+		strconv.FormatInt(r.MissionsLost, 10),
+		strconv.FormatInt(r.MissionTime, 10),
+		strconv.FormatInt(r.TerminidKills, 10),
+		strconv.FormatInt(r.AutomatonKills, 10),
+		strconv.FormatInt(r.IlluminateKills, 10),
+		strconv.FormatInt(r.BulletsFired, 10),
+		strconv.FormatInt(r.BulletsHit, 10),
+		strconv.FormatInt(r.TimePlayed, 10),
+		strconv.FormatInt(r.Deaths, 10),
+		strconv.FormatInt(r.Revives, 10),
+		strconv.FormatInt(r.Friendlies, 10),
+		strconv.FormatInt(r.PlayerCount, 10),
+	}
+}
+
+type planetJSON struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Sector    string `json:"sector"`
+	MaxHealth int64  `json:"max_health"`
+}
+
+type eventJSON struct {
+	ID        int32      `json:"id"`
+	Faction   *string    `json:"faction"`
+	Health    *int64     `json:"health"`
+	MaxHealth *int64     `json:"max_health"`
+	EndTime   *time.Time `json:"end_time"`
+}
+
+type statisticsJSON struct {
+	MissionsWon     int64 `json:"missions_won"`
+	MissionsLost    int64 `json:"missions_lost"`
+	MissionTime     int64 `json:"mission_time"`
+	TerminidKills   int64 `json:"terminid_kills"`
+	AutomatonKills  int64 `json:"automaton_kills"`
+	IlluminateKills int64 `json:"illuminate_kills"`
+	BulletsFired    int64 `json:"bullets_fired"`
+	BulletsHit      int64 `json:"bullets_hit"`
+	TimePlayed      int64 `json:"time_played"`
+	Deaths          int64 `json:"deaths"`
+	Revives         int64 `json:"revives"`
+	Friendlies      int64 `json:"friendlies"`
+	PlayerCount     int64 `json:"player_count"`
+}
+
+type planetSnapshotJSON struct {
+	CreateTime     time.Time  `json:"create_time"`
+	WarID          int32      `json:"war_id"`
+	Planet         planetJSON `json:"planet"`
+	CurrentOwner   string     `json:"current_owner"`
+	Health         int64      `json:"health"`
+	RegenPerSecond float64    `json:"regen_per_second"`
+	// AttackingPlanets has been resolved by the query already
+	AttackingPlanets json.RawMessage `json:"attacking_planets"`
+	Event            *eventJSON      `json:"event"`
+	Statistics       statisticsJSON  `json:"statistics"`
+}
+
+func (r planetSnapshot) json() any {
+	s := planetSnapshotJSON{
+		CreateTime: r.CreateTime.Time.UTC(),
+		WarID:      r.WarID,
+		Planet: planetJSON{
+			ID:        r.PlanetID,
+			Name:      r.PlanetName,
+			Sector:    r.Sector,
+			MaxHealth: r.MaxHealth,
+		},
+		CurrentOwner:     r.CurrentOwner,
+		Health:           r.Health,
+		RegenPerSecond:   r.RegenPerSecond,
+		AttackingPlanets: r.AttackingPlanets,
+		Statistics: statisticsJSON{
+			MissionsWon:     r.MissionsWon,
+			MissionsLost:    r.MissionsLost,
+			MissionTime:     r.MissionTime,
+			TerminidKills:   r.TerminidKills,
+			AutomatonKills:  r.AutomatonKills,
+			IlluminateKills: r.IlluminateKills,
+			BulletsFired:    r.BulletsFired,
+			BulletsHit:      r.BulletsHit,
+			TimePlayed:      r.TimePlayed,
+			Deaths:          r.Deaths,
+			Revives:         r.Revives,
+			Friendlies:      r.Friendlies,
+			PlayerCount:     r.PlayerCount,
+		},
+	}
+	if r.EventID != nil {
+		s.Event = &eventJSON{
+			ID:        *r.EventID,
+			Faction:   r.EventFaction,
+			Health:    r.EventHealth,
+			MaxHealth: r.EventMaxHealth,
+		}
+		if r.EventEndTime.Valid {
+			endTime := r.EventEndTime.Time.UTC()
+			s.Event.EndTime = &endTime
+		}
+	}
+	return s
+}
+
+func csvTime(t pgtype.Timestamp) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339Nano)
+}
+
+// csvOptional formats `x` with `format` or returns an empty string if it is nil.
+func csvOptional[T any](x *T, format func(T) string) string {
+	if x == nil {
+		return ""
+	}
+	return format(*x)
+}
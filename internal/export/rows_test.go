@@ -0,0 +1,213 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+)
+
+// fakeRowSource returns its rows regardless of the params, which are recorded.
+type fakeRowSource struct {
+	rows   []db.PlanetSnapshotRow
+	params db.PlanetSnapshotParams
+}
+
+func (s *fakeRowSource) ForEachPlanetSnapshot(_ context.Context, params db.PlanetSnapshotParams, fn func(db.PlanetSnapshotRow) error) error {
+	s.params = params
+	for _, row := range s.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	testCreateTime = time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	testEventID    = int32(555)
+	testFaction    = "Automatons"
+	testHealth     = int64(999)
+)
+
+var testPlanetSnapshots = []db.PlanetSnapshotRow{
+	{
+		CreateTime:         pgtype.Timestamp{Time: testCreateTime, Valid: true},
+		WarID:              801,
+		PlanetID:           1,
+		PlanetName:         "Foo",
+		Sector:             "Bar",
+		MaxHealth:          1000,
+		CurrentOwner:       "Humans",
+		Health:             500,
+		RegenPerSecond:     0.5,
+		AttackingPlanetIds: []int32{2, 3},
+		AttackingPlanets:   []byte(`[{"id": 2, "name": "Baz", "sector": "Bar"}, {"id": 3, "name": "Qux", "sector": "Bar"}]`),
+		EventID:            &testEventID,
+		EventFaction:       &testFaction,
+		EventHealth:        &testHealth,
+		EventMaxHealth:     &testHealth,
+		EventEndTime:       pgtype.Timestamp{Time: testCreateTime.Add(time.Hour), Valid: true},
+		PlayerCount:        42,
+	},
+	{
+		CreateTime:         pgtype.Timestamp{Time: testCreateTime, Valid: true},
+		WarID:              801,
+		PlanetID:           2,
+		PlanetName:         "Baz",
+		Sector:             "Bar",
+		MaxHealth:          1000,
+		CurrentOwner:       "Automatons",
+		Health:             1000,
+		AttackingPlanetIds: []int32{},
+		AttackingPlanets:   []byte(`[]`),
+	},
+}
+
+func TestWriteRowsCSV(t *testing.T) {
+	src := &fakeRowSource{rows: testPlanetSnapshots}
+	var buf bytes.Buffer
+	n, err := WriteRows(context.Background(), src, "planet_snapshots", FormatCSV, Filter{}, &buf)
+	if err != nil {
+		t.Fatalf("WriteRows() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("WriteRows() = %d, want 2", n)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d CSV records, want header and 2 rows", len(records))
+	}
+	header := records[0]
+	field := func(row int, name string) string {
+		for i, col := range header {
+			if col == name {
+				return records[row][i]
+			}
+		}
+		t.Fatalf("column %s missing", name)
+		return ""
+	}
+	tests := []struct {
+		row  int
+		col  string
+		want string
+	}{
+		{1, "create_time", "2024-05-06T07:08:09Z"},
+		{1, "attacking_planet_ids", "2 3"},
+		{1, "event_id", "555"},
+		{1, "event_end_time", "2024-05-06T08:08:09Z"},
+		{1, "regen_per_second", "0.5"},
+		{1, "player_count", "42"},
+		{2, "attacking_planet_ids", ""},
+		{2, "event_id", ""},
+		{2, "event_end_time", ""},
+	}
+	for _, tt := range tests {
+		if got := field(tt.row, tt.col); got != tt.want {
+			t.Errorf("row %d %s = %q, want %q", tt.row, tt.col, got, tt.want)
+		}
+	}
+}
+
+func TestWriteRowsJSONL(t *testing.T) {
+	src := &fakeRowSource{rows: testPlanetSnapshots}
+	var buf bytes.Buffer
+	if _, err := WriteRows(context.Background(), src, "planet_snapshots", FormatJSONL, Filter{}, &buf); err != nil {
+		t.Fatalf("WriteRows() error = %v", err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	var first, second map[string]any
+	if err := json.Unmarshal(lines[0], &first); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if err := json.Unmarshal(lines[1], &second); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	wantAttacking := []any{
+		map[string]any{"id": 2.0, "name": "Baz", "sector": "Bar"},
+		map[string]any{"id": 3.0, "name": "Qux", "sector": "Bar"},
+	}
+	if !reflect.DeepEqual(first["attacking_planets"], wantAttacking) {
+		t.Errorf("attacking_planets = %v, want %v", first["attacking_planets"], wantAttacking)
+	}
+	wantPlanet := map[string]any{"id": 1.0, "name": "Foo", "sector": "Bar", "max_health": 1000.0}
+	if !reflect.DeepEqual(first["planet"], wantPlanet) {
+		t.Errorf("planet = %v, want %v", first["planet"], wantPlanet)
+	}
+	if event, ok := first["event"].(map[string]any); !ok || event["id"] != 555.0 || event["faction"] != "Automatons" {
+		t.Errorf("event = %v, want event 555", first["event"])
+	}
+	if second["event"] != nil {
+		t.Errorf("event = %v, want null", second["event"])
+	}
+	if stats, ok := first["statistics"].(map[string]any); !ok || stats["player_count"] != 42.0 {
+		t.Errorf("statistics = %v, want player_count 42", first["statistics"])
+	}
+}
+
+func TestWriteRowsFilter(t *testing.T) {
+	src := &fakeRowSource{}
+	planetID := int32(7)
+	filter := Filter{
+		From:     testCreateTime,
+		PlanetID: &planetID,
+		Faction:  "Humans",
+	}
+	if _, err := WriteRows(context.Background(), src, "planet_snapshots", FormatJSONL, filter, &bytes.Buffer{}); err != nil {
+		t.Fatalf("WriteRows() error = %v", err)
+	}
+
+	p := src.params
+	if !p.Since.Valid || !p.Since.Time.Equal(testCreateTime) {
+		t.Errorf("Since = %v, want %v", p.Since, testCreateTime)
+	}
+	if p.Until.Valid {
+		t.Errorf("Until = %v, want NULL", p.Until)
+	}
+	if p.PlanetID == nil || *p.PlanetID != 7 {
+		t.Errorf("PlanetID = %v, want 7", p.PlanetID)
+	}
+	if p.Sector != nil {
+		t.Errorf("Sector = %v, want NULL", *p.Sector)
+	}
+	if p.Faction == nil || *p.Faction != "Humans" {
+		t.Errorf("Faction = %v, want Humans", p.Faction)
+	}
+}
+
+func TestWriteRowsErrors(t *testing.T) {
+	src := &fakeRowSource{rows: testPlanetSnapshots}
+	if _, err := WriteRows(context.Background(), src, "planets", FormatCSV, Filter{}, &bytes.Buffer{}); err == nil {
+		t.Error("WriteRows() with unknown entity error = nil, want error")
+	}
+	if _, err := WriteRows(context.Background(), src, "planet_snapshots", Format("xml"), Filter{}, &bytes.Buffer{}); err == nil {
+		t.Error("WriteRows() with unknown format error = nil, want error")
+	}
+	if _, err := WriteRows(context.Background(), src, "planet_snapshots", FormatJSONL, Filter{}, failingWriter{}); err == nil {
+		t.Error("WriteRows() with failing writer error = nil, want error")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
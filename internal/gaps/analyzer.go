@@ -0,0 +1,173 @@
+package gaps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/robfig/cron/v3"
+
+	"github.com/stnokott/helldivers-client/internal/archive"
+	"github.com/stnokott/helldivers-client/internal/config"
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+	"github.com/stnokott/helldivers-client/internal/logging"
+	"github.com/stnokott/helldivers-client/internal/transform"
+)
+
+// Options control which snapshots are analyzed and what is done with the detected gaps.
+type Options struct {
+	// Since limits the analysis to snapshots taken at or after this time
+	Since time.Time
+	// Fill inserts snapshots from the raw response archive into the detected gaps
+	Fill bool
+	// Mark records the remaining gaps in the DB
+	Mark bool
+}
+
+// Analyzer reports gaps in the snapshot history.
+type Analyzer struct {
+	db       *db.Client
+	schedule cron.Schedule
+	archive  *archive.Archive
+	// out receives the human-readable gap report
+	out io.Writer
+	log *slog.Logger
+}
+
+// New creates a new Analyzer for the snapshot schedule in `cfg`.
+func New(db *db.Client, cfg *config.Config, logger *slog.Logger) (*Analyzer, error) {
+	schedule, err := cron.ParseStandard(cfg.SnapshotCron)
+	if err != nil {
+		return nil, fmt.Errorf("parsing snapshot cron: %w", err)
+	}
+	a := &Analyzer{
+		db:       db,
+		schedule: schedule,
+		out:      os.Stdout,
+		log:      logger,
+	}
+	if cfg.ArchiveDir != "" {
+		a.archive = archive.New(cfg.ArchiveDir, cfg.ArchiveRetention)
+	}
+	return a, nil
+}
+
+// Run detects gaps according to `opts` and prints them.
+func (a *Analyzer) Run(ctx context.Context, opts Options) error {
+	gaps, err := a.detect(ctx, opts.Since)
+	if err != nil {
+		return err
+	}
+
+	if opts.Fill && len(gaps) > 0 {
+		if a.archive == nil {
+			return errors.New("cannot fill gaps, ARCHIVE_DIR is not configured")
+		}
+		filled, err := a.fill(ctx, gaps)
+		if err != nil {
+			return err
+		}
+		if filled > 0 {
+			a.log.InfoContext(ctx, "filled gaps with archived snapshots", "snapshots", filled)
+			if gaps, err = a.detect(ctx, opts.Since); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.Mark {
+		if err = a.mark(ctx, opts.Since, gaps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Analyzer) detect(ctx context.Context, since time.Time) ([]Gap, error) {
+	times, err := a.db.SnapshotTimes(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	a.log.InfoContext(ctx, "analyzing snapshots", "snapshots", len(times))
+	gaps := Detect(times, a.schedule)
+	missed := 0
+	for _, gap := range gaps {
+		missed += gap.Missed
+	}
+	a.log.InfoContext(ctx, "detected gaps", "gaps", len(gaps), "missed_runs", missed)
+	if len(gaps) > 0 {
+		if err = printGaps(a.out, gaps); err != nil {
+			return nil, err
+		}
+	}
+	return gaps, nil
+}
+
+// fill merges all archived snapshots within `gaps` into the DB and returns how many were merged.
+//
+// Archived snapshots which cannot be merged, e.g. because they reference entities which are
+// not in the DB, are skipped.
+func (a *Analyzer) fill(ctx context.Context, gaps []Gap) (int, error) {
+	converter := &transform.ConverterImpl{}
+	filled := 0
+	for _, gap := range gaps {
+		archived, err := a.archive.Between(gap.Start, gap.End)
+		if err != nil {
+			return filled, err
+		}
+		for _, data := range archived {
+			mergers, err := transform.Snapshot(converter, data)
+			if err != nil {
+				a.log.WarnContext(ctx, "skipping archived snapshot", "snapshot_time", data.War.Now, logging.Err(err))
+				continue
+			}
+			if err = a.db.Merge(ctx, stats.NewCollector(), mergers); err != nil {
+				a.log.WarnContext(ctx, "skipping archived snapshot", "snapshot_time", data.War.Now, logging.Err(err))
+				continue
+			}
+			filled++
+		}
+	}
+	return filled, nil
+}
+
+// mark replaces all gaps in the DB starting at or after `since` with `gaps`.
+func (a *Analyzer) mark(ctx context.Context, since time.Time, gaps []Gap) error {
+	merger := &db.SnapshotGaps{
+		Since: db.PGTimestamp(since),
+		Gaps:  make([]gen.SnapshotGap, len(gaps)),
+	}
+	for i, gap := range gaps {
+		merger.Gaps[i] = gen.SnapshotGap{
+			StartTime:  db.PGTimestamp(gap.Start),
+			EndTime:    db.PGTimestamp(gap.End),
+			MissedRuns: int32(gap.Missed),
+		}
+	}
+	a.log.InfoContext(ctx, "marking gaps in database")
+	return a.db.Merge(ctx, stats.NewCollector(), []db.Entity{merger})
+}
+
+// printGaps writes `gaps` to `out` as a human-readable table.
+func printGaps(out io.Writer, gaps []Gap) error {
+	w := table.NewWriter()
+	w.AppendHeader(table.Row{"Start", "End", "Duration", "Missed Runs"})
+	total := 0
+	for _, gap := range gaps {
+		w.AppendRow(table.Row{gap.Start, gap.End, gap.End.Sub(gap.Start).Round(time.Second), gap.Missed})
+		total += gap.Missed
+	}
+	w.AppendSeparator()
+	w.AppendFooter(table.Row{"Total", len(gaps), "", total})
+	w.SetStyle(table.StyleLight)
+
+	_, err := fmt.Fprintln(out, w.Render())
+	return err
+}
@@ -0,0 +1,49 @@
+// Package gaps detects missing snapshots, i.e. scheduled snapshot runs which did not produce a snapshot.
+package gaps
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Gap is an interval between two consecutive snapshots in which scheduled snapshots are missing.
+type Gap struct {
+	// Start is the time of the last snapshot before the gap
+	Start time.Time
+	// End is the time of the first snapshot after the gap
+	End time.Time
+	// Missed is the number of scheduled runs which did not produce a snapshot
+	Missed int
+}
+
+// Detect returns all gaps between the snapshots taken at `times` (in ascending order) according to `schedule`.
+//
+// Snapshot times are reported by the API and therefore never later than the run which created them.
+// Each snapshot is attributed to the first scheduled run at or after its time, every run in
+// between the runs of two consecutive snapshots is considered missed.
+//
+// Runs after the latest snapshot are not considered, since the next run might still be pending.
+func Detect(times []time.Time, schedule cron.Schedule) []Gap {
+	var gaps []Gap
+	for i := 1; i < len(times); i++ {
+		start, end := times[i-1], times[i]
+		startRun, endRun := runAt(schedule, start), runAt(schedule, end)
+
+		missed := 0
+		for run := schedule.Next(startRun); !run.IsZero() && run.Before(endRun); run = schedule.Next(run) {
+			missed++
+		}
+		if missed > 0 {
+			gaps = append(gaps, Gap{Start: start, End: end, Missed: missed})
+		}
+	}
+	return gaps
+}
+
+// runAt returns the first scheduled run at or after `t`.
+func runAt(schedule cron.Schedule, t time.Time) time.Time {
+	// the scheduler runs in local time and only supports whole seconds
+	t = t.In(time.Local).Add(-time.Nanosecond).Truncate(time.Second)
+	return schedule.Next(t)
+}
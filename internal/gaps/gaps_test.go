@@ -0,0 +1,77 @@
+package gaps
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+func TestDetect(t *testing.T) {
+	schedule, err := cron.ParseStandard("*/5 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes, seconds int) time.Time {
+		return base.Add(time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
+	}
+
+	tests := []struct {
+		name  string
+		times []time.Time
+		want  []Gap
+	}{
+		{
+			name:  "empty",
+			times: nil,
+			want:  nil,
+		},
+		{
+			name:  "single",
+			times: []time.Time{at(0, 0)},
+			want:  nil,
+		},
+		{
+			name:  "regular",
+			times: []time.Time{at(0, 0), at(5, 0), at(10, 0)},
+			want:  nil,
+		},
+		{
+			name:  "regular with varying API delay",
+			times: []time.Time{at(-4, 0), at(5, 0), at(9, 30), at(11, 0), at(15, 0)},
+			want:  nil,
+		},
+		{
+			name:  "sub-second times",
+			times: []time.Time{at(0, 0).Add(-time.Millisecond), at(0, 0).Add(time.Millisecond)},
+			want:  nil,
+		},
+		{
+			name:  "single missed run",
+			times: []time.Time{at(0, 0), at(10, 0)},
+			want:  []Gap{{Start: at(0, 0), End: at(10, 0), Missed: 1}},
+		},
+		{
+			name:  "missed runs with API delay",
+			times: []time.Time{at(-1, 0), at(14, 50)},
+			want:  []Gap{{Start: at(-1, 0), End: at(14, 50), Missed: 2}},
+		},
+		{
+			name:  "multiple gaps",
+			times: []time.Time{at(0, 0), at(5, 0), at(60, 0), at(65, 0), at(75, 0)},
+			want: []Gap{
+				{Start: at(5, 0), End: at(60, 0), Missed: 10},
+				{Start: at(65, 0), End: at(75, 0), Missed: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.times, schedule); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Detect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
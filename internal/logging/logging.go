@@ -0,0 +1,95 @@
+// Package logging configures structured logging with log/slog.
+//
+// All packages log through a *slog.Logger carrying a component attribute.
+// Attributes which identify the subject of a message use the keys defined here,
+// so that records of different components can be correlated.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// keys of attributes shared between components
+const (
+	KeyComponent = "component"
+	KeyTable     = "table"
+	KeyEntityID  = "entity_id"
+	KeyEndpoint  = "endpoint"
+	KeyRunID     = "run_id"
+	KeyError     = "error"
+)
+
+// formats of the log output
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// New creates a logger writing records of at least `level` to `w` in `format`.
+//
+// The sync run ID attached to a context with WithRunID is added to all records logged with that context.
+func New(w io.Writer, format string, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q, must be one of %s, %s", format, FormatText, FormatJSON)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+// Discard returns a logger which drops all records, e.g. for tests.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// Err returns an attribute for `err`.
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
+
+type runIDKey struct{}
+
+// WithRunID returns a context whose log records are attributed to the sync run with `id`.
+func WithRunID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, id)
+}
+
+// RunID returns the sync run ID attached to `ctx` or an empty string if there is none.
+func RunID(ctx context.Context) string {
+	id, _ := ctx.Value(runIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds attributes stored in the context to each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RunID(ctx); id != "" {
+		r.AddAttrs(slog.String(KeyRunID, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
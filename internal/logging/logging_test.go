@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		level   string
+		wantErr bool
+	}{
+		{name: "text", format: "text", level: "info"},
+		{name: "json uppercase", format: "JSON", level: "DEBUG"},
+		{name: "invalid format", format: "xml", level: "info", wantErr: true},
+		{name: "invalid level", format: "text", level: "verbose", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(&bytes.Buffer{}, tt.format, tt.level); (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatJSON, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger = logger.With(KeyComponent, "worker")
+
+	ctx := WithRunID(context.Background(), "abc")
+	logger.InfoContext(ctx, "with run ID", KeyEndpoint, "war")
+	logger.Info("without run ID")
+	logger.DebugContext(ctx, "below level")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d records, want 2:\n%s", len(lines), buf.String())
+	}
+	var first, second map[string]any
+	if err = json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatal(err)
+	}
+	if first[KeyRunID] != "abc" || first[KeyComponent] != "worker" || first[KeyEndpoint] != "war" {
+		t.Errorf("first record = %v, want run ID, component and endpoint", first)
+	}
+	if _, ok := second[KeyRunID]; ok {
+		t.Errorf("second record = %v, want no run ID", second)
+	}
+	if got := RunID(context.Background()); got != "" {
+		t.Errorf("RunID() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,197 @@
+// Package metrics exposes Prometheus metrics about syncs, API requests and merged rows.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+)
+
+const namespace = "helldivers_client"
+
+// Metrics records measurements and serves them in the Prometheus exposition format.
+//
+// All methods can be called on a nil *Metrics, in which case nothing is recorded.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	syncDuration    *prometheus.HistogramVec
+	lastSuccess     *prometheus.GaugeVec
+	requestDuration *prometheus.HistogramVec
+	requests        *prometheus.CounterVec
+	retries         *prometheus.CounterVec
+	rateLimited     *prometheus.CounterVec
+	mergedRows      *prometheus.CounterVec
+
+	// domain gauges are nil if disabled
+	playerCount  prometheus.Gauge
+	planetHealth *prometheus.GaugeVec
+}
+
+// New creates a new Metrics instance with its own registry.
+//
+// If `domain` is true, gauges describing the war itself (player count, planet health) are exported as well.
+func New(domain bool) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		syncDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "sync_duration_seconds",
+			Help:      "Duration of sync job runs, including fetching and merging.",
+			Buckets:   []float64{1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+		}, []string{"job", "outcome"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_successful_sync_timestamp_seconds",
+			Help:      "Unix time at which the sync job last finished successfully.",
+		}, []string{"job"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "api_request_duration_seconds",
+			Help:      "Latency of single API request attempts.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_requests_total",
+			Help:      "API request attempts by HTTP status code, \"error\" if no response was received.",
+		}, []string{"endpoint", "status"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_retries_total",
+			Help:      "Retried API requests.",
+		}, []string{"endpoint"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_rate_limited_total",
+			Help:      "API requests rejected with HTTP 429.",
+		}, []string{"endpoint"}),
+		mergedRows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "merged_rows_total",
+			Help:      "Rows merged into the sinks by table and result (inserted, updated, noop).",
+		}, []string{"table", "result"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.syncDuration,
+		m.lastSuccess,
+		m.requestDuration,
+		m.requests,
+		m.retries,
+		m.rateLimited,
+		m.mergedRows,
+	)
+
+	if domain {
+		m.playerCount = prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "war_player_count",
+			Help:      "Number of players in the current war, as of the latest snapshot.",
+		})
+		m.planetHealth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "planet_health",
+			Help:      "Health of planets with active campaigns, as of the latest snapshot.",
+		}, []string{"planet_id", "planet"})
+		m.registry.MustRegister(m.playerCount, m.planetHealth)
+	}
+	return m
+}
+
+// Handler returns the HTTP handler serving all metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveSync records a finished run of the sync job `job`.
+func (m *Metrics) ObserveSync(job string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	m.syncDuration.WithLabelValues(job, outcome).Observe(d.Seconds())
+	if err == nil {
+		m.lastSuccess.WithLabelValues(job).SetToCurrentTime()
+	}
+}
+
+// ObserveRequest records a single API request attempt to `endpoint`.
+// A status of 0 means that no response was received.
+func (m *Metrics) ObserveRequest(endpoint string, status int, d time.Duration) {
+	if m == nil {
+		return
+	}
+	statusLabel := "error"
+	if status != 0 {
+		statusLabel = strconv.Itoa(status)
+	}
+	m.requestDuration.WithLabelValues(endpoint).Observe(d.Seconds())
+	m.requests.WithLabelValues(endpoint, statusLabel).Inc()
+}
+
+// ObserveRetry records that a request to `endpoint` is retried.
+func (m *Metrics) ObserveRetry(endpoint string) {
+	if m == nil {
+		return
+	}
+	m.retries.WithLabelValues(endpoint).Inc()
+}
+
+// ObserveRateLimited records that a request to `endpoint` was rejected with HTTP 429.
+func (m *Metrics) ObserveRateLimited(endpoint string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(endpoint).Inc()
+}
+
+// ObserveMerge adds the per-table statistics of a merge to the row counters.
+func (m *Metrics) ObserveMerge(collector stats.Collector) {
+	if m == nil {
+		return
+	}
+	for table, s := range collector {
+		m.mergedRows.WithLabelValues(table.String(), "inserted").Add(float64(s.Inserted))
+		m.mergedRows.WithLabelValues(table.String(), "updated").Add(float64(s.Updated))
+		m.mergedRows.WithLabelValues(table.String(), "noop").Add(float64(s.Noop))
+	}
+}
+
+// PlanetHealth is the current health of a single planet.
+type PlanetHealth struct {
+	ID     int32
+	Name   string
+	Health int64
+}
+
+// SetPlayerCount updates the player count gauge, if domain gauges are enabled.
+func (m *Metrics) SetPlayerCount(n uint64) {
+	if m == nil || m.playerCount == nil {
+		return
+	}
+	m.playerCount.Set(float64(n))
+}
+
+// SetPlanetHealth replaces the planet health gauges, if domain gauges are enabled.
+//
+// Planets not contained in `planets` are removed, so that planets without active campaigns are not reported forever.
+func (m *Metrics) SetPlanetHealth(planets []PlanetHealth) {
+	if m == nil || m.planetHealth == nil {
+		return
+	}
+	m.planetHealth.Reset()
+	for _, p := range planets {
+		m.planetHealth.WithLabelValues(strconv.Itoa(int(p.ID)), p.Name).Set(float64(p.Health))
+	}
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+)
+
+func TestObserveSync(t *testing.T) {
+	m := New(false)
+	m.ObserveSync("snapshot", time.Second, nil)
+	m.ObserveSync("snapshot", time.Second, errors.New("foo"))
+
+	if got := testutil.ToFloat64(m.lastSuccess.WithLabelValues("snapshot")); got == 0 {
+		t.Error("last successful sync timestamp not set")
+	}
+	if got := testutil.CollectAndCount(m.syncDuration); got != 2 {
+		t.Errorf("got %d sync duration series, want one per outcome", got)
+	}
+}
+
+func TestObserveRequest(t *testing.T) {
+	m := New(false)
+	m.ObserveRequest("/api/v1/war", 200, time.Millisecond)
+	m.ObserveRequest("/api/v1/war", 0, time.Millisecond)
+	m.ObserveRequest("/api/v1/war", 429, time.Millisecond)
+	m.ObserveRateLimited("/api/v1/war")
+	m.ObserveRetry("/api/v1/war")
+	m.ObserveRetry("/api/v1/war")
+
+	tests := []struct {
+		status string
+		want   float64
+	}{
+		{"200", 1},
+		{"429", 1},
+		{"error", 1},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(m.requests.WithLabelValues("/api/v1/war", tt.status)); got != tt.want {
+			t.Errorf("requests with status %s = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+	if got := testutil.ToFloat64(m.retries.WithLabelValues("/api/v1/war")); got != 2 {
+		t.Errorf("retries = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.rateLimited.WithLabelValues("/api/v1/war")); got != 1 {
+		t.Errorf("rate limited = %v, want 1", got)
+	}
+}
+
+func TestObserveMerge(t *testing.T) {
+	m := New(false)
+	for i := 0; i < 2; i++ {
+		collector := stats.NewCollector()
+		collector.Inserted(gen.TablePlanets, 3)
+		collector.Updated(gen.TablePlanets, 2)
+		collector.Noop(gen.TableWars, 1)
+		m.ObserveMerge(collector)
+	}
+
+	tests := []struct {
+		table  gen.Table
+		result string
+		want   float64
+	}{
+		{gen.TablePlanets, "inserted", 6},
+		{gen.TablePlanets, "updated", 4},
+		{gen.TablePlanets, "noop", 0},
+		{gen.TableWars, "noop", 2},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(m.mergedRows.WithLabelValues(tt.table.String(), tt.result)); got != tt.want {
+			t.Errorf("%s rows %s = %v, want %v", tt.table, tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestDomainGauges(t *testing.T) {
+	m := New(true)
+	m.SetPlayerCount(1234)
+	m.SetPlanetHealth([]PlanetHealth{{ID: 1, Name: "Foo", Health: 100}, {ID: 2, Name: "Bar", Health: 200}})
+	m.SetPlanetHealth([]PlanetHealth{{ID: 2, Name: "Bar", Health: 150}})
+
+	want := `
+# HELP helldivers_client_planet_health Health of planets with active campaigns, as of the latest snapshot.
+# TYPE helldivers_client_planet_health gauge
+helldivers_client_planet_health{planet="Bar",planet_id="2"} 150
+# HELP helldivers_client_war_player_count Number of players in the current war, as of the latest snapshot.
+# TYPE helldivers_client_war_player_count gauge
+helldivers_client_war_player_count 1234
+`
+	if err := testutil.GatherAndCompare(m.registry, strings.NewReader(want), "helldivers_client_planet_health", "helldivers_client_war_player_count"); err != nil {
+		t.Error(err)
+	}
+
+	// disabled domain gauges are not exported
+	m = New(false)
+	m.SetPlayerCount(1234)
+	if n, err := testutil.GatherAndCount(m.registry, "helldivers_client_war_player_count"); err != nil || n != 0 {
+		t.Errorf("got %d player count series (err %v), want none", n, err)
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+	// must not panic
+	m.ObserveSync("static", time.Second, nil)
+	m.ObserveRequest("/api/v1/war", 200, time.Second)
+	m.ObserveRetry("/api/v1/war")
+	m.ObserveRateLimited("/api/v1/war")
+	m.ObserveMerge(stats.NewCollector())
+	m.SetPlayerCount(1)
+	m.SetPlanetHealth(nil)
+}
@@ -0,0 +1,180 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+)
+
+// maxLineSize limits the size of a single record when reading the file, snapshots can get large.
+const maxLineSize = 64 << 20
+
+// JSONL appends every entity as a single JSON line to a file.
+//
+// The file is never rewritten, so updated entities appear multiple times.
+// Consumers should use the latest record of each entity.
+type JSONL struct {
+	mu   sync.Mutex
+	file *os.File
+	// latest is the most recent snapshot written to the file, nil if there is none
+	latest *snapshotRef
+	log    *slog.Logger
+}
+
+// record is a single line of the file.
+type record struct {
+	// Time is when the record was written
+	Time time.Time `json:"time"`
+	// Kind is the type of the entity, e.g. "Planet" or "Snapshot"
+	Kind   string          `json:"kind"`
+	Entity json.RawMessage `json:"entity"`
+}
+
+const kindSnapshot = "Snapshot"
+
+// snapshotRef is the part of a snapshot required to answer queries.
+type snapshotRef struct {
+	CreateTime  pgtype.Timestamp
+	WarSnapshot struct {
+		WarID int32
+	}
+}
+
+// NewJSONL opens the file at `path` for appending, creating it if necessary.
+//
+// Existing records are read to determine the latest snapshot.
+func NewJSONL(path string, logger *slog.Logger) (*JSONL, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening JSONL file: %w", err)
+	}
+	latest, err := readLatestSnapshot(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("reading JSONL file %s: %w", path, err)
+	}
+	return &JSONL{file: file, latest: latest, log: logger}, nil
+}
+
+func readLatestSnapshot(r io.Reader) (*snapshotRef, error) {
+	var latest *snapshotRef
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if rec.Kind != kindSnapshot {
+			continue
+		}
+		ref := new(snapshotRef)
+		if err := json.Unmarshal(rec.Entity, ref); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if latest == nil || ref.CreateTime.Time.After(latest.CreateTime.Time) {
+			latest = ref
+		}
+	}
+	return latest, scanner.Err()
+}
+
+// Close closes the underlying file.
+func (s *JSONL) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// Merge implements Sink.
+//
+// All records are written at once and synced to disk.
+// Statistics are not collected, since every entity is simply appended.
+func (s *JSONL) Merge(ctx context.Context, _ stats.Collector, entities ...[]db.Entity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	var (
+		buf    bytes.Buffer
+		n      int
+		latest = s.latest
+	)
+	enc := json.NewEncoder(&buf)
+	for _, eSlice := range entities {
+		for _, entity := range eSlice {
+			encoded, err := json.Marshal(entity)
+			if err != nil {
+				return fmt.Errorf("encoding %T: %w", entity, err)
+			}
+			if err = enc.Encode(record{Time: now, Kind: kindOf(entity), Entity: encoded}); err != nil {
+				return fmt.Errorf("encoding %T: %w", entity, err)
+			}
+			n++
+			if snap, ok := entity.(*db.Snapshot); ok && (latest == nil || snap.CreateTime.Time.After(latest.CreateTime.Time)) {
+				latest = &snapshotRef{CreateTime: snap.CreateTime}
+				latest.WarSnapshot.WarID = snap.WarSnapshot.WarID
+			}
+		}
+	}
+
+	if _, err := s.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing JSONL file: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("syncing JSONL file: %w", err)
+	}
+	s.latest = latest
+	s.log.InfoContext(ctx, "appended records", "records", n, "path", s.file.Name())
+	return nil
+}
+
+func (s *JSONL) latestRef() *snapshotRef {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest
+}
+
+// kindOf returns the name of the type of `entity`, e.g. "Planet" for *db.Planet.
+func kindOf(entity db.Entity) string {
+	return reflect.Indirect(reflect.ValueOf(entity)).Type().Name()
+}
+
+// LatestSnapshotTime implements Sink.
+func (s *JSONL) LatestSnapshotTime(_ context.Context) (time.Time, bool, error) {
+	latest := s.latestRef()
+	if latest == nil {
+		return time.Time{}, false, nil
+	}
+	return latest.CreateTime.Time, true, nil
+}
+
+// LatestWarID implements Sink.
+func (s *JSONL) LatestWarID(_ context.Context) (int32, bool, error) {
+	latest := s.latestRef()
+	if latest == nil {
+		return 0, false, nil
+	}
+	return latest.WarSnapshot.WarID, true, nil
+}
+
+// WarSummary implements Sink.
+//
+// Wars are not summarized, consumers can derive summaries from the snapshot records.
+func (s *JSONL) WarSummary(_ context.Context, _ int32) (gen.WarSummary, bool, error) {
+	return gen.WarSummary{}, false, nil
+}
@@ -0,0 +1,102 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+	"github.com/stnokott/helldivers-client/internal/logging"
+)
+
+func snapshotAt(t time.Time, warID int32) *db.Snapshot {
+	return &db.Snapshot{
+		Snapshot:    gen.Snapshot{CreateTime: db.PGTimestamp(t)},
+		WarSnapshot: gen.WarSnapshot{WarID: warID},
+	}
+}
+
+func TestJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+	logger := logging.Discard()
+	ctx := context.Background()
+	base := time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC)
+
+	s, err := NewJSONL(path, logger)
+	if err != nil {
+		t.Fatalf("NewJSONL() error = %v", err)
+	}
+	if _, ok, err := s.LatestSnapshotTime(ctx); ok || err != nil {
+		t.Errorf("LatestSnapshotTime() of empty file = ok %v, error %v, want false, nil", ok, err)
+	}
+
+	mergers := []db.Entity{
+		&db.War{ID: 801},
+		snapshotAt(base.Add(5*time.Minute), 801),
+		snapshotAt(base, 801),
+	}
+	if err = s.Merge(ctx, stats.NewCollector(), mergers, []db.Entity{&db.Dispatch{ID: 3}}); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if latest, ok, err := s.LatestSnapshotTime(ctx); !ok || err != nil || !latest.Equal(base.Add(5*time.Minute)) {
+		t.Errorf("LatestSnapshotTime() = %v, %v, %v, want %v, true, nil", latest, ok, err, base.Add(5*time.Minute))
+	}
+	if err = s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var kinds []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var rec record
+		if err = json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid record %s: %v", scanner.Text(), err)
+		}
+		kinds = append(kinds, rec.Kind)
+	}
+	if want := []string{"War", "Snapshot", "Snapshot", "Dispatch"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("record kinds = %v, want %v", kinds, want)
+	}
+
+	// reopening restores the latest snapshot
+	s, err = NewJSONL(path, logger)
+	if err != nil {
+		t.Fatalf("NewJSONL() of existing file error = %v", err)
+	}
+	defer s.Close()
+	if id, ok, err := s.LatestWarID(ctx); !ok || err != nil || id != 801 {
+		t.Errorf("LatestWarID() = %d, %v, %v, want 801, true, nil", id, ok, err)
+	}
+	if latest, _, _ := s.LatestSnapshotTime(ctx); !latest.Equal(base.Add(5 * time.Minute)) {
+		t.Errorf("LatestSnapshotTime() = %v, want %v", latest, base.Add(5*time.Minute))
+	}
+
+	if err = s.Merge(ctx, stats.NewCollector(), []db.Entity{snapshotAt(base.Add(10*time.Minute), 802)}); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if id, _, _ := s.LatestWarID(ctx); id != 802 {
+		t.Errorf("LatestWarID() = %d, want 802", id)
+	}
+}
+
+func TestNewJSONLInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+	if err := os.WriteFile(path, []byte("{\"kind\":\"Snapshot\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJSONL(path, logging.Discard()); err == nil {
+		t.Error("NewJSONL() of corrupt file error = nil, want error")
+	}
+}
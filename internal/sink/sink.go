@@ -0,0 +1,89 @@
+// Package sink provides the destinations the synced API data is written to.
+//
+// The PostgreSQL client in package db is the primary implementation,
+// the SQLite client in package sqlite stores the same data for deployments without a database server.
+// Other sinks receive the same typed entities, e.g. to append them to a file.
+package sink
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/sqlite"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+)
+
+// names of the available sinks, as used in the configuration
+const (
+	NamePostgres = "postgres"
+	NameSQLite   = "sqlite"
+	NameJSONL    = "jsonl"
+)
+
+// Sink stores the entities transformed from API data.
+type Sink interface {
+	// Merge stores all `entities` at once, adding insert/update statistics to `collector`.
+	//
+	// Merging the same entities again must be safe, since failed syncs are retried with the same data.
+	Merge(ctx context.Context, collector stats.Collector, entities ...[]db.Entity) error
+	// LatestSnapshotTime returns the time of the most recent snapshot.
+	//
+	// `ok` is false if no snapshot exists yet.
+	LatestSnapshotTime(ctx context.Context) (t time.Time, ok bool, err error)
+	// LatestWarID returns the ID of the war of the most recent snapshot.
+	//
+	// `ok` is false if no snapshot exists yet.
+	LatestWarID(ctx context.Context) (id int32, ok bool, err error)
+	// WarSummary returns the summary of a closed war.
+	//
+	// `ok` is false if the war has not been closed or the sink does not summarize wars.
+	WarSummary(ctx context.Context, warID int32) (summary gen.WarSummary, ok bool, err error)
+}
+
+// compile-time implementation checks
+var (
+	_ Sink = (*db.Client)(nil)
+	_ Sink = (*sqlite.Client)(nil)
+	_ Sink = (*JSONL)(nil)
+	_ Sink = Multi(nil)
+)
+
+// Multi fans out to multiple sinks in order.
+//
+// The first sink is the primary one, it answers all queries and collects the statistics.
+type Multi []Sink
+
+// Merge implements Sink.
+//
+// Merging stops at the first failing sink, so that all sinks receive the same data once the sync is retried.
+func (m Multi) Merge(ctx context.Context, collector stats.Collector, entities ...[]db.Entity) error {
+	for i, s := range m {
+		c := collector
+		if i > 0 {
+			// statistics of secondary sinks would count every entity twice
+			c = stats.NewCollector()
+		}
+		if err := s.Merge(ctx, c, entities...); err != nil {
+			return fmt.Errorf("merging into sink %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// LatestSnapshotTime implements Sink.
+func (m Multi) LatestSnapshotTime(ctx context.Context) (time.Time, bool, error) {
+	return m[0].LatestSnapshotTime(ctx)
+}
+
+// LatestWarID implements Sink.
+func (m Multi) LatestWarID(ctx context.Context) (int32, bool, error) {
+	return m[0].LatestWarID(ctx)
+}
+
+// WarSummary implements Sink.
+func (m Multi) WarSummary(ctx context.Context, warID int32) (gen.WarSummary, bool, error) {
+	return m[0].WarSummary(ctx, warID)
+}
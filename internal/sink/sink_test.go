@@ -0,0 +1,80 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+)
+
+// fakeSink records merged entities and reports a fixed latest snapshot.
+type fakeSink struct {
+	merged    int
+	collector stats.Collector
+	latest    time.Time
+	err       error
+}
+
+func (f *fakeSink) Merge(_ context.Context, collector stats.Collector, mergers ...[]db.Entity) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.collector = collector
+	for _, mSlice := range mergers {
+		f.merged += len(mSlice)
+	}
+	return nil
+}
+
+func (f *fakeSink) LatestSnapshotTime(context.Context) (time.Time, bool, error) {
+	return f.latest, !f.latest.IsZero(), nil
+}
+
+func (f *fakeSink) LatestWarID(context.Context) (int32, bool, error) {
+	return 0, false, nil
+}
+
+func (f *fakeSink) WarSummary(context.Context, int32) (gen.WarSummary, bool, error) {
+	return gen.WarSummary{}, false, nil
+}
+
+func TestMulti(t *testing.T) {
+	ctx := context.Background()
+	mergers := []db.Entity{&db.War{ID: 801}, &db.Dispatch{ID: 3}}
+
+	t.Run("fan out", func(t *testing.T) {
+		primary := &fakeSink{latest: time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC)}
+		secondary := &fakeSink{}
+		multi := Multi{primary, secondary}
+
+		collector := stats.NewCollector()
+		if err := multi.Merge(ctx, collector, mergers); err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		if primary.merged != 2 || secondary.merged != 2 {
+			t.Errorf("merged entities = %d, %d, want 2 each", primary.merged, secondary.merged)
+		}
+		collector[gen.TableWars].Inserted = 1
+		if primary.collector[gen.TableWars].Inserted != 1 || secondary.collector[gen.TableWars].Inserted != 0 {
+			t.Error("Merge() did not pass collector to primary sink only")
+		}
+		if latest, ok, _ := multi.LatestSnapshotTime(ctx); !ok || !latest.Equal(primary.latest) {
+			t.Errorf("LatestSnapshotTime() = %v, %v, want time of primary sink", latest, ok)
+		}
+	})
+
+	t.Run("stops at failing sink", func(t *testing.T) {
+		failing := &fakeSink{err: errors.New("disk full")}
+		secondary := &fakeSink{}
+		if err := (Multi{failing, secondary}).Merge(ctx, stats.NewCollector(), mergers); err == nil {
+			t.Fatal("Merge() error = nil, want error")
+		}
+		if secondary.merged != 0 {
+			t.Errorf("secondary sink merged %d entities after failure, want 0", secondary.merged)
+		}
+	})
+}
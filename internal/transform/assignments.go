@@ -10,18 +10,23 @@ import (
 )
 
 // Assignments converts API data into mergable DB entities.
-func Assignments(c Converter, data APIData) ([]db.EntityMerger, error) {
+func Assignments(c Converter, data APIData) ([]db.Entity, error) {
 	if data.Assignments == nil {
 		return nil, errors.New("got nil assignments slice")
 	}
+	warID, err := MustWarID(data.WarID)
+	if err != nil {
+		return nil, err
+	}
 
 	src := *data.Assignments
-	mergers := make([]db.EntityMerger, len(src))
+	mergers := make([]db.Entity, len(src))
 	for i, assignment := range src {
 		a, err := c.ConvertAssignment(assignment)
 		if err != nil {
 			return nil, err
 		}
+		a.WarID = warID
 		mergers[i] = a
 	}
 	return mergers, nil
@@ -72,7 +72,7 @@ func TestAssignments(t *testing.T) {
 	tests := []struct {
 		name     string
 		modifier modifier
-		want     []db.EntityMerger
+		want     []db.Entity
 		wantErr  bool
 	}{
 		{
@@ -80,7 +80,7 @@ func TestAssignments(t *testing.T) {
 			modifier: func(a *api.Assignment2) {
 				// keep valid
 			},
-			want: []db.EntityMerger{
+			want: []db.Entity{
 				&db.Assignment{
 					Assignment: gen.Assignment{
 						ID:           7,
@@ -90,6 +90,7 @@ func TestAssignments(t *testing.T) {
 						Expiration:   db.PGTimestamp(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
 						RewardType:   3,
 						RewardAmount: db.PGUint64(100),
+						WarID:        801,
 					},
 					Tasks: []gen.AssignmentTask{
 						{
@@ -140,6 +141,7 @@ func TestAssignments(t *testing.T) {
 			// call modifier on valid assignment copy
 			tt.modifier(&assignment)
 			data := APIData{
+				WarID: &testWarID,
 				Assignments: &[]api.Assignment2{
 					assignment,
 				},
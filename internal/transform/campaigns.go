@@ -2,24 +2,95 @@ package transform
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/stnokott/helldivers-client/internal/api"
 	"github.com/stnokott/helldivers-client/internal/db"
 )
 
 // Campaigns converts API data into mergable DB entities.
-func Campaigns(c Converter, data APIData) ([]db.EntityMerger, error) {
+func Campaigns(c Converter, data APIData) ([]db.Entity, error) {
 	if data.Campaigns == nil {
 		return nil, errors.New("got nil campaigns slice")
 	}
+	warID, err := MustWarID(data.WarID)
+	if err != nil {
+		return nil, err
+	}
 
 	src := *data.Campaigns
-	mergers := make([]db.EntityMerger, len(src))
+	mergers := make([]db.Entity, len(src))
 	for i, campaign := range src {
 		merger, err := c.ConvertCampaign(campaign)
 		if err != nil {
 			return nil, err
 		}
+		merger.WarID = warID
 		mergers[i] = merger
 	}
 	return mergers, nil
 }
+
+// CampaignLifecycles converts API data into a mergable DB entity tracking which campaigns are active.
+//
+// The campaigns are considered active at the time of the API data, i.e. the time of the snapshot.
+// The owners of all queried planets are included, so that the outcomes of ended campaigns can be resolved.
+func CampaignLifecycles(_ Converter, data APIData) ([]db.Entity, error) {
+	if data.Campaigns == nil {
+		return nil, errors.New("got nil campaigns slice")
+	}
+	warID, err := MustWarID(data.WarID)
+	if err != nil {
+		return nil, err
+	}
+	var now *time.Time
+	if data.War != nil {
+		now = data.War.Now
+	}
+	seen, err := MustTimestamp(now)
+	if err != nil {
+		return nil, fmt.Errorf("War.Now: %w", err)
+	}
+
+	src := *data.Campaigns
+	lifecycles := &db.CampaignLifecycles{
+		WarID:       warID,
+		Seen:        seen,
+		CampaignIDs: make([]int32, len(src)),
+	}
+	for i, campaign := range src {
+		id, err := MustInt32Ptr(campaign.Id)
+		if err != nil {
+			return nil, fmt.Errorf("Campaign ID: %w", err)
+		}
+		lifecycles.CampaignIDs[i] = id
+	}
+	if data.Planets != nil {
+		lifecycles.PlanetOwners = make(map[int32]string, len(*data.Planets))
+		for _, planet := range *data.Planets {
+			index, err := MustInt32Ptr(planet.Index)
+			if err != nil {
+				return nil, fmt.Errorf("Planet Index: %w", err)
+			}
+			owner, err := MustString(planet.CurrentOwner)
+			if err != nil {
+				return nil, fmt.Errorf("Planet CurrentOwner: %w", err)
+			}
+			lifecycles.PlanetOwners[index] = owner
+		}
+	}
+	return []db.Entity{lifecycles}, nil
+}
+
+// CampaignPlanetID returns the index of the planet a campaign is fought on or nil if the planet is missing.
+func CampaignPlanetID(source *api.Campaign2_Planet) (*int32, error) {
+	if source == nil {
+		return nil, nil
+	}
+	planet, err := source.AsPlanet()
+	if err != nil {
+		return nil, fmt.Errorf("parse Campaign Planet: %w", err)
+	}
+	return planet.Index, nil
+}
@@ -5,6 +5,7 @@ package transform
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stnokott/helldivers-client/internal/api"
 	"github.com/stnokott/helldivers-client/internal/copytest"
@@ -23,7 +24,7 @@ func TestCampaigns(t *testing.T) {
 	tests := []struct {
 		name     string
 		modifier modifier
-		want     []db.EntityMerger
+		want     []db.Entity
 		wantErr  bool
 	}{
 		{
@@ -31,15 +32,58 @@ func TestCampaigns(t *testing.T) {
 			modifier: func(a *api.Campaign2) {
 				// keep valid
 			},
-			want: []db.EntityMerger{
+			want: []db.Entity{
 				&db.Campaign{
 					ID:    987,
 					Type:  7,
 					Count: db.PGUint64(123),
+					WarID: 801,
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "with planet",
+			modifier: func(c *api.Campaign2) {
+				c.Planet = campaignPlanet(t, api.Planet{Index: ptr(int32(456))})
+			},
+			want: []db.Entity{
+				&db.Campaign{
+					ID:       987,
+					Type:     7,
+					Count:    db.PGUint64(123),
+					WarID:    801,
+					PlanetID: ptr(int32(456)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "planet without index",
+			modifier: func(c *api.Campaign2) {
+				c.Planet = campaignPlanet(t, api.Planet{})
+			},
+			want: []db.Entity{
+				&db.Campaign{
+					ID:    987,
+					Type:  7,
+					Count: db.PGUint64(123),
+					WarID: 801,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid planet",
+			modifier: func(c *api.Campaign2) {
+				c.Planet = &api.Campaign2_Planet{}
+				if err := c.Planet.UnmarshalJSON([]byte(`"Malevelon Creek"`)); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "missing required type",
 			modifier: func(c *api.Campaign2) {
@@ -59,6 +103,7 @@ func TestCampaigns(t *testing.T) {
 			// call modifier on valid assignment copy
 			tt.modifier(&campaign)
 			data := APIData{
+				WarID: &testWarID,
 				Campaigns: &[]api.Campaign2{
 					campaign,
 				},
@@ -75,3 +120,59 @@ func TestCampaigns(t *testing.T) {
 		})
 	}
 }
+
+func campaignPlanet(t *testing.T, planet api.Planet) *api.Campaign2_Planet {
+	t.Helper()
+	var union api.Campaign2_Planet
+	if err := union.FromPlanet(planet); err != nil {
+		t.Fatalf("failed to create campaign planet: %v", err)
+	}
+	return &union
+}
+
+func TestCampaignsMissingWarID(t *testing.T) {
+	data := APIData{
+		Campaigns: &[]api.Campaign2{validCampaign},
+	}
+	if _, err := Campaigns(&ConverterImpl{}, data); err == nil {
+		t.Error("Campaigns() err = nil, want error for missing war ID")
+	}
+}
+
+func TestCampaignLifecycles(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	other := validCampaign
+	other.Id = ptr(int32(988))
+
+	data := APIData{
+		WarID:     &testWarID,
+		War:       &api.War{Now: &now},
+		Campaigns: &[]api.Campaign2{validCampaign, other},
+		Planets:   &[]api.Planet{{Index: ptr(int32(123)), CurrentOwner: ptr("Humans")}},
+	}
+	got, err := CampaignLifecycles(&ConverterImpl{}, data)
+	if err != nil {
+		t.Fatalf("CampaignLifecycles() err = %v, want nil", err)
+	}
+	want := []db.Entity{
+		&db.CampaignLifecycles{
+			WarID:        801,
+			Seen:         db.PGTimestamp(now),
+			CampaignIDs:  []int32{987, 988},
+			PlanetOwners: map[int32]string{123: "Humans"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CampaignLifecycles() = %v, want %v", got, want)
+	}
+
+	data.Planets = &[]api.Planet{{Index: ptr(int32(123))}}
+	if _, err = CampaignLifecycles(&ConverterImpl{}, data); err == nil {
+		t.Error("CampaignLifecycles() err = nil, want error for missing planet owner")
+	}
+
+	data.War = nil
+	if _, err = CampaignLifecycles(&ConverterImpl{}, data); err == nil {
+		t.Error("CampaignLifecycles() err = nil, want error for missing time")
+	}
+}
@@ -8,13 +8,13 @@ import (
 )
 
 // Dispatches converts API data into mergable DB entities.
-func Dispatches(c Converter, data APIData) ([]db.EntityMerger, error) {
+func Dispatches(c Converter, data APIData) ([]db.Entity, error) {
 	if data.Dispatches == nil {
 		return nil, errors.New(("got nil dispatches slice"))
 	}
 
 	src := *data.Dispatches
-	mergers := make([]db.EntityMerger, len(src))
+	mergers := make([]db.Entity, len(src))
 	for i, dispatch := range src {
 		merger, err := c.ConvertDispatch(dispatch)
 		if err != nil {
@@ -32,7 +32,7 @@ func TestDispatch(t *testing.T) {
 	tests := []struct {
 		name     string
 		modifier modifier
-		want     []db.EntityMerger
+		want     []db.Entity
 		wantErr  bool
 	}{
 		{
@@ -40,7 +40,7 @@ func TestDispatch(t *testing.T) {
 			modifier: func(d *api.Dispatch) {
 				// keep valid
 			},
-			want: []db.EntityMerger{
+			want: []db.Entity{
 				&db.Dispatch{
 					ID:         678,
 					Message:    "A dispatch message",
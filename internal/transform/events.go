@@ -7,18 +7,22 @@ import (
 )
 
 // Events converts API data into mergable DB entities.
-func Events(c Converter, data APIData) ([]db.EntityMerger, error) {
+func Events(c Converter, data APIData) ([]db.Entity, error) {
 	if data.Planets == nil {
 		return nil, errors.New(("got nil planets slice (required for events)"))
 	}
 	if data.Campaigns == nil {
 		return nil, errors.New("got nil campaigns slice (required for events)")
 	}
+	warID, err := MustWarID(data.WarID)
+	if err != nil {
+		return nil, err
+	}
 
 	src := *data.Planets
 	// we could also use the planet-events endpoint directly which returns only planets with active events.
 	// this would introduce an additional API query though which causes more overhead than a simple client-side check in this function.
-	events := []db.EntityMerger{}
+	events := []db.Entity{}
 	for _, planet := range src {
 		if planet.Event == nil {
 			// event is optional
@@ -32,6 +36,7 @@ func Events(c Converter, data APIData) ([]db.EntityMerger, error) {
 		if err != nil {
 			return nil, err
 		}
+		merger.WarID = warID
 		events = append(events, merger)
 	}
 	return events, nil
@@ -58,7 +58,7 @@ func TestEvent(t *testing.T) {
 	tests := []struct {
 		name     string
 		modifier modifier
-		want     []db.EntityMerger
+		want     []db.Entity
 		wantErr  bool
 	}{
 		{
@@ -66,7 +66,7 @@ func TestEvent(t *testing.T) {
 			modifier: func(p *api.Planet) {
 				// keep valid
 			},
-			want: []db.EntityMerger{
+			want: []db.Entity{
 				&db.Event{
 					ID:         997,
 					StartTime:  db.PGTimestamp(time.Date(2024, 4, 5, 6, 7, 8, 9, time.UTC)),
@@ -75,6 +75,7 @@ func TestEvent(t *testing.T) {
 					Faction:    "Terminids",
 					MaxHealth:  4455667788,
 					CampaignID: 123,
+					WarID:      801,
 				},
 			},
 			wantErr: false,
@@ -84,7 +85,7 @@ func TestEvent(t *testing.T) {
 			modifier: func(p *api.Planet) {
 				p.Event = nil
 			},
-			want:    []db.EntityMerger{},
+			want:    []db.Entity{},
 			wantErr: false,
 		},
 		{
@@ -111,6 +112,7 @@ func TestEvent(t *testing.T) {
 			tt.modifier(&planets[0])
 
 			data := APIData{
+				WarID:   &testWarID,
 				Planets: &planets,
 				Campaigns: &[]api.Campaign2{
 					validEventCampaign, // campaign remains static
@@ -94,6 +94,11 @@ func (c *ConverterImpl) ConvertCampaign(source api.Campaign2) (*db.Campaign, err
 		return nil, fmt.Errorf("error setting field Count: %w", err)
 	}
 	dbCampaign.Count = pgtypeNumeric
+	pInt32, err := CampaignPlanetID(source.Planet)
+	if err != nil {
+		return nil, fmt.Errorf("error setting field PlanetID: %w", err)
+	}
+	dbCampaign.PlanetID = pInt32
 	return &dbCampaign, nil
 }
 func (c *ConverterImpl) ConvertDispatch(source api.Dispatch) (*db.Dispatch, error) {
@@ -391,6 +396,15 @@ func (c *ConverterImpl) ConvertSinglePlanet(source api.Planet) (gen.Planet, erro
 }
 func (c *ConverterImpl) ConvertSnapshot(source APIData) (gen.Snapshot, error) {
 	genSnapshot := DefaultSnapshot()
+	var pTimeTime *time.Time
+	if source.War != nil {
+		pTimeTime = source.War.Now
+	}
+	pgtypeTimestamp, err := MustTimestamp(pTimeTime)
+	if err != nil {
+		return genSnapshot, fmt.Errorf("error setting field CreateTime: %w", err)
+	}
+	genSnapshot.CreateTime = pgtypeTimestamp
 	int32List, err := MustSnapshotCampaignIDs(source.Campaigns)
 	if err != nil {
 		return genSnapshot, fmt.Errorf("error setting field CampaignIds: %w", err)
@@ -401,6 +415,7 @@ func (c *ConverterImpl) ConvertSnapshot(source APIData) (gen.Snapshot, error) {
 		return genSnapshot, fmt.Errorf("error setting field DispatchIds: %w", err)
 	}
 	genSnapshot.DispatchIds = int32List2
+	genSnapshot.Upstream = OptionalString(source.Upstream)
 	return genSnapshot, nil
 }
 func (c *ConverterImpl) ConvertStatistics(source api.Statistics) (*gen.SnapshotStatistic, error) {
@@ -25,6 +25,7 @@ type Converter interface {
 	ConvertAssignment(source api.Assignment2) (*db.Assignment, error)
 	// goverter:map Id ID
 	// goverter:ignore TaskIds
+	// goverter:ignore WarID
 	// goverter:map Reward RewardType | parseAssignmentRewardType
 	// goverter:map Reward RewardAmount | parseAssignmentRewardAmount
 	ConvertSingleAssignment(source api.Assignment2) (*gen.Assignment, error)
@@ -34,6 +35,8 @@ type Converter interface {
 	ConvertAssignmentTasks(source []api.Task2) ([]gen.AssignmentTask, error)
 
 	// goverter:map Id ID
+	// goverter:map Planet PlanetID | CampaignPlanetID
+	// goverter:ignore WarID
 	ConvertCampaign(source api.Campaign2) (*db.Campaign, error)
 
 	// goverter:map Id ID
@@ -43,6 +46,7 @@ type Converter interface {
 	// goverter:map Id ID
 	// goverter:map CampaignId CampaignID
 	// goverter:map EventType Type
+	// goverter:ignore WarID
 	ConvertEvent(source api.Event) (*db.Event, error)
 
 	// goverter:map . Planet
@@ -53,6 +57,7 @@ type Converter interface {
 	// goverter:map Waypoints WaypointIds
 	// goverter:map Biome BiomeName
 	// goverter:map Hazards HazardNames
+	// goverter:ignore WarID
 	ConvertSinglePlanet(source api.Planet) (gen.Planet, error)
 
 	// goverter:map WarID ID
@@ -62,13 +67,14 @@ type Converter interface {
 	ConvertWar(source APIData) (*db.War, error)
 
 	// goverter:default DefaultSnapshot
-	// goverter:ignore CreateTime
+	// goverter:map War.Now CreateTime | MustTimestamp
 	// goverter:ignore WarSnapshotID
 	// goverter:ignore AssignmentSnapshotIds
 	// goverter:ignore PlanetSnapshotIds
 	// goverter:ignore StatisticsID
 	// goverter:map Campaigns CampaignIds
 	// goverter:map Dispatches DispatchIds
+	// goverter:map Upstream Upstream | OptionalString
 	ConvertSnapshot(source APIData) (gen.Snapshot, error)
 	// goverter:ignore ID
 	// goverter:autoMap War
@@ -76,6 +82,7 @@ type Converter interface {
 	// goverter:default DefaultAssignmentSnapshot
 	// goverter:ignore ID
 	// goverter:map Id AssignmentID
+	// goverter:ignore WarID
 	ConvertAssignmentSnapshot(source api.Assignment2) (gen.AssignmentSnapshot, error)
 	ConvertAssignmentSnapshots(source []api.Assignment2) ([]gen.AssignmentSnapshot, error)
 	// goverter:default DefaultPlanetSnapshot
@@ -84,6 +91,7 @@ type Converter interface {
 	// goverter:ignore EventSnapshotID
 	// goverter:ignore StatisticsID
 	// goverter:map Attacking AttackingPlanetIds
+	// goverter:ignore WarID
 	ConvertPlanetSnapshotOnly(source api.Planet) (gen.PlanetSnapshot, error)
 	ConvertPlanetSnapshotsOnly(source []api.Planet) ([]gen.PlanetSnapshot, error)
 	// goverter:map . PlanetSnapshot
@@ -91,6 +99,7 @@ type Converter interface {
 	ConvertPlanetSnapshots(source []api.Planet) ([]db.PlanetSnapshot, error)
 	// goverter:ignore ID
 	// goverter:map Id EventID
+	// goverter:ignore WarID
 	ConvertEventSnapshot(source api.Event) (*gen.EventSnapshot, error)
 	// goverter:ignore ID
 	ConvertStatistics(source api.Statistics) (*gen.SnapshotStatistic, error)
@@ -154,6 +163,14 @@ func MustNumericSlice(ptr *[]uint64) ([]pgtype.Numeric, error) {
 	return out, nil
 }
 
+// OptionalString converts an empty string to nil.
+func OptionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
 // MustTimestamp converts a timestamp into a pgx-compatible type or an error if nil.
 func MustTimestamp(ptr *time.Time) (pgtype.Timestamp, error) {
 	t, err := mustPtr(ptr)
@@ -10,18 +10,23 @@ import (
 )
 
 // Planets converts API data into mergable DB entities.
-func Planets(c Converter, data APIData) ([]db.EntityMerger, error) {
+func Planets(c Converter, data APIData) ([]db.Entity, error) {
 	if data.Planets == nil {
 		return nil, errors.New("got nil planets slice")
 	}
+	warID, err := MustWarID(data.WarID)
+	if err != nil {
+		return nil, err
+	}
 
 	src := *data.Planets
-	planets := make([]db.EntityMerger, len(src))
+	planets := make([]db.Entity, len(src))
 	for i, planet := range src {
 		converted, err := c.ConvertPlanet(planet)
 		if err != nil {
 			return nil, err
 		}
+		converted.WarID = warID
 		planets[i] = converted
 	}
 	return planets, nil
@@ -73,7 +73,7 @@ func TestPlanets(t *testing.T) {
 	tests := []struct {
 		name     string
 		modifier modifier
-		want     []db.EntityMerger
+		want     []db.Entity
 		wantErr  bool
 	}{
 		{
@@ -81,7 +81,7 @@ func TestPlanets(t *testing.T) {
 			modifier: func(a *api.Planet) {
 				// keep valid
 			},
-			want: []db.EntityMerger{
+			want: []db.Entity{
 				&db.Planet{
 					Planet: gen.Planet{
 						ID:           3,
@@ -94,6 +94,7 @@ func TestPlanets(t *testing.T) {
 						Position:     []float64{38, 6},
 						Sector:       "A sector",
 						WaypointIds:  []int32{3, 4, 5},
+						WarID:        801,
 					},
 					Biome: gen.Biome{
 						Name:        "Foobiome",
@@ -114,7 +115,7 @@ func TestPlanets(t *testing.T) {
 			modifier: func(p *api.Planet) {
 				p.Waypoints = &[]int32{}
 			},
-			want: []db.EntityMerger{
+			want: []db.Entity{
 				&db.Planet{
 					Planet: gen.Planet{
 						ID:           3,
@@ -127,6 +128,7 @@ func TestPlanets(t *testing.T) {
 						Position:     []float64{38, 6},
 						Sector:       "A sector",
 						WaypointIds:  []int32{},
+						WarID:        801,
 					},
 					Biome: gen.Biome{
 						Name:        "Foobiome",
@@ -187,6 +189,7 @@ func TestPlanets(t *testing.T) {
 			// call modifier on valid planet copy
 			tt.modifier(&planet)
 			data := APIData{
+				WarID: &testWarID,
 				Planets: &[]api.Planet{
 					planet,
 				},
@@ -11,7 +11,7 @@ import (
 )
 
 // Snapshot converts API data into a mergable DB entity.
-func Snapshot(c Converter, data APIData) (mergers []db.EntityMerger, err error) {
+func Snapshot(c Converter, data APIData) (mergers []db.Entity, err error) {
 	snapshot, err := c.ConvertSnapshot(data)
 	if err != nil {
 		return nil, err
@@ -47,14 +47,14 @@ func Snapshot(c Converter, data APIData) (mergers []db.EntityMerger, err error)
 		Statistics:          *warStats,
 	}
 
-	mergers = []db.EntityMerger{s}
+	mergers = []db.Entity{s}
 	return
 }
 
 // DefaultSnapshot generates a snapshot with default values for FK IDs and identity columns.
 func DefaultSnapshot() gen.Snapshot {
 	return gen.Snapshot{
-		CreateTime:            pgtype.Timestamp{Valid: false}, // identity column
+		CreateTime:            pgtype.Timestamp{Valid: false}, // will be filled from War.Now
 		WarSnapshotID:         -1,                             // will be filled during insert
 		AssignmentSnapshotIds: nil,                            // see above
 		PlanetSnapshotIds:     nil,                            // see above
@@ -25,6 +25,7 @@ var validWarIDSnapshot = api.WarId{
 }
 
 var validWarSnapshot = api.War{
+	Now:              ptr(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)),
 	ImpactMultiplier: ptr(float64(0.0004)),
 	Statistics: mustWarStatistics(api.Statistics{
 		MissionsWon:     ptr(uint64(564643344)),
@@ -96,7 +97,7 @@ func TestSnapshots(t *testing.T) {
 	tests := []struct {
 		name      string
 		modifiers modifiers
-		want      []db.EntityMerger
+		want      []db.Entity
 		wantErr   bool
 	}{
 		{
@@ -109,16 +110,17 @@ func TestSnapshots(t *testing.T) {
 				Dispatch:   func(d *api.Dispatch) {},
 				Planet:     func(p *api.Planet) {},
 			},
-			want: []db.EntityMerger{
+			want: []db.Entity{
 				&db.Snapshot{
 					Snapshot: gen.Snapshot{
-						CreateTime:            pgtype.Timestamp{Valid: false},
+						CreateTime:            db.PGTimestamp(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)),
 						AssignmentSnapshotIds: nil,
 						CampaignIds:           []int32{987},
 						DispatchIds:           []int32{678},
 						StatisticsID:          -1,
 						WarSnapshotID:         -1,
 						PlanetSnapshotIds:     nil,
+						Upstream:              ptr("http://localhost:4000"),
 					},
 					WarSnapshot: gen.WarSnapshot{
 						WarID:            999,
@@ -197,6 +199,21 @@ func TestSnapshots(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "empty war now",
+			modifiers: modifiers{
+				WarID: func(wi *api.WarId) {},
+				War: func(w *api.War) {
+					w.Now = nil
+				},
+				Assignment: func(a *api.Assignment2) {},
+				Campaign:   func(c *api.Campaign2) {},
+				Dispatch:   func(d *api.Dispatch) {},
+				Planet:     func(p *api.Planet) {},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "empty war statistic",
 			modifiers: modifiers{
@@ -264,6 +281,7 @@ func TestSnapshots(t *testing.T) {
 				Assignments: &[]api.Assignment2{assignment},
 				Campaigns:   &[]api.Campaign2{campaign},
 				Dispatches:  &[]api.Dispatch{dispatch},
+				Upstream:    "http://localhost:4000",
 			}
 			converter := &ConverterImpl{}
 			got, err := Snapshot(converter, data)
@@ -13,4 +13,6 @@ type APIData struct {
 	Campaigns   *[]api.Campaign2
 	Dispatches  *[]api.Dispatch
 	Assignments *[]api.Assignment2
+	// Upstream is the API instance which served the data
+	Upstream string
 }
@@ -1,5 +1,10 @@
 package transform
 
+import "github.com/stnokott/helldivers-client/internal/api"
+
 func ptr[T any](x T) *T {
 	return &x
 }
+
+// testWarID is the war all entities in the tests belong to
+var testWarID = api.WarId{Id: ptr(int32(801))}
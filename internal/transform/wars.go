@@ -8,12 +8,12 @@ import (
 )
 
 // Wars converts API data into mergable DB entities.
-func Wars(c Converter, data APIData) ([]db.EntityMerger, error) {
+func Wars(c Converter, data APIData) ([]db.Entity, error) {
 	war, err := c.ConvertWar(data)
 	if err != nil {
 		return nil, err
 	}
-	return []db.EntityMerger{war}, nil
+	return []db.Entity{war}, nil
 }
 
 // MustWarID implements a converter for a war ID.
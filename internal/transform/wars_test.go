@@ -33,7 +33,7 @@ func TestWar(t *testing.T) {
 		name          string
 		warIDModifier warIDModifier
 		warModifier   warModifier
-		want          []db.EntityMerger
+		want          []db.Entity
 		wantErr       bool
 	}{
 		{
@@ -44,7 +44,7 @@ func TestWar(t *testing.T) {
 			warModifier: func(a *api.War) {
 				// keep valid
 			},
-			want: []db.EntityMerger{
+			want: []db.Entity{
 				&db.War{
 					ID:        999,
 					StartTime: db.PGTimestamp(time.Date(2024, 1, 1, 1, 1, 1, 1, time.UTC)),
@@ -62,7 +62,7 @@ func TestWar(t *testing.T) {
 			warModifier: func(w *api.War) {
 				w.Factions = &[]string{}
 			},
-			want: []db.EntityMerger{
+			want: []db.Entity{
 				&db.War{
 					ID:        999,
 					StartTime: db.PGTimestamp(time.Date(2024, 1, 1, 1, 1, 1, 1, time.UTC)),
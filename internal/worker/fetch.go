@@ -0,0 +1,300 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/stnokott/helldivers-client/internal/api"
+	"github.com/stnokott/helldivers-client/internal/client"
+	"github.com/stnokott/helldivers-client/internal/logging"
+	"github.com/stnokott/helldivers-client/internal/transform"
+)
+
+// names of the queried API endpoints
+const (
+	endpointWarID       = "war ID"
+	endpointWar         = "war"
+	endpointCampaigns   = "campaigns"
+	endpointPlanets     = "planets"
+	endpointAssignments = "assignments"
+	endpointDispatches  = "dispatches"
+)
+
+// fetchResult records the outcome of querying a single API endpoint.
+type fetchResult struct {
+	Endpoint string
+	Duration time.Duration
+	Err      error
+	// Unchanged is true if the API returned the same data as in the previous fetch
+	Unchanged bool
+	// Upstream is the API instance which served the data
+	Upstream string
+}
+
+// status summarizes the result as "ok", "unchanged" or "failed".
+func (r fetchResult) status() string {
+	if r.Err != nil {
+		return "failed"
+	}
+	if r.Unchanged {
+		return "unchanged"
+	}
+	return "ok"
+}
+
+// endpointFetch describes how to query a single API endpoint.
+type endpointFetch struct {
+	endpoint string
+	fetch    func(context.Context) error
+}
+
+// fetchSet defines which endpoints are queried during a sync.
+type fetchSet struct {
+	// endpoints are queried concurrently
+	endpoints []string
+	// campaignPlanets queries the planets of active campaigns individually once all other endpoints are finished, if not nil.
+	// It requires endpointCampaigns to be part of `endpoints`.
+	campaignPlanets *campaignPlanets
+}
+
+// campaignPlanets tracks the planets of active campaigns between the runs of a job.
+type campaignPlanets struct {
+	// previous are the planets queried in the previous run
+	previous []int32
+}
+
+// indices returns the planets of all active campaigns and the planets queried in the previous run.
+//
+// The planets of campaigns which ended since the previous run are queried once more,
+// so that their owner after the campaign is captured and its outcome can be resolved.
+func (p *campaignPlanets) indices(active []int32) []int32 {
+	indices := slices.Clone(active)
+	for _, index := range p.previous {
+		if !slices.Contains(indices, index) {
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
+
+// update remembers the planets to query again in the next run.
+// If querying failed, all planets are kept, so that the planets of ended campaigns are retried.
+func (p *campaignPlanets) update(active []int32, queried []int32, err error) {
+	if err != nil {
+		p.previous = queried
+		return
+	}
+	p.previous = active
+}
+
+// queryData queries all endpoints in `set`.
+//
+// The war ID is queried first, since all war-scoped endpoints are queried for that war.
+// If the war is queried, all other endpoints are pinned to the upstream which served it.
+// Each endpoint gets its own timeout budget, which spans all retries performed by the API client.
+// A failing endpoint does not cancel the others, its data will simply be nil.
+func (w *Worker) queryData(ctx context.Context, set fetchSet) (data transform.APIData, results []fetchResult) {
+	// warScoped queries an endpoint for the war whose ID has been queried before
+	warScoped := func(fetch func(ctx context.Context, warID int32) error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			warID, err := transform.MustWarID(data.WarID)
+			if err != nil {
+				return fmt.Errorf("war ID unavailable, cannot query war-scoped data: %w", err)
+			}
+			return fetch(ctx, warID)
+		}
+	}
+
+	// each fetch only writes to its own field in data, so no synchronization is needed
+	fetches := make([]endpointFetch, len(set.endpoints))
+	for i, endpoint := range set.endpoints {
+		fetches[i] = endpointFetch{endpoint: endpoint}
+		switch endpoint {
+		case endpointWarID:
+			fetches[i].fetch = func(ctx context.Context) (err error) {
+				data.WarID, err = w.api.WarID(ctx)
+				return
+			}
+		case endpointWar:
+			fetches[i].fetch = warScoped(func(ctx context.Context, warID int32) (err error) {
+				data.War, err = w.api.War(ctx, warID)
+				return
+			})
+		case endpointCampaigns:
+			fetches[i].fetch = warScoped(func(ctx context.Context, warID int32) (err error) {
+				data.Campaigns, err = w.api.Campaigns(ctx, warID)
+				return
+			})
+		case endpointPlanets:
+			fetches[i].fetch = warScoped(func(ctx context.Context, warID int32) (err error) {
+				data.Planets, err = w.api.Planets(ctx, warID)
+				return
+			})
+		case endpointAssignments:
+			fetches[i].fetch = warScoped(func(ctx context.Context, warID int32) (err error) {
+				data.Assignments, err = w.api.Assignments(ctx, warID)
+				return
+			})
+		case endpointDispatches:
+			fetches[i].fetch = func(ctx context.Context) (err error) {
+				data.Dispatches, err = w.api.Dispatches(ctx)
+				return
+			}
+		default:
+			panic("unknown endpoint " + endpoint)
+		}
+	}
+	if i := slices.IndexFunc(fetches, func(f endpointFetch) bool { return f.endpoint == endpointWarID }); i >= 0 {
+		results = append(results, w.fetch(ctx, fetches[i]))
+		fetches = slices.Delete(fetches, i, i+1)
+	}
+	// all other data dates from the war's timestamp, so its source identifies the snapshot.
+	// The war is queried next, so that all other endpoints can be queried from the same upstream.
+	if i := slices.IndexFunc(fetches, func(f endpointFetch) bool { return f.endpoint == endpointWar }); i >= 0 {
+		war := w.fetch(ctx, fetches[i])
+		results = append(results, war)
+		fetches = slices.Delete(fetches, i, i+1)
+		if war.Err == nil {
+			data.Upstream = war.Upstream
+			ctx = client.WithUpstream(ctx, war.Upstream)
+		}
+	}
+	results = append(results, w.fetchAll(ctx, fetches)...)
+
+	if set.campaignPlanets != nil {
+		results = append(results, w.fetchCampaignPlanets(ctx, set.campaignPlanets, &data))
+	}
+
+	logFetchResults(ctx, results, w.log)
+	return
+}
+
+// fetchAll runs all fetches with bounded concurrency and waits until all of them are finished.
+func (w *Worker) fetchAll(ctx context.Context, fetches []endpointFetch) []fetchResult {
+	results := make([]fetchResult, len(fetches))
+
+	// we don't use errgroup.WithContext since one failing endpoint should not cancel the others
+	g := new(errgroup.Group)
+	g.SetLimit(w.fetchConcurrency)
+	for i, f := range fetches {
+		g.Go(func() error {
+			results[i] = w.fetch(ctx, f)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return results
+}
+
+// fetchCampaignPlanets queries the planets of all campaigns in `data` individually,
+// along with the planets of campaigns which ended since the previous run.
+//
+// Since every planet is a separate request, the timeout is extended by the time the rate limit needs to let them through.
+// Planets which could be queried are kept even if others failed.
+func (w *Worker) fetchCampaignPlanets(ctx context.Context, tracked *campaignPlanets, data *transform.APIData) fetchResult {
+	if data.Campaigns == nil {
+		return w.fetch(ctx, endpointFetch{endpointPlanets, func(context.Context) error {
+			return errors.New("campaigns unavailable, cannot determine planets to query")
+		}})
+	}
+	active, err := campaignPlanetIndices(*data.Campaigns)
+	if err != nil {
+		return w.fetch(ctx, endpointFetch{endpointPlanets, func(context.Context) error { return err }})
+	}
+	// campaigns are only available if the war ID is
+	warID, _ := transform.MustWarID(data.WarID)
+	indices := tracked.indices(active)
+	timeout := w.fetchTimeout + w.api.RateLimitBudget(len(indices))
+	result := w.fetchWithin(ctx, endpointFetch{endpointPlanets, func(ctx context.Context) error {
+		planets, err := w.api.PlanetsByIndex(ctx, warID, indices)
+		if err == nil || len(*planets) > 0 {
+			data.Planets = planets
+		}
+		return err
+	}}, timeout)
+	tracked.update(active, indices, result.Err)
+	return result
+}
+
+func (w *Worker) fetch(ctx context.Context, f endpointFetch) fetchResult {
+	return w.fetchWithin(ctx, f, w.fetchTimeout)
+}
+
+// fetchWithin runs `f` with a timeout of `timeout`.
+func (w *Worker) fetchWithin(ctx context.Context, f endpointFetch, timeout time.Duration) fetchResult {
+	ctx, cancel := context.WithTimeoutCause(
+		ctx,
+		timeout,
+		fmt.Errorf("timeout of %s for %s expired", timeout, f.endpoint),
+	)
+	defer cancel()
+
+	info := new(client.ResponseInfo)
+	ctx = client.WithResponseInfo(ctx, info)
+
+	start := time.Now()
+	err := f.fetch(ctx)
+	result := fetchResult{
+		Endpoint:  f.endpoint,
+		Duration:  time.Since(start),
+		Err:       err,
+		Unchanged: err == nil && info.Unchanged,
+		Upstream:  info.Upstream,
+	}
+	if err != nil {
+		w.log.WarnContext(ctx, "query failed", logging.KeyEndpoint, f.endpoint, logging.Err(err))
+	}
+	return result
+}
+
+// logFetchResults logs one record per queried endpoint.
+func logFetchResults(ctx context.Context, results []fetchResult, logger *slog.Logger) {
+	for _, result := range results {
+		logger.InfoContext(ctx, "fetched endpoint",
+			logging.KeyEndpoint, result.Endpoint,
+			"duration", result.Duration.Round(time.Millisecond),
+			"result", result.status(),
+			"upstream", result.Upstream,
+		)
+	}
+}
+
+// campaignPlanetIndices returns the distinct indices of all planets with an active campaign.
+func campaignPlanetIndices(campaigns []api.Campaign2) ([]int32, error) {
+	seen := map[int32]bool{}
+	indices := make([]int32, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		if campaign.Planet == nil {
+			return nil, errors.New("campaign planet is nil")
+		}
+		planet, err := campaign.Planet.AsPlanet()
+		if err != nil {
+			return nil, fmt.Errorf("parsing campaign planet: %w", err)
+		}
+		if planet.Index == nil {
+			return nil, errors.New("campaign planet index is nil")
+		}
+		if !seen[*planet.Index] {
+			seen[*planet.Index] = true
+			indices = append(indices, *planet.Index)
+		}
+	}
+	return indices, nil
+}
+
+// unchangedEndpoints returns the set of endpoints which returned the same data as in the previous fetch.
+func unchangedEndpoints(results []fetchResult) map[string]bool {
+	unchanged := map[string]bool{}
+	for _, result := range results {
+		if result.Unchanged {
+			unchanged[result.Endpoint] = true
+		}
+	}
+	return unchanged
+}
@@ -0,0 +1,149 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/api"
+	"github.com/stnokott/helldivers-client/internal/logging"
+)
+
+func TestWorkerFetchAll(t *testing.T) {
+	w := &Worker{
+		fetchConcurrency: 2,
+		fetchTimeout:     200 * time.Millisecond,
+		log:              logging.Discard(),
+	}
+
+	var running, maxRunning atomic.Int32
+	track := func(d time.Duration) func(context.Context) error {
+		return func(ctx context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			select {
+			case <-time.After(d):
+				return nil
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+		}
+	}
+	errFailed := errors.New("failed")
+
+	results := w.fetchAll(context.Background(), []endpointFetch{
+		{"fast", track(10 * time.Millisecond)},
+		{"slow", track(time.Second)},
+		{"failing", func(context.Context) error { return errFailed }},
+		{"other", track(10 * time.Millisecond)},
+	})
+
+	if len(results) != 4 {
+		t.Fatalf("Worker.fetchAll() returned %d results, want 4", len(results))
+	}
+	for i, endpoint := range []string{"fast", "slow", "failing", "other"} {
+		if results[i].Endpoint != endpoint {
+			t.Errorf("results[%d].Endpoint = %s, want %s", i, results[i].Endpoint, endpoint)
+		}
+	}
+	if results[0].Err != nil || results[3].Err != nil {
+		t.Errorf("fast endpoints returned errors: %v, %v", results[0].Err, results[3].Err)
+	}
+	if results[1].Err == nil {
+		t.Error("slow endpoint error = nil, want timeout")
+	}
+	if !errors.Is(results[2].Err, errFailed) {
+		t.Errorf("failing endpoint error = %v, want %v", results[2].Err, errFailed)
+	}
+	if results[1].Duration >= time.Second {
+		t.Errorf("slow endpoint took %s, want less than its duration due to timeout", results[1].Duration)
+	}
+	if got := maxRunning.Load(); got > 2 {
+		t.Errorf("max. concurrent fetches = %d, want <= 2", got)
+	}
+}
+
+func TestCampaignPlanetIndices(t *testing.T) {
+	campaign := func(planetIndex *int32) api.Campaign2 {
+		planet := new(api.Campaign2_Planet)
+		if err := planet.FromPlanet(api.Planet{Index: planetIndex}); err != nil {
+			t.Fatal(err)
+		}
+		return api.Campaign2{Planet: planet}
+	}
+	index := func(i int32) *int32 { return &i }
+
+	tests := []struct {
+		name      string
+		campaigns []api.Campaign2
+		want      []int32
+		wantErr   bool
+	}{
+		{
+			name:      "empty",
+			campaigns: []api.Campaign2{},
+			want:      []int32{},
+		},
+		{
+			name:      "distinct",
+			campaigns: []api.Campaign2{campaign(index(3)), campaign(index(1)), campaign(index(3))},
+			want:      []int32{3, 1},
+		},
+		{
+			name:      "nil planet",
+			campaigns: []api.Campaign2{{}},
+			wantErr:   true,
+		},
+		{
+			name:      "nil index",
+			campaigns: []api.Campaign2{campaign(nil)},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := campaignPlanetIndices(tt.campaigns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("campaignPlanetIndices() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("campaignPlanetIndices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCampaignPlanetsIndices(t *testing.T) {
+	tracked := new(campaignPlanets)
+
+	steps := []struct {
+		name   string
+		active []int32
+		err    error
+		want   []int32
+	}{
+		{name: "first run", active: []int32{1, 2}, want: []int32{1, 2}},
+		// campaign on planet 2 ended, its planet is queried once more
+		{name: "campaign ended", active: []int32{1, 3}, want: []int32{1, 3, 2}},
+		{name: "query failed", active: []int32{1}, err: errors.New("query failed"), want: []int32{1, 3}},
+		// querying failed, so the planet of the campaign which ended before is retried
+		{name: "ended planet retried", active: []int32{1}, want: []int32{1, 3}},
+		{name: "no campaigns", active: []int32{}, want: []int32{1}},
+	}
+	for _, step := range steps {
+		got := tracked.indices(step.active)
+		if !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: indices() = %v, want %v", step.name, got, step.want)
+		}
+		tracked.update(step.active, got, step.err)
+	}
+}
@@ -1,6 +1,13 @@
 package worker
 
-import "context"
+import (
+	"context"
+	"time"
+
+	health "github.com/stnokott/healthchecks"
+
+	"github.com/stnokott/helldivers-client/internal/logging"
+)
 
 type healthcheckType int
 
@@ -10,8 +17,10 @@ const (
 	healthcheckFail
 )
 
-func (w *Worker) healthNotify(ctx context.Context, t healthcheckType) {
-	if w.healthcheck == nil {
+const healthcheckTimeout = 10 * time.Second
+
+func (w *Worker) healthNotify(ctx context.Context, healthcheck health.Notifier, t healthcheckType) {
+	if healthcheck == nil {
 		return
 	}
 	var (
@@ -20,18 +29,35 @@ func (w *Worker) healthNotify(ctx context.Context, t healthcheckType) {
 	)
 	switch t {
 	case healthcheckStart:
-		healthFunc = w.healthcheck.Start
+		healthFunc = healthcheck.Start
 		healthName = "start"
 	case healthcheckSuccess:
-		healthFunc = w.healthcheck.Success
+		healthFunc = healthcheck.Success
 		healthName = "success"
 	case healthcheckFail:
-		healthFunc = w.healthcheck.Fail
+		healthFunc = healthcheck.Fail
 		healthName = "fail"
 	}
 
-	w.log.Println("signalling healthcheck", healthName)
+	ctx, cancel := context.WithTimeout(ctx, healthcheckTimeout)
+	defer cancel()
+
+	w.log.DebugContext(ctx, "signalling healthcheck", "signal", healthName)
 	if err := healthFunc(ctx); err != nil {
-		w.log.Printf("WARN: failed to signal %s: %v", healthName, err)
+		w.log.WarnContext(ctx, "failed to signal healthcheck", "signal", healthName, logging.Err(err))
+	}
+}
+
+// healthLog attaches `msg` to the healthcheck as an event without changing its status.
+func (w *Worker) healthLog(ctx context.Context, healthcheck health.Notifier, msg string) {
+	if healthcheck == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthcheckTimeout)
+	defer cancel()
+
+	w.log.DebugContext(ctx, "logging healthcheck event")
+	if err := healthcheck.Log(ctx, msg); err != nil {
+		w.log.WarnContext(ctx, "failed to log healthcheck event", logging.Err(err))
 	}
 }
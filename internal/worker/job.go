@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+
+	health "github.com/stnokott/healthchecks"
+
+	"github.com/stnokott/helldivers-client/internal/config"
+)
+
+// job is a scheduled sync of a subset of the entities.
+type job struct {
+	name  string
+	cron  string
+	fetch fetchSet
+	// groups are the names of the entity groups merged by this job
+	groups []string
+	// checkStale enables warnings if the API data did not advance for too long
+	checkStale  bool
+	healthcheck health.Notifier
+	// dependsOn needs to have succeeded at least once before this job can run
+	dependsOn *job
+
+	mu        sync.Mutex
+	succeeded bool
+}
+
+// hasSucceeded reports whether the job finished successfully at least once.
+// It blocks while the job is running.
+func (j *job) hasSucceeded() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.succeeded
+}
+
+// reset marks the job as not having succeeded, e.g. because its data is outdated.
+func (j *job) reset() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.succeeded = false
+}
+
+// merges reports whether the job merges the entity group with `name`.
+func (j *job) merges(name string) bool {
+	for _, group := range j.groups {
+		if group == name {
+			return true
+		}
+	}
+	return false
+}
+
+// newJobs creates the static and snapshot jobs.
+//
+// Wars and planets rarely change, so they are synced by the static job on a slow schedule.
+// The snapshot job syncs everything else and depends on the static job, since its entities reference planets and wars.
+func newJobs(cfg *config.Config) ([]*job, error) {
+	staticHealthcheck, err := newHealthcheck(cfg.StaticHealthchecksURL)
+	if err != nil {
+		return nil, fmt.Errorf("preparing static healthcheck: %w", err)
+	}
+	snapshotHealthcheck, err := newHealthcheck(cfg.SnapshotHealthchecksURL)
+	if err != nil {
+		return nil, fmt.Errorf("preparing snapshot healthcheck: %w", err)
+	}
+
+	static := &job{
+		name: "static",
+		cron: cfg.StaticCron,
+		fetch: fetchSet{
+			endpoints: []string{endpointWarID, endpointWar, endpointPlanets},
+		},
+		groups:      []string{groupWars, groupPlanets},
+		healthcheck: staticHealthcheck,
+	}
+	snapshot := &job{
+		name:        "snapshot",
+		cron:        cfg.SnapshotCron,
+		fetch:       snapshotFetchSet(cfg.SnapshotCampaignPlanetsOnly),
+		groups:      []string{groupCampaigns, groupEvents, groupPlanets, groupAssignments, groupDispatches, groupSnapshots, groupCampaignLifecycles},
+		checkStale:  true,
+		healthcheck: snapshotHealthcheck,
+		dependsOn:   static,
+	}
+	return []*job{static, snapshot}, nil
+}
+
+// snapshotFetchSet returns the endpoints queried by the snapshot job.
+//
+// By default, all planets are queried, so that every planet is part of each snapshot.
+// With `campaignPlanetsOnly`, only the planets of active campaigns are queried individually,
+// plus the planets of campaigns which ended since the previous run to resolve their outcome.
+func snapshotFetchSet(campaignPlanetsOnly bool) fetchSet {
+	endpoints := []string{endpointWarID, endpointWar, endpointCampaigns, endpointAssignments, endpointDispatches}
+	if campaignPlanetsOnly {
+		return fetchSet{endpoints: endpoints, campaignPlanets: new(campaignPlanets)}
+	}
+	return fetchSet{endpoints: append(endpoints, endpointPlanets)}
+}
+
+func newHealthcheck(url string) (health.Notifier, error) {
+	if url == "" {
+		return nil, nil
+	}
+	return health.FromURL(url)
+}
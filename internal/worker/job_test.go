@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stnokott/helldivers-client/internal/config"
+)
+
+func TestNewJobs(t *testing.T) {
+	for _, campaignPlanetsOnly := range []bool{false, true} {
+		jobs, err := newJobs(&config.Config{
+			StaticCron:                  "0 * * * *",
+			SnapshotCron:                "*/5 * * * *",
+			SnapshotCampaignPlanetsOnly: campaignPlanetsOnly,
+		})
+		if err != nil {
+			t.Fatalf("newJobs() error = %v", err)
+		}
+		testJobs(t, jobs)
+	}
+}
+
+func TestSnapshotFetchSet(t *testing.T) {
+	if set := snapshotFetchSet(false); set.campaignPlanets != nil || !slices.Contains(set.endpoints, endpointPlanets) {
+		t.Errorf("snapshotFetchSet(false) = %+v, want all planets", set)
+	}
+	if set := snapshotFetchSet(true); set.campaignPlanets == nil || slices.Contains(set.endpoints, endpointPlanets) {
+		t.Errorf("snapshotFetchSet(true) = %+v, want campaign planets only", set)
+	}
+}
+
+// testJobs checks that every job queries the endpoints of the entities it merges and that every entity group is merged.
+func testJobs(t *testing.T, jobs []*job) {
+	t.Helper()
+
+	merged := map[string]bool{}
+	for _, j := range jobs {
+		fetched := map[string]bool{}
+		for _, endpoint := range j.fetch.endpoints {
+			fetched[endpoint] = true
+		}
+		if j.fetch.campaignPlanets != nil {
+			if !fetched[endpointCampaigns] {
+				t.Errorf("job %s queries campaign planets without querying campaigns", j.name)
+			}
+			fetched[endpointPlanets] = true
+		}
+
+		for _, name := range j.groups {
+			found := false
+			for _, group := range entityGroups {
+				if group.name != name {
+					continue
+				}
+				found = true
+				for _, endpoint := range group.endpoints {
+					if !fetched[endpoint] {
+						t.Errorf("job %s merges %s without querying %s", j.name, name, endpoint)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("job %s merges unknown entity group %s", j.name, name)
+			}
+			merged[name] = true
+		}
+	}
+
+	for _, group := range entityGroups {
+		if !merged[group.name] {
+			t.Errorf("entity group %s is not merged by any job", group.name)
+		}
+	}
+}
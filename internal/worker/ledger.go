@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+	"github.com/stnokott/helldivers-client/internal/logging"
+)
+
+// runRecord collects everything about a single sync run which ends up in the sync_runs ledger.
+type runRecord struct {
+	id    uuid.UUID
+	job   string
+	start time.Time
+	// results are nil if no endpoints have been queried
+	results []fetchResult
+	// collector holds the statistics of the merge, it stays empty unless the merge succeeded
+	collector stats.Collector
+	// snapshotTime is invalid if the run did not create a snapshot
+	snapshotTime pgtype.Timestamp
+}
+
+func newRunRecord(job string) *runRecord {
+	return &runRecord{
+		id:        uuid.New(),
+		job:       job,
+		start:     time.Now(),
+		collector: stats.NewCollector(),
+	}
+}
+
+// merged records a successful merge with its statistics in `collector`.
+//
+// A failed merge has been rolled back, so its statistics must not end up in the ledger.
+func (r *runRecord) merged(collector stats.Collector, snapshotTime pgtype.Timestamp) {
+	r.collector = collector
+	r.snapshotTime = snapshotTime
+}
+
+// syncRun converts the record into a ledger entry for a run which finished at `end` with `err`.
+func (r *runRecord) syncRun(end time.Time, err error) *db.SyncRun {
+	run := &db.SyncRun{
+		ID:                 pgtype.UUID{Bytes: r.id, Valid: true},
+		Job:                r.job,
+		StartTime:          db.PGTimestamp(r.start.UTC()),
+		EndTime:            db.PGTimestamp(end.UTC()),
+		Outcome:            db.SyncOutcomeSuccess,
+		Endpoints:          make([]db.SyncRunEndpoint, len(r.results)),
+		MergeCounts:        r.collector.Counts(),
+		SnapshotCreateTime: r.snapshotTime,
+	}
+	for i, result := range r.results {
+		run.Endpoints[i] = db.SyncRunEndpoint{
+			Endpoint:  result.Endpoint,
+			Status:    result.status(),
+			LatencyMS: result.Duration.Milliseconds(),
+			Upstream:  result.Upstream,
+		}
+		if result.Err != nil {
+			run.Endpoints[i].Error = result.Err.Error()
+		}
+	}
+	if err != nil {
+		msg := err.Error()
+		run.Outcome = db.SyncOutcomeFailure
+		run.Error = &msg
+	}
+	return run
+}
+
+// recordRun merges the ledger entry of a finished run into the sink.
+//
+// A failure is only logged since the run itself is already over.
+func (w *Worker) recordRun(ctx context.Context, run *db.SyncRun) {
+	ctx, cancel := context.WithTimeout(ctx, mergeTimeout)
+	defer cancel()
+
+	// the ledger is not part of the merge statistics of the run
+	if err := w.sink.Merge(ctx, stats.NewCollector(), []db.Entity{run}); err != nil {
+		w.log.WarnContext(ctx, "failed to record sync run", logging.Err(err))
+	}
+}
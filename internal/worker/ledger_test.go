@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+)
+
+func TestRunRecordSyncRun(t *testing.T) {
+	run := newRunRecord("snapshot")
+	run.results = []fetchResult{
+		{Endpoint: endpointWar, Duration: 1500 * time.Millisecond, Upstream: "https://api.example.com"},
+		{Endpoint: endpointDispatches, Duration: 20 * time.Millisecond, Unchanged: true},
+		{Endpoint: endpointPlanets, Duration: 10 * time.Second, Err: errors.New("timeout")},
+	}
+	end := run.start.Add(11 * time.Second)
+
+	// statistics of a failed merge are not recorded
+	if got := run.syncRun(end, errors.New("merging failed")); len(got.MergeCounts) != 0 || got.SnapshotCreateTime.Valid {
+		t.Errorf("syncRun() before merge = counts %+v, snapshot %v, want none", got.MergeCounts, got.SnapshotCreateTime)
+	}
+
+	collector := stats.NewCollector()
+	collector.Inserted(gen.TableSnapshots, 1)
+	collector.SourceUnchanged(gen.TableDispatches)
+	run.merged(collector, db.PGTimestamp(run.start))
+
+	got := run.syncRun(end, nil)
+	if got.ID.Bytes != run.id || got.Job != "snapshot" || !got.EndTime.Time.Equal(end) {
+		t.Errorf("syncRun() = %+v, want run %s of job snapshot ending at %v", got, run.id, end)
+	}
+	if !got.SnapshotCreateTime.Time.Equal(run.start) {
+		t.Errorf("syncRun().SnapshotCreateTime = %v, want %v", got.SnapshotCreateTime, run.start)
+	}
+	if got.Outcome != db.SyncOutcomeSuccess || got.Error != nil {
+		t.Errorf("syncRun() outcome = %s (%v), want success", got.Outcome, got.Error)
+	}
+	wantEndpoints := []db.SyncRunEndpoint{
+		{Endpoint: endpointWar, Status: "ok", LatencyMS: 1500, Upstream: "https://api.example.com"},
+		{Endpoint: endpointDispatches, Status: "unchanged", LatencyMS: 20},
+		{Endpoint: endpointPlanets, Status: "failed", LatencyMS: 10000, Error: "timeout"},
+	}
+	if !reflect.DeepEqual(got.Endpoints, wantEndpoints) {
+		t.Errorf("syncRun().Endpoints = %+v, want %+v", got.Endpoints, wantEndpoints)
+	}
+	wantCounts := map[string]stats.TableCounts{
+		"Snapshots":  {Inserted: 1},
+		"Dispatches": {SourceUnchanged: true},
+	}
+	if !reflect.DeepEqual(got.MergeCounts, wantCounts) {
+		t.Errorf("syncRun().MergeCounts = %+v, want %+v", got.MergeCounts, wantCounts)
+	}
+
+	failed := run.syncRun(end, errors.New("merging failed"))
+	if failed.Outcome != db.SyncOutcomeFailure || failed.Error == nil || *failed.Error != "merging failed" {
+		t.Errorf("syncRun() with error = %s (%v), want failure", failed.Outcome, failed.Error)
+	}
+}
+
+func TestMergedSnapshotTime(t *testing.T) {
+	createTime := db.PGTimestamp(time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC))
+	mergers := [][]db.Entity{
+		{&db.Dispatch{ID: 1}},
+		{&db.Snapshot{Snapshot: gen.Snapshot{CreateTime: createTime}}},
+	}
+	if got := mergedSnapshotTime(mergers); got != createTime {
+		t.Errorf("mergedSnapshotTime() = %v, want %v", got, createTime)
+	}
+	if got := mergedSnapshotTime(mergers[:1]); got.Valid {
+		t.Errorf("mergedSnapshotTime() without snapshot = %v, want invalid", got)
+	}
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"github.com/stnokott/helldivers-client/internal/metrics"
+	"github.com/stnokott/helldivers-client/internal/transform"
+)
+
+// recordDomainMetrics updates the player count and planet health gauges from the latest API data.
+//
+// Planet health is only reported for planets with active campaigns.
+func (w *Worker) recordDomainMetrics(data transform.APIData) {
+	if data.War != nil && data.War.Statistics != nil {
+		if statistics, err := data.War.Statistics.AsStatistics(); err == nil && statistics.PlayerCount != nil {
+			w.metrics.SetPlayerCount(*statistics.PlayerCount)
+		}
+	}
+	if data.Planets == nil || data.Campaigns == nil {
+		return
+	}
+	indices, err := campaignPlanetIndices(*data.Campaigns)
+	if err != nil {
+		return
+	}
+	campaignPlanets := make(map[int32]bool, len(indices))
+	for _, index := range indices {
+		campaignPlanets[index] = true
+	}
+	planets := make([]metrics.PlanetHealth, 0, len(indices))
+	for _, planet := range *data.Planets {
+		if planet.Index == nil || planet.Health == nil || !campaignPlanets[*planet.Index] {
+			continue
+		}
+		name, err := transform.MustPlanetName(planet.Name)
+		if err != nil {
+			continue
+		}
+		planets = append(planets, metrics.PlanetHealth{ID: *planet.Index, Name: name, Health: *planet.Health})
+	}
+	w.metrics.SetPlanetHealth(planets)
+}
@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stnokott/helldivers-client/internal/api"
+	"github.com/stnokott/helldivers-client/internal/metrics"
+	"github.com/stnokott/helldivers-client/internal/transform"
+)
+
+func TestWorkerRecordDomainMetrics(t *testing.T) {
+	planet := func(index int32, name string, health int64) api.Planet {
+		planetName := new(api.Planet_Name)
+		if err := planetName.FromPlanetName0(name); err != nil {
+			t.Fatal(err)
+		}
+		return api.Planet{Index: &index, Name: planetName, Health: &health}
+	}
+	campaign := func(p api.Planet) api.Campaign2 {
+		campaignPlanet := new(api.Campaign2_Planet)
+		if err := campaignPlanet.FromPlanet(p); err != nil {
+			t.Fatal(err)
+		}
+		return api.Campaign2{Planet: campaignPlanet}
+	}
+
+	foo, bar := planet(1, "Foo", 100), planet(2, "Bar", 200)
+	w := &Worker{metrics: metrics.New(true)}
+	w.recordDomainMetrics(transform.APIData{
+		Planets:   &[]api.Planet{foo, bar},
+		Campaigns: &[]api.Campaign2{campaign(bar)},
+	})
+
+	rec := httptest.NewRecorder()
+	w.metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	if want := `helldivers_client_planet_health{planet="Bar",planet_id="2"} 200`; !strings.Contains(body, want) {
+		t.Errorf("metrics do not contain %q", want)
+	}
+	// Foo has no active campaign
+	if strings.Contains(body, `planet="Foo"`) {
+		t.Error("metrics contain planet health of planet without campaign")
+	}
+}
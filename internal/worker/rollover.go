@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+	"github.com/stnokott/helldivers-client/internal/logging"
+	"github.com/stnokott/helldivers-client/internal/transform"
+)
+
+// warTracker keeps track of the ongoing war to detect season rollovers.
+type warTracker struct {
+	mu    sync.Mutex
+	id    int32
+	known bool
+}
+
+// latestWarFunc returns the war of the most recent snapshot, `ok` is false if there is none.
+type latestWarFunc func(ctx context.Context) (id int32, ok bool, err error)
+
+// observe compares `current` with the ongoing war and returns the previous war ID if they differ.
+//
+// Initially, the ongoing war is loaded using `latest`. If no war is known yet, `current` becomes the ongoing war.
+// The ongoing war is not updated on rollover, this is up to the caller using set.
+func (t *warTracker) observe(ctx context.Context, current int32, latest latestWarFunc) (prev int32, rolled bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.known {
+		id, ok, err := latest(ctx)
+		if err != nil {
+			return 0, false, err
+		}
+		if !ok {
+			id = current
+		}
+		t.id, t.known = id, true
+	}
+	if t.id == current {
+		return 0, false, nil
+	}
+	return t.id, true, nil
+}
+
+// set marks `id` as the ongoing war.
+func (t *warTracker) set(id int32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.id, t.known = id, true
+}
+
+// detectRollover checks whether the API reports a new war and closes out the previous war if so.
+//
+// It reports whether a rollover happened.
+// If the previous war cannot be closed, it remains the ongoing war so that closing is retried during the next sync.
+func (w *Worker) detectRollover(ctx context.Context, j *job, data transform.APIData) (bool, error) {
+	if data.WarID == nil || data.WarID.Id == nil {
+		// let the transformation fail with a proper error
+		return false, nil
+	}
+	current := *data.WarID.Id
+	prev, rolled, err := w.war.observe(ctx, current, w.sink.LatestWarID)
+	if err != nil || !rolled {
+		return false, err
+	}
+
+	w.log.InfoContext(ctx, "war season rollover detected", "previous_war", prev, "current_war", current)
+	w.healthLog(ctx, j.healthcheck, fmt.Sprintf("war season rollover detected: war %d ended, war %d started", prev, current))
+
+	if err = w.closeWar(ctx, prev); err != nil {
+		return false, fmt.Errorf("closing out war %d: %w", prev, err)
+	}
+	w.war.set(current)
+	// responses cached during the previous war must not cause entities of the new war to be skipped
+	w.api.InvalidateCache()
+	return true, nil
+}
+
+// closeWar summarizes the snapshots of war `id` and stores its final planet ownership.
+func (w *Worker) closeWar(ctx context.Context, id int32) error {
+	ctx, cancel := context.WithTimeout(ctx, mergeTimeout)
+	defer cancel()
+
+	closure := &db.WarClosure{
+		WarID:     id,
+		CloseTime: db.PGTimestamp(time.Now()),
+	}
+	if err := w.sink.Merge(ctx, stats.NewCollector(), []db.Entity{closure}); err != nil {
+		return err
+	}
+
+	summary, ok, err := w.sink.WarSummary(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		w.log.InfoContext(ctx, "no war summary available", logging.KeyTable, gen.TableWarSummaries.String(), logging.KeyEntityID, id)
+		return nil
+	}
+	players, _ := summary.PeakPlayerCount.Float64Value()
+	w.log.InfoContext(ctx, "summarized war",
+		logging.KeyTable, gen.TableWarSummaries.String(),
+		logging.KeyEntityID, id,
+		"snapshots", summary.SnapshotCount,
+		"first_snapshot_time", summary.FirstSnapshotTime.Time,
+		"final_snapshot_time", summary.FinalSnapshotTime.Time,
+		"peak_players", int64(players.Float64),
+	)
+	return nil
+}
+
+// refreshDependency runs the dependency of `j` again, e.g. to sync static data of a new war.
+func (w *Worker) refreshDependency(ctx context.Context, j *job) error {
+	dep := j.dependsOn
+	if dep == nil {
+		return nil
+	}
+	dep.reset()
+	w.log.InfoContext(ctx, "job requires data of the new war, running dependency first", "job", j.name, "dependency", dep.name)
+	w.runJob(dep)
+	if !dep.hasSucceeded() {
+		return fmt.Errorf("dependency %s job failed for the new war", dep.name)
+	}
+	return nil
+}
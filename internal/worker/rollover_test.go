@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWarTrackerObserve(t *testing.T) {
+	latestWar := func(id int32, ok bool) latestWarFunc {
+		return func(context.Context) (int32, bool, error) {
+			return id, ok, nil
+		}
+	}
+	ctx := context.Background()
+
+	t.Run("no snapshots yet", func(t *testing.T) {
+		var tracker warTracker
+		if _, rolled, err := tracker.observe(ctx, 801, latestWar(0, false)); err != nil || rolled {
+			t.Fatalf("observe() = rolled %v, error %v, want no rollover", rolled, err)
+		}
+		if _, rolled, _ := tracker.observe(ctx, 801, nil); rolled {
+			t.Error("observe() reported rollover for unchanged war")
+		}
+	})
+
+	t.Run("rollover since latest snapshot", func(t *testing.T) {
+		var tracker warTracker
+		prev, rolled, err := tracker.observe(ctx, 802, latestWar(801, true))
+		if err != nil || !rolled || prev != 801 {
+			t.Fatalf("observe() = %d, %v, %v, want 801, true, nil", prev, rolled, err)
+		}
+		// not closed yet, so the rollover is reported again
+		if prev, rolled, _ = tracker.observe(ctx, 802, nil); !rolled || prev != 801 {
+			t.Errorf("observe() = %d, %v, want 801, true", prev, rolled)
+		}
+		tracker.set(802)
+		if _, rolled, _ = tracker.observe(ctx, 802, nil); rolled {
+			t.Error("observe() reported rollover after set()")
+		}
+		if prev, rolled, _ = tracker.observe(ctx, 803, nil); !rolled || prev != 802 {
+			t.Errorf("observe() = %d, %v, want 802, true", prev, rolled)
+		}
+	})
+
+	t.Run("loading fails", func(t *testing.T) {
+		var tracker warTracker
+		failing := func(context.Context) (int32, bool, error) {
+			return 0, false, errors.New("connection refused")
+		}
+		if _, _, err := tracker.observe(ctx, 801, failing); err == nil {
+			t.Fatal("observe() error = nil, want error")
+		}
+		// retried on next call
+		if prev, rolled, err := tracker.observe(ctx, 802, latestWar(801, true)); err != nil || !rolled || prev != 801 {
+			t.Errorf("observe() = %d, %v, %v, want 801, true, nil", prev, rolled, err)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/transform"
+)
+
+// upstreamTime returns the time all API data dates from.
+func upstreamTime(data transform.APIData) (time.Time, bool) {
+	if data.War == nil || data.War.Now == nil {
+		return time.Time{}, false
+	}
+	// the DB stores timestamps in UTC with microsecond precision
+	return data.War.Now.UTC().Truncate(time.Microsecond), true
+}
+
+// snapshotAdvanced reports whether the API data advanced since the latest snapshot,
+// i.e. whether a new snapshot should be created.
+func (w *Worker) snapshotAdvanced(ctx context.Context, data transform.APIData) (bool, error) {
+	now, ok := upstreamTime(data)
+	if !ok {
+		// let the transformation fail with a proper error
+		return true, nil
+	}
+	latest, ok, err := w.sink.LatestSnapshotTime(ctx)
+	if err != nil || !ok {
+		return true, err
+	}
+	return now.After(latest), nil
+}
+
+// checkStale warns if the API data has not advanced for longer than the configured threshold.
+//
+// It returns an error if the data is stale and staleness should be reported as failure.
+func (w *Worker) checkStale(ctx context.Context, data transform.APIData) error {
+	if w.staleThreshold <= 0 {
+		return nil
+	}
+	now, ok := upstreamTime(data)
+	if !ok {
+		return nil
+	}
+	age := time.Since(now)
+	if age <= w.staleThreshold {
+		return nil
+	}
+	w.log.WarnContext(ctx, "API data is stale", "age", age.Round(time.Second), "data_time", now)
+	if w.staleFail {
+		return fmt.Errorf("API data not updated for %s, exceeding threshold of %s", age.Round(time.Second), w.staleThreshold)
+	}
+	return nil
+}
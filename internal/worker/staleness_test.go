@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stnokott/helldivers-client/internal/api"
+	"github.com/stnokott/helldivers-client/internal/logging"
+	"github.com/stnokott/helldivers-client/internal/transform"
+)
+
+func TestWorkerCheckStale(t *testing.T) {
+	dataAt := func(now time.Time) transform.APIData {
+		return transform.APIData{War: &api.War{Now: &now}}
+	}
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		fail      bool
+		data      transform.APIData
+		wantErr   bool
+	}{
+		{
+			name:      "fresh",
+			threshold: time.Hour,
+			fail:      true,
+			data:      dataAt(time.Now().Add(-time.Minute)),
+			wantErr:   false,
+		},
+		{
+			name:      "stale warn only",
+			threshold: time.Hour,
+			fail:      false,
+			data:      dataAt(time.Now().Add(-2 * time.Hour)),
+			wantErr:   false,
+		},
+		{
+			name:      "stale fail",
+			threshold: time.Hour,
+			fail:      true,
+			data:      dataAt(time.Now().Add(-2 * time.Hour)),
+			wantErr:   true,
+		},
+		{
+			name:      "disabled",
+			threshold: 0,
+			fail:      true,
+			data:      dataAt(time.Now().Add(-2 * time.Hour)),
+			wantErr:   false,
+		},
+		{
+			name:      "missing war",
+			threshold: time.Hour,
+			fail:      true,
+			data:      transform.APIData{},
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Worker{
+				staleThreshold: tt.threshold,
+				staleFail:      tt.fail,
+				log:            logging.Discard(),
+			}
+			if err := w.checkStale(context.Background(), tt.data); (err != nil) != tt.wantErr {
+				t.Errorf("checkStale() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,173 +2,398 @@
 
 // Package worker synchronizes data between API and DB.
 //
-// It queries the API at a specified interval and merges the results into the DB.
+// It queries the API at specified intervals and merges the results into the configured sinks.
+// Rarely changing data and snapshots are synced by separate jobs.
 package worker
 
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/google/uuid"
-	health "github.com/stnokott/healthchecks"
+	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/stnokott/helldivers-client/internal/archive"
 	"github.com/stnokott/helldivers-client/internal/client"
 	"github.com/stnokott/helldivers-client/internal/config"
 	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/gen"
+	"github.com/stnokott/helldivers-client/internal/db/stats"
+	"github.com/stnokott/helldivers-client/internal/logging"
+	"github.com/stnokott/helldivers-client/internal/metrics"
+	"github.com/stnokott/helldivers-client/internal/sink"
 	"github.com/stnokott/helldivers-client/internal/transform"
 )
 
-// Worker coordinates communication between API and DB.
+// Worker coordinates communication between API and sinks.
 type Worker struct {
-	api         *client.Client
-	db          *db.Client
-	healthcheck health.Notifier
-	log         *log.Logger
+	api              *client.Client
+	sink             sink.Sink
+	jobs             []*job
+	fetchConcurrency int
+	fetchTimeout     time.Duration
+	staleThreshold   time.Duration
+	staleFail        bool
+	// archive is nil if archiving is disabled
+	archive *archive.Archive
+	// war is the ongoing war, used to detect season rollovers
+	war warTracker
+	// statsTable prints merge statistics as a table instead of logging them
+	statsTable bool
+	// metrics is nil if metrics are disabled
+	metrics *metrics.Metrics
+	log     *slog.Logger
 }
 
+const mergeTimeout = 30 * time.Second
+
 // New creates a new Worker instance.
-func New(api *client.Client, db *db.Client, cfg *config.Config, logger *log.Logger) (*Worker, error) {
-	var healthcheck health.Notifier
-	if cfg.HealthchecksURL != "" {
-		var err error
-		healthcheck, err = health.FromURL(cfg.HealthchecksURL)
-		if err != nil {
-			return nil, fmt.Errorf("preparing healthcheck: %w", err)
-		}
+//
+// Sync runs are recorded in `m`, which may be nil if metrics are disabled.
+func New(api *client.Client, sink sink.Sink, cfg *config.Config, m *metrics.Metrics, logger *slog.Logger) (*Worker, error) {
+	jobs, err := newJobs(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	return &Worker{
-		api:         api,
-		db:          db,
-		healthcheck: healthcheck,
-		log:         logger,
-	}, nil
+	fetchConcurrency := cfg.APIFetchConcurrency
+	if fetchConcurrency < 1 {
+		fetchConcurrency = 1
+	}
+
+	w := &Worker{
+		api:              api,
+		sink:             sink,
+		jobs:             jobs,
+		fetchConcurrency: fetchConcurrency,
+		fetchTimeout:     cfg.APIFetchTimeout,
+		staleThreshold:   cfg.UpstreamStaleThreshold,
+		staleFail:        cfg.UpstreamStaleFail,
+		statsTable:       cfg.LogStatsTable,
+		metrics:          m,
+		log:              logger,
+	}
+	if cfg.ArchiveDir != "" {
+		w.archive = archive.New(cfg.ArchiveDir, cfg.ArchiveRetention)
+	}
+	return w, nil
 }
 
-// Run schedules a new sync job at the specified interval. It is blocking.
-func (w *Worker) Run(cron string, stop <-chan struct{}) error {
+// Run schedules all sync jobs at their configured intervals. It is blocking.
+func (w *Worker) Run(stop <-chan struct{}) error {
 	// create scheduler
 	scheduler, err := gocron.NewScheduler()
 	if err != nil {
 		return fmt.Errorf("creating scheduler: %w", err)
 	}
 
-	printNextRun := func() {
-		if next, errNext := scheduler.Jobs()[0].NextRun(); errNext == nil {
-			w.log.Printf("next run at %v", next)
+	printNextRun := func(id uuid.UUID) {
+		for _, scheduled := range scheduler.Jobs() {
+			if scheduled.ID() != id {
+				continue
+			}
+			if next, errNext := scheduled.NextRun(); errNext == nil {
+				w.log.Info("scheduled next run", "job", scheduled.Name(), "next_run", next)
+			}
 		}
 	}
 
-	// create scheduled job
-	_, err = scheduler.NewJob(
-		gocron.CronJob(cron, false),
-		gocron.NewTask(w.do),
-		gocron.WithSingletonMode(gocron.LimitModeWait),
-		gocron.WithEventListeners(gocron.AfterJobRuns(func(_ uuid.UUID, _ string) {
-			printNextRun()
-		})),
-	)
-	if err != nil {
-		return fmt.Errorf("creating scheduled job: %w", err)
+	// create scheduled jobs
+	for _, j := range w.jobs {
+		_, err = scheduler.NewJob(
+			gocron.CronJob(j.cron, false),
+			gocron.NewTask(w.runJob, j),
+			gocron.WithName(j.name),
+			gocron.WithSingletonMode(gocron.LimitModeWait),
+			gocron.WithEventListeners(gocron.AfterJobRuns(func(id uuid.UUID, _ string) {
+				printNextRun(id)
+			})),
+		)
+		if err != nil {
+			return fmt.Errorf("creating scheduled %s job: %w", j.name, err)
+		}
+		w.log.Info("scheduled job", "job", j.name, "cron", j.cron)
 	}
 
 	scheduler.Start()
-
-	w.log.Printf("started scheduler with cron <%s>", cron)
-	printNextRun()
+	w.log.Info("started scheduler")
+	for _, scheduled := range scheduler.Jobs() {
+		printNextRun(scheduled.ID())
+	}
 
 	<-stop
-	w.log.Println("received stop signal")
+	w.log.Info("received stop signal")
 	return scheduler.Shutdown()
 }
 
-func (w *Worker) do() {
-	w.log.Println("synchronizing")
+// runJob performs a single sync for `j`.
+//
+// Each sync gets a new run ID which is attached to all of its log records and to its entry in the sync_runs ledger.
+// If the job depends on another job which has not succeeded yet, the dependency is run first.
+func (w *Worker) runJob(j *job) {
+	if dep := j.dependsOn; dep != nil && !dep.hasSucceeded() {
+		w.log.Info("job requires a successful dependency, running it first", "job", j.name, "dependency", dep.name)
+		w.runJob(dep)
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	run := newRunRecord(j.name)
+	// fetching and merging use their own timeouts
+	ctx := logging.WithRunID(context.Background(), run.id.String())
+	w.log.InfoContext(ctx, "running job", "job", j.name)
 
-	w.healthNotify(ctx, healthcheckStart)
+	// the run ID allows looking up the run in the logs and the ledger
+	w.healthLog(ctx, j.healthcheck, fmt.Sprintf("sync run %s started", run.id))
+	w.healthNotify(ctx, j.healthcheck, healthcheckStart)
 
 	var err error
 	defer func() {
+		end := time.Now()
+		w.metrics.ObserveSync(j.name, end.Sub(run.start), err)
+		w.recordRun(ctx, run.syncRun(end, err))
 		if err != nil {
-			w.log.Printf("error: %v", err)
-			w.healthNotify(ctx, healthcheckFail)
+			// make sure the data is merged again during the next sync
+			w.api.InvalidateCache()
+			w.log.ErrorContext(ctx, "job failed", "job", j.name, logging.Err(err))
+			w.healthLog(ctx, j.healthcheck, fmt.Sprintf("sync run %s failed: %v", run.id, err))
+			w.healthNotify(ctx, j.healthcheck, healthcheckFail)
 		} else {
-			w.healthNotify(ctx, healthcheckSuccess)
+			j.succeeded = true
+			w.healthLog(ctx, j.healthcheck, fmt.Sprintf("sync run %s succeeded", run.id))
+			w.healthNotify(ctx, j.healthcheck, healthcheckSuccess)
 		}
-		w.log.Println("synchronized")
+		w.log.InfoContext(ctx, "finished job", "job", j.name, "duration", end.Sub(run.start).Round(time.Millisecond))
 	}()
 
-	data := w.queryData(ctx)
+	if dep := j.dependsOn; dep != nil && !dep.hasSucceeded() {
+		err = fmt.Errorf("dependency %s job has not succeeded yet", dep.name)
+		return
+	}
+
+	var data transform.APIData
+	data, run.results = w.queryData(ctx, j.fetch)
 
-	if err = w.mergeData(ctx, data); err != nil {
+	var rolled bool
+	if rolled, err = w.detectRollover(ctx, j, data); err != nil {
 		return
 	}
-}
+	if rolled {
+		if err = w.refreshDependency(ctx, j); err != nil {
+			return
+		}
+	}
 
-func (w *Worker) queryData(ctx context.Context) (data transform.APIData) {
-	var err error
-	data.WarID, err = w.api.WarID(ctx)
-	if err != nil {
-		w.log.Printf("failed to query current war ID: %v", err)
+	if j.merges(groupSnapshots) {
+		w.archiveData(ctx, data, run.results)
 	}
-	data.War, err = w.api.War(ctx)
-	if err != nil {
-		w.log.Printf("failed to query current war: %v", err)
+
+	mergeCtx, cancel := context.WithTimeout(ctx, mergeTimeout)
+	defer cancel()
+	collector := stats.NewCollector()
+	var snapshotTime pgtype.Timestamp
+	if snapshotTime, err = w.mergeData(mergeCtx, j, data, run.results, collector); err != nil {
+		return
 	}
-	data.Campaigns, err = w.api.Campaigns(ctx)
-	if err != nil {
-		w.log.Printf("failed to query campaigns: %v", err)
+	run.merged(collector, snapshotTime)
+	if j.merges(groupSnapshots) {
+		w.recordDomainMetrics(data)
 	}
-	data.Planets, err = w.api.Planets(ctx)
-	if err != nil {
-		w.log.Printf("failed to query planets: %v", err)
+	if j.checkStale {
+		err = w.checkStale(ctx, data)
 	}
-	data.Assignments, err = w.api.Assignments(ctx)
-	if err != nil {
-		w.log.Printf("failed to query assignments: %v", err)
+}
+
+// archiveData stores the raw API data in the archive, if enabled.
+//
+// Incomplete data is not archived since it cannot be turned into a snapshot later.
+func (w *Worker) archiveData(ctx context.Context, data transform.APIData, results []fetchResult) {
+	if w.archive == nil {
+		return
 	}
-	data.Dispatches, err = w.api.Dispatches(ctx)
-	if err != nil {
-		w.log.Printf("failed to query dispatches: %v", err)
+	for _, result := range results {
+		if result.Err != nil {
+			w.log.InfoContext(ctx, "not archiving API data, endpoint unavailable", logging.KeyEndpoint, result.Endpoint)
+			return
+		}
+	}
+	if err := w.archive.Write(data); err != nil {
+		w.log.WarnContext(ctx, "failed to archive API data", logging.Err(err))
 	}
-	return
 }
 
-func (w *Worker) mergeData(ctx context.Context, data transform.APIData) (err error) {
-	w.log.Println("transforming API responses")
-	var wars, events, planets, campaigns, assignments, dispatches, snapshots []db.EntityMerger
+// entityGroup describes how to transform API data into DB entities of one kind.
+type entityGroup struct {
+	name string
+	// endpoints are the API endpoints this group is built from, including the war ID for war-scoped entities
+	endpoints []string
+	// tables are the DB tables this group merges into
+	tables []gen.Table
+	// timeSeries groups are created on every sync as long as the API data advanced
+	timeSeries bool
+	transform  func(transform.Converter, transform.APIData) ([]db.Entity, error)
+}
 
-	converter := &transform.ConverterImpl{}
-	if wars, err = transform.Wars(converter, data); err != nil {
-		return
+// names of the entity groups
+const (
+	groupWars               = "wars"
+	groupCampaigns          = "campaigns"
+	groupEvents             = "events"
+	groupPlanets            = "planets"
+	groupAssignments        = "assignments"
+	groupDispatches         = "dispatches"
+	groupSnapshots          = "snapshots"
+	groupCampaignLifecycles = "campaign lifecycles"
+)
+
+// entityGroups lists all groups in merge order, which is important due to FK constraints.
+var entityGroups = []entityGroup{
+	{
+		name:      groupWars,
+		endpoints: []string{endpointWarID, endpointWar},
+		tables:    []gen.Table{gen.TableWars},
+		transform: transform.Wars,
+	},
+	{
+		name:      groupCampaigns,
+		endpoints: []string{endpointWarID, endpointCampaigns},
+		tables:    []gen.Table{gen.TableCampaigns},
+		transform: transform.Campaigns,
+	},
+	{
+		name:      groupEvents,
+		endpoints: []string{endpointWarID, endpointPlanets, endpointCampaigns},
+		tables:    []gen.Table{gen.TableEvents},
+		transform: transform.Events,
+	},
+	{
+		name:      groupPlanets,
+		endpoints: []string{endpointWarID, endpointPlanets},
+		tables:    []gen.Table{gen.TablePlanets, gen.TableBiomes, gen.TableHazards},
+		transform: transform.Planets,
+	},
+	{
+		name:      groupAssignments,
+		endpoints: []string{endpointWarID, endpointAssignments},
+		tables:    []gen.Table{gen.TableAssignments, gen.TableAssignmentTasks},
+		transform: transform.Assignments,
+	},
+	{
+		name:      groupDispatches,
+		endpoints: []string{endpointDispatches},
+		tables:    []gen.Table{gen.TableDispatches},
+		transform: transform.Dispatches,
+	},
+	{
+		name: groupSnapshots,
+		tables: []gen.Table{
+			gen.TableSnapshots,
+			gen.TableWarSnapshots,
+			gen.TableEventSnapshots,
+			gen.TableAssignmentSnapshots,
+			gen.TablePlanetSnapshots,
+			gen.TableSnapshotStatistics,
+		},
+		timeSeries: true,
+		transform:  transform.Snapshot,
+	},
+	{
+		// no endpoints since campaigns which are still active need to be extended on every snapshot
+		name:       groupCampaignLifecycles,
+		tables:     []gen.Table{gen.TableCampaignLifecycles},
+		timeSeries: true,
+		transform:  transform.CampaignLifecycles,
+	},
+}
+
+// unchanged reports whether all source endpoints of the group returned the same data as in the previous sync.
+func (g entityGroup) unchanged(unchangedEndpoints map[string]bool) bool {
+	if len(g.endpoints) == 0 {
+		return false
 	}
-	if campaigns, err = transform.Campaigns(converter, data); err != nil {
-		return
+	for _, endpoint := range g.endpoints {
+		if !unchangedEndpoints[endpoint] {
+			return false
+		}
 	}
-	if events, err = transform.Events(converter, data); err != nil {
+	return true
+}
+
+// mergeData transforms the API data and merges the entity groups of `j` into the sink.
+//
+// Entity groups whose source data did not change since the previous sync are skipped.
+// Snapshots are only created if the API data time advanced since the latest snapshot,
+// the time of the created snapshot is returned if so.
+// Merge statistics are added to `collector`.
+func (w *Worker) mergeData(ctx context.Context, j *job, data transform.APIData, results []fetchResult, collector stats.Collector) (snapshotTime pgtype.Timestamp, err error) {
+	w.log.DebugContext(ctx, "transforming API responses")
+	unchanged := unchangedEndpoints(results)
+	advanced, err := w.snapshotAdvanced(ctx, data)
+	if err != nil {
 		return
 	}
-	if planets, err = transform.Planets(converter, data); err != nil {
-		return
+	mergers := make([][]db.Entity, 0, len(entityGroups))
+
+	converter := &transform.ConverterImpl{}
+	for _, group := range entityGroups {
+		if !j.merges(group.name) {
+			continue
+		}
+		skipReason := ""
+		if group.unchanged(unchanged) {
+			skipReason = "source data unchanged"
+		} else if group.timeSeries && !advanced {
+			skipReason = "API data time has not advanced since latest snapshot"
+		}
+		if skipReason != "" {
+			w.log.InfoContext(ctx, "skipping entity group", "group", group.name, "reason", skipReason)
+			for _, table := range group.tables {
+				collector.SourceUnchanged(table)
+			}
+			continue
+		}
+		var groupMergers []db.Entity
+		if groupMergers, err = group.transform(converter, data); err != nil {
+			err = fmt.Errorf("transforming %s: %w", group.name, err)
+			return
+		}
+		mergers = append(mergers, groupMergers)
 	}
-	if assignments, err = transform.Assignments(converter, data); err != nil {
+
+	w.log.DebugContext(ctx, "merging transformed entities into sink")
+	if err = w.sink.Merge(ctx, collector, mergers...); err != nil {
 		return
 	}
-	if dispatches, err = transform.Dispatches(converter, data); err != nil {
-		return
+	snapshotTime = mergedSnapshotTime(mergers)
+	w.metrics.ObserveMerge(collector)
+	w.reportStats(ctx, collector)
+	return
+}
+
+// mergedSnapshotTime returns the time of the snapshot contained in `mergers`, if any.
+func mergedSnapshotTime(mergers [][]db.Entity) pgtype.Timestamp {
+	for _, mSlice := range mergers {
+		for _, merger := range mSlice {
+			if snapshot, ok := merger.(*db.Snapshot); ok {
+				return snapshot.CreateTime
+			}
+		}
 	}
-	if snapshots, err = transform.Snapshot(converter, data); err != nil {
+	return pgtype.Timestamp{}
+}
+
+// reportStats prints the merge statistics as a table or logs them, depending on the configuration.
+func (w *Worker) reportStats(ctx context.Context, collector stats.Collector) {
+	if !w.statsTable {
+		collector.Log(ctx, w.log)
 		return
 	}
-
-	w.log.Println("merging transformed entities into database")
-	// order is important here due to FK constraints
-	err = w.db.Merge(ctx, wars, campaigns, events, planets, assignments, dispatches, snapshots)
-	return
+	if err := collector.Print(os.Stdout); err != nil {
+		w.log.WarnContext(ctx, "failed to print merge statistics", logging.Err(err))
+	}
 }
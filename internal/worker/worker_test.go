@@ -4,7 +4,7 @@ package worker
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"testing"
 
 	"github.com/stnokott/helldivers-client/internal/client"
@@ -14,15 +14,15 @@ import (
 
 func mustWorker() *Worker {
 	cfg := config.MustGet()
-	api, err := client.New(cfg, log.Default())
+	api, err := client.New(cfg, client.DefaultIdentity("helldivers-client", "0.0.0", "test"), nil, slog.Default())
 	if err != nil {
 		panic(err)
 	}
-	db, err := db.New(cfg, log.Default())
+	db, err := db.New(cfg, slog.Default())
 	if err != nil {
 		panic(err)
 	}
-	worker, err := New(api, db, cfg, log.Default())
+	worker, err := New(api, db, cfg, nil, slog.Default())
 	if err != nil {
 		panic(err)
 	}
@@ -32,17 +32,30 @@ func mustWorker() *Worker {
 func TestWorkerQueryData(t *testing.T) {
 	worker := mustWorker()
 
-	got := worker.queryData(context.Background())
-	if got.Planets == nil {
-		t.Error("Worker.queryData().Planets = nil, want non-nil")
-		return
-	}
-	if got.WarID == nil {
-		t.Error("Worker.queryData().WarID = nil, want non-nil")
-		return
-	}
-	if got.War == nil {
-		t.Error("Worker.queryData().War = nil, want non-nil")
-		return
+	for _, j := range worker.jobs {
+		t.Run(j.name, func(t *testing.T) {
+			got, results := worker.queryData(context.Background(), j.fetch)
+			for _, result := range results {
+				if result.Err != nil {
+					t.Errorf("Worker.queryData() endpoint %s error = %v, want nil", result.Endpoint, result.Err)
+					return
+				}
+			}
+			if got.Planets == nil {
+				t.Error("Worker.queryData().Planets = nil, want non-nil")
+				return
+			}
+			if got.WarID == nil {
+				t.Error("Worker.queryData().WarID = nil, want non-nil")
+				return
+			}
+			if got.War == nil {
+				t.Error("Worker.queryData().War = nil, want non-nil")
+				return
+			}
+			if j.fetch.campaignPlanets != nil && got.Campaigns != nil && len(*got.Planets) > len(*got.Campaigns) {
+				t.Errorf("Worker.queryData() got %d planets for %d campaigns, want at most one planet per campaign", len(*got.Planets), len(*got.Campaigns))
+			}
+		})
 	}
 }
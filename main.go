@@ -3,7 +3,7 @@ package main
 
 import (
 	"flag"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 )
@@ -16,26 +16,43 @@ var (
 func main() {
 	flag.Parse()
 
+	// subcommands return their errors instead of exiting, so that their deferred cleanup runs first
+	var err error
+	switch flag.Arg(0) {
+	case "export":
+		err = runExport(flag.Args()[1:])
+	case "gaps":
+		err = runGaps(flag.Args()[1:])
+	default:
+		err = runWorker()
+	}
+	if err != nil {
+		fatal(slog.Default(), err)
+	}
+}
+
+// runWorker runs the worker until it receives SIGINT.
+func runWorker() error {
 	workerStopChan := make(chan struct{})
 
 	// stop worker on SIGINT
-	osSignalChan := make(chan os.Signal)
+	osSignalChan := make(chan os.Signal, 1)
 	signal.Notify(osSignalChan, os.Interrupt)
 	go func() {
 		s := <-osSignalChan
-		log.Printf("main loop received %s signal, sending stop signal to worker\n", s.String())
+		slog.Info("main loop received signal, sending stop signal to worker", "signal", s.String())
 		workerStopChan <- struct{}{}
 	}()
 
 	// check if profiling is enabled
 	if *pprofDuration != 0 {
-		log.Printf("profiling enabled, duration=%s, out=%s\n", pprofDuration.String(), *pprofOut)
+		slog.Info("profiling enabled", "duration", *pprofDuration, "out", *pprofOut)
 		if err := startProfiling(*pprofDuration, *pprofOut, workerStopChan); err != nil {
-			log.Fatalln(err)
+			return err
 		}
 	}
 
 	// we separate the run() and main() function so that we can include additional
 	// pre- and post-mainloop logic like profiling.
-	run(workerStopChan)
+	return run(workerStopChan)
 }
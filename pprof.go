@@ -2,10 +2,12 @@ package main
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"runtime/pprof"
 	"time"
+
+	"github.com/stnokott/helldivers-client/internal/logging"
 )
 
 // startProfiling will start CPU profiling. After `duration`, it will stop profiling and will send a signal to `stopChan`.
@@ -27,7 +29,7 @@ func startProfiling(duration time.Duration, out string, stopChan chan<- struct{}
 		// stop profiling
 		pprof.StopCPUProfile()
 		if err := fCPU.Close(); err != nil {
-			log.Println(err)
+			slog.Error("closing profile failed", logging.Err(err))
 		}
 		// stop worker
 		stopChan <- struct{}{}
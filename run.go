@@ -2,14 +2,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/stnokott/helldivers-client/internal/client"
 	"github.com/stnokott/helldivers-client/internal/config"
 	"github.com/stnokott/helldivers-client/internal/db"
+	"github.com/stnokott/helldivers-client/internal/db/sqlite"
+	"github.com/stnokott/helldivers-client/internal/logging"
+	"github.com/stnokott/helldivers-client/internal/metrics"
+	"github.com/stnokott/helldivers-client/internal/sink"
 	"github.com/stnokott/helldivers-client/internal/worker"
 )
 
@@ -27,47 +34,200 @@ const (
 
 const apiReadyTimeout = 30 * time.Second
 
-func run(stopChan <-chan struct{}) {
+const metricsShutdownTimeout = 5 * time.Second
+
+func run(stopChan <-chan struct{}) error {
 	fmt.Printf("%s v%s %s built %s\n\n", projectName, version, commit, buildDate)
 
 	cfg := config.MustGet()
+	setupLogging(cfg)
 	logger := loggerFor("main")
+	for _, msg := range cfg.Deprecations() {
+		logger.Warn(msg)
+	}
+	for _, msg := range cfg.Limitations() {
+		logger.Warn(msg)
+	}
 
-	dbClient, err := db.New(cfg, loggerFor("postgresql"))
+	dataSink, closeSinks := mustOpenSinks(cfg, logger)
+	defer closeSinks()
+
+	var m *metrics.Metrics
+	if cfg.MetricsAddr != "" {
+		m = metrics.New(cfg.MetricsDomain)
+		stopMetrics := serveMetrics(cfg.MetricsAddr, m, loggerFor("metrics"))
+		defer stopMetrics()
+	}
+
+	apiClient, err := client.New(cfg, client.DefaultIdentity(projectName, version, commit), m, loggerFor("api"))
 	if err != nil {
-		logger.Fatal(err)
+		return err
 	}
-	if err = waitFor(dbClient, dbReadyTimeout, logger); err != nil {
-		logger.Fatal(err)
+	if err = waitFor(apiClient, apiReadyTimeout, logger); err != nil {
+		return err
+	}
+
+	worker, err := worker.New(apiClient, dataSink, cfg, m, loggerFor("worker"))
+	if err != nil {
+		return err
 	}
 
-	defer func() {
-		if errInner := dbClient.Disconnect(); errInner != nil {
-			logger.Println(errInner)
+	return worker.Run(stopChan)
+}
+
+// serveMetrics serves `m` at /metrics on `addr` in the background.
+//
+// The returned function stops the server.
+func serveMetrics(addr string, m *metrics.Metrics, logger *slog.Logger) func() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		logger.Info("serving metrics", "addr", addr, "path", "/metrics")
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			logger.Warn("metrics server stopped", logging.Err(err))
 		}
 	}()
-	if err = dbClient.MigrateUp("./scripts/migrations"); err != nil {
-		logger.Fatal(err)
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Error("stopping metrics server failed", logging.Err(err))
+		}
 	}
+}
 
-	apiClient, err := client.New(cfg, loggerFor("api"))
+// mustOpenSinks opens all sinks configured in SINKS.
+//
+// The returned function closes all opened sinks.
+func mustOpenSinks(cfg *config.Config, logger *slog.Logger) (sink.Sink, func()) {
+	if len(cfg.Sinks) == 0 {
+		fatal(logger, errors.New("no sinks configured in SINKS"))
+	}
+	var (
+		sinks   sink.Multi
+		closers []func()
+	)
+	closeAll := func() {
+		for _, closeSink := range closers {
+			closeSink()
+		}
+	}
+	for _, name := range cfg.Sinks {
+		switch name {
+		case sink.NamePostgres:
+			dbClient := mustConnectDB(cfg, logger)
+			sinks = append(sinks, dbClient)
+			closers = append(closers, func() { disconnectDB(dbClient, logger) })
+		case sink.NameSQLite:
+			sqliteClient, err := openSQLite(cfg)
+			if err != nil {
+				closeAll()
+				fatal(logger, err)
+			}
+			sinks = append(sinks, sqliteClient)
+			closers = append(closers, func() {
+				if err := sqliteClient.Disconnect(); err != nil {
+					logger.Error("closing sink failed", "sink", sink.NameSQLite, logging.Err(err))
+				}
+			})
+		case sink.NameJSONL:
+			if cfg.JSONLPath == "" {
+				closeAll()
+				fatal(logger, errors.New("JSONL_PATH is required for the jsonl sink"))
+			}
+			jsonl, err := sink.NewJSONL(cfg.JSONLPath, loggerFor("jsonl"))
+			if err != nil {
+				closeAll()
+				fatal(logger, err)
+			}
+			sinks = append(sinks, jsonl)
+			closers = append(closers, func() {
+				if err := jsonl.Close(); err != nil {
+					logger.Error("closing sink failed", "sink", sink.NameJSONL, logging.Err(err))
+				}
+			})
+		default:
+			closeAll()
+			fatal(logger, fmt.Errorf("unknown sink %q in SINKS, available: %s, %s, %s", name, sink.NamePostgres, sink.NameSQLite, sink.NameJSONL))
+		}
+	}
+	if len(sinks) == 1 {
+		return sinks[0], closeAll
+	}
+	return sinks, closeAll
+}
+
+// mustConnectDB connects to the database and migrates it to the latest version.
+func mustConnectDB(cfg *config.Config, logger *slog.Logger) *db.Client {
+	if cfg.PostgresURI == "" {
+		fatal(logger, errors.New("POSTGRES_URI is required"))
+	}
+	dbClient, err := db.New(cfg, loggerFor("postgresql"))
 	if err != nil {
-		logger.Fatal(err)
+		fatal(logger, err)
 	}
-	if err = waitFor(apiClient, apiReadyTimeout, logger); err != nil {
-		logger.Fatal(err)
+	if err = waitFor(dbClient, dbReadyTimeout, logger); err != nil {
+		fatal(logger, err)
 	}
+	if err = dbClient.MigrateUp("./scripts/migrations"); err != nil {
+		disconnectDB(dbClient, logger)
+		fatal(logger, err)
+	}
+	return dbClient
+}
 
-	worker, err := worker.New(apiClient, dbClient, cfg, loggerFor("worker"))
+// openSQLite opens the SQLite database and migrates it to the latest version.
+func openSQLite(cfg *config.Config) (*sqlite.Client, error) {
+	if cfg.SQLiteDSN == "" {
+		return nil, errors.New("SQLITE_DSN is required for the sqlite sink")
+	}
+	client, err := sqlite.New(cfg.SQLiteDSN, loggerFor("sqlite"))
 	if err != nil {
-		logger.Fatal(err)
+		return nil, err
+	}
+	if err = client.MigrateUp("./scripts/sqlite/migrations"); err != nil {
+		client.Disconnect()
+		return nil, err
+	}
+	return client, nil
+}
+
+func disconnectDB(dbClient *db.Client, logger *slog.Logger) {
+	if err := dbClient.Disconnect(); err != nil {
+		logger.Error("disconnecting from database failed", logging.Err(err))
 	}
+}
+
+// baseLogger is configured by setupLogging once the configuration has been read.
+var baseLogger = slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	if err = worker.Run(cfg.WorkerCron, stopChan); err != nil {
-		logger.Fatal(err)
+// setupLogging configures the format and level of all loggers according to `cfg`.
+//
+// Messages of the standard log package are written to the same logger.
+func setupLogging(cfg *config.Config) {
+	logger, err := logging.New(os.Stdout, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		fmt.Printf("failed to configure logging: %v\n", err)
+		os.Exit(1)
 	}
+	baseLogger = logger
+	slog.SetDefault(logger)
+}
+
+// loggerFor returns a logger whose records are attributed to `component`.
+func loggerFor(component string) *slog.Logger {
+	return baseLogger.With(logging.KeyComponent, component)
 }
 
-func loggerFor(name string) *log.Logger {
-	return log.New(os.Stdout, name+" | ", log.Ldate|log.Ltime|log.Lmsgprefix)
+// fatal logs `err` and exits.
+//
+// Deferred functions do not run, so it must not be called once resources need to be released.
+func fatal(logger *slog.Logger, err error) {
+	logger.Error("fatal error", logging.Err(err))
+	os.Exit(1)
 }
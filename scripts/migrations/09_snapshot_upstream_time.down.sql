@@ -0,0 +1,5 @@
+ALTER TABLE snapshots
+ALTER COLUMN create_time SET DEFAULT CURRENT_TIMESTAMP;
+
+COMMENT ON COLUMN snapshots.create_time
+    IS 'The time the snapshot of the war was taken, auto-generated as current timestamp';
@@ -0,0 +1,5 @@
+ALTER TABLE snapshots
+ALTER COLUMN create_time DROP DEFAULT;
+
+COMMENT ON COLUMN snapshots.create_time
+    IS 'The time the snapshot of the war was taken, as reported by the API. All other data of the snapshot dates from this time.';
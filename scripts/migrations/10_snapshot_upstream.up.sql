@@ -0,0 +1,5 @@
+ALTER TABLE snapshots
+ADD COLUMN upstream text;
+
+COMMENT ON COLUMN snapshots.upstream
+    IS 'Root URL of the API instance which served the snapshot data, NULL for snapshots taken before it was recorded';
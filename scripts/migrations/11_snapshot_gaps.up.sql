@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS snapshot_gaps
+(
+    start_time timestamp NOT NULL REFERENCES snapshots (create_time),
+    end_time timestamp NOT NULL REFERENCES snapshots (create_time),
+    missed_runs integer NOT NULL CHECK (missed_runs > 0),
+    PRIMARY KEY (start_time),
+    CHECK (end_time > start_time)
+);
+
+COMMENT ON TABLE snapshot_gaps
+    IS 'Marks intervals between two consecutive snapshots in which scheduled snapshots are missing, e.g. because the worker was down. Charts should not interpolate across these.';
+
+COMMENT ON COLUMN snapshot_gaps.start_time
+    IS 'Time of the last snapshot before the gap';
+
+COMMENT ON COLUMN snapshot_gaps.end_time
+    IS 'Time of the first snapshot after the gap';
+
+COMMENT ON COLUMN snapshot_gaps.missed_runs
+    IS 'Number of scheduled snapshot runs which did not produce a snapshot';
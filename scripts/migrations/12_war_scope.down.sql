@@ -0,0 +1,103 @@
+CREATE OR REPLACE FUNCTION validate_planet_snapshot_refs() RETURNS TRIGGER AS $validate_planet_snapshot_refs$
+	DECLARE
+		new_attacking_planet_id integer;
+    BEGIN
+		-- check attacking planet IDs
+		FOREACH new_attacking_planet_id IN ARRAY NEW.attacking_planet_ids LOOP
+			IF NOT EXISTS (SELECT 1 FROM planets WHERE id = new_attacking_planet_id) THEN
+				RAISE EXCEPTION 'planet statistic ID=% has non-existent attacking planet ID %', NEW.id, new_attacking_planet_id;
+			END IF;
+		END LOOP;
+
+        RETURN NEW;
+    END;
+$validate_planet_snapshot_refs$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION validate_snapshot_refs() RETURNS TRIGGER AS $validate_snapshot_refs$
+	DECLARE
+		new_assignment_snapshot_id bigint;
+        new_campaign_id integer;
+        new_dispatch_id integer;
+        new_planet_snapshot_id integer;
+    BEGIN
+		-- check assignment snapshot refs
+		FOREACH new_assignment_snapshot_id IN ARRAY NEW.assignment_snapshot_ids LOOP
+			IF NOT EXISTS (SELECT 1 FROM assignment_snapshots WHERE id = new_assignment_snapshot_id) THEN
+				RAISE EXCEPTION 'snapshot at % has non-existent assignment snapshot ID %', NEW.create_time, new_assignment_snapshot_id;
+			END IF;
+		END LOOP;
+
+        -- check campaign refs
+		FOREACH new_campaign_id IN ARRAY NEW.campaign_ids LOOP
+			IF NOT EXISTS (SELECT 1 FROM campaigns WHERE id = new_campaign_id) THEN
+				RAISE EXCEPTION 'snapshot at % has non-existent campaign ID %', NEW.create_time, new_campaign_id;
+			END IF;
+		END LOOP;
+
+        -- check dispatch refs
+		FOREACH new_dispatch_id IN ARRAY NEW.dispatch_ids LOOP
+			IF NOT EXISTS (SELECT 1 FROM dispatches WHERE id = new_dispatch_id) THEN
+				RAISE EXCEPTION 'snapshot at % has non-existent dispatch ID %', NEW.create_time, new_dispatch_id;
+			END IF;
+		END LOOP;
+
+        -- check planet snapshot refs
+		FOREACH new_planet_snapshot_id IN ARRAY NEW.planet_snapshot_ids LOOP
+			IF NOT EXISTS (SELECT 1 FROM planet_snapshots WHERE id = new_planet_snapshot_id) THEN
+				RAISE EXCEPTION 'snapshot at % has non-existent planet snapshot ID %', NEW.create_time, new_planet_snapshot_id;
+			END IF;
+		END LOOP;
+
+        RETURN NEW;
+    END;
+$validate_snapshot_refs$ LANGUAGE plpgsql;
+
+
+
+-- fails if data of more than one war exists
+ALTER TABLE assignment_snapshots DROP CONSTRAINT IF EXISTS assignment_snapshots_war_id_assignment_id_fkey;
+ALTER TABLE event_snapshots DROP CONSTRAINT IF EXISTS event_snapshots_war_id_event_id_fkey;
+ALTER TABLE planet_snapshots DROP CONSTRAINT IF EXISTS planet_snapshots_war_id_planet_id_fkey;
+ALTER TABLE war_snapshots DROP CONSTRAINT IF EXISTS war_snapshots_war_id_fkey;
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_war_id_campaign_id_fkey;
+
+ALTER TABLE assignments
+DROP CONSTRAINT assignments_war_id_fkey,
+DROP CONSTRAINT assignments_pkey,
+ADD CONSTRAINT assignments_id_key UNIQUE (id),
+ADD PRIMARY KEY (id);
+
+ALTER TABLE events
+DROP CONSTRAINT events_war_id_fkey,
+DROP CONSTRAINT events_pkey,
+ADD CONSTRAINT events_id_key UNIQUE (id),
+ADD PRIMARY KEY (id);
+
+ALTER TABLE campaigns
+DROP CONSTRAINT campaigns_war_id_fkey,
+DROP CONSTRAINT campaigns_pkey,
+ADD CONSTRAINT campaigns_id_key UNIQUE (id),
+ADD PRIMARY KEY (id);
+
+ALTER TABLE planets
+DROP CONSTRAINT planets_war_id_fkey,
+DROP CONSTRAINT planets_war_id_name_key,
+DROP CONSTRAINT planets_pkey,
+ADD CONSTRAINT planets_name_key UNIQUE (name),
+ADD CONSTRAINT planets_id_key UNIQUE (id),
+ADD PRIMARY KEY (id);
+
+ALTER TABLE events ADD FOREIGN KEY (campaign_id) REFERENCES campaigns;
+ALTER TABLE planet_snapshots ADD FOREIGN KEY (planet_id) REFERENCES planets;
+ALTER TABLE event_snapshots ADD FOREIGN KEY (event_id) REFERENCES events;
+ALTER TABLE assignment_snapshots ADD FOREIGN KEY (assignment_id) REFERENCES assignments;
+
+
+
+ALTER TABLE assignment_snapshots DROP COLUMN IF EXISTS war_id;
+ALTER TABLE event_snapshots DROP COLUMN IF EXISTS war_id;
+ALTER TABLE planet_snapshots DROP COLUMN IF EXISTS war_id;
+ALTER TABLE assignments DROP COLUMN IF EXISTS war_id;
+ALTER TABLE events DROP COLUMN IF EXISTS war_id;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS war_id;
+ALTER TABLE planets DROP COLUMN IF EXISTS war_id;
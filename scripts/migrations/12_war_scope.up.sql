@@ -0,0 +1,160 @@
+-- all data collected so far dates from war season 801
+ALTER TABLE planets
+ADD COLUMN war_id integer NOT NULL DEFAULT 801;
+
+ALTER TABLE campaigns
+ADD COLUMN war_id integer NOT NULL DEFAULT 801;
+
+ALTER TABLE events
+ADD COLUMN war_id integer NOT NULL DEFAULT 801;
+
+ALTER TABLE assignments
+ADD COLUMN war_id integer NOT NULL DEFAULT 801;
+
+ALTER TABLE planet_snapshots
+ADD COLUMN war_id integer NOT NULL DEFAULT 801;
+
+ALTER TABLE event_snapshots
+ADD COLUMN war_id integer NOT NULL DEFAULT 801;
+
+ALTER TABLE assignment_snapshots
+ADD COLUMN war_id integer NOT NULL DEFAULT 801;
+
+ALTER TABLE planets ALTER COLUMN war_id DROP DEFAULT;
+ALTER TABLE campaigns ALTER COLUMN war_id DROP DEFAULT;
+ALTER TABLE events ALTER COLUMN war_id DROP DEFAULT;
+ALTER TABLE assignments ALTER COLUMN war_id DROP DEFAULT;
+ALTER TABLE planet_snapshots ALTER COLUMN war_id DROP DEFAULT;
+ALTER TABLE event_snapshots ALTER COLUMN war_id DROP DEFAULT;
+ALTER TABLE assignment_snapshots ALTER COLUMN war_id DROP DEFAULT;
+
+
+
+-- references to single-column keys need to be dropped before the keys can be replaced
+ALTER TABLE events DROP CONSTRAINT events_campaign_id_fkey;
+ALTER TABLE planet_snapshots DROP CONSTRAINT planet_snapshots_planet_id_fkey;
+ALTER TABLE event_snapshots DROP CONSTRAINT event_snapshots_event_id_fkey;
+ALTER TABLE assignment_snapshots DROP CONSTRAINT assignment_snapshots_assignment_id_fkey;
+
+-- wars are only merged by the worker since war tracking exists, so older rows may lack their war.
+-- NOT VALID skips checking existing rows while still enforcing the reference for new ones.
+ALTER TABLE planets
+DROP CONSTRAINT planets_pkey,
+DROP CONSTRAINT planets_id_key,
+DROP CONSTRAINT planets_name_key,
+ADD PRIMARY KEY (war_id, id),
+ADD CONSTRAINT planets_war_id_name_key UNIQUE (war_id, name),
+ADD FOREIGN KEY (war_id) REFERENCES wars NOT VALID;
+
+ALTER TABLE campaigns
+DROP CONSTRAINT campaigns_pkey,
+DROP CONSTRAINT campaigns_id_key,
+ADD PRIMARY KEY (war_id, id),
+ADD FOREIGN KEY (war_id) REFERENCES wars NOT VALID;
+
+ALTER TABLE events
+DROP CONSTRAINT events_pkey,
+DROP CONSTRAINT events_id_key,
+ADD PRIMARY KEY (war_id, id),
+ADD FOREIGN KEY (war_id) REFERENCES wars NOT VALID,
+ADD FOREIGN KEY (war_id, campaign_id) REFERENCES campaigns;
+
+ALTER TABLE assignments
+DROP CONSTRAINT assignments_pkey,
+DROP CONSTRAINT assignments_id_key,
+ADD PRIMARY KEY (war_id, id),
+ADD FOREIGN KEY (war_id) REFERENCES wars NOT VALID;
+
+ALTER TABLE war_snapshots
+ADD FOREIGN KEY (war_id) REFERENCES wars NOT VALID;
+
+ALTER TABLE planet_snapshots
+ADD FOREIGN KEY (war_id, planet_id) REFERENCES planets;
+
+ALTER TABLE event_snapshots
+ADD FOREIGN KEY (war_id, event_id) REFERENCES events;
+
+ALTER TABLE assignment_snapshots
+ADD FOREIGN KEY (war_id, assignment_id) REFERENCES assignments;
+
+
+
+CREATE OR REPLACE FUNCTION validate_planet_snapshot_refs() RETURNS TRIGGER AS $validate_planet_snapshot_refs$
+	DECLARE
+		new_attacking_planet_id integer;
+    BEGIN
+		-- check attacking planet IDs
+		FOREACH new_attacking_planet_id IN ARRAY NEW.attacking_planet_ids LOOP
+			IF NOT EXISTS (SELECT 1 FROM planets WHERE war_id = NEW.war_id AND id = new_attacking_planet_id) THEN
+				RAISE EXCEPTION 'planet statistic ID=% has non-existent attacking planet ID %', NEW.id, new_attacking_planet_id;
+			END IF;
+		END LOOP;
+
+        RETURN NEW;
+    END;
+$validate_planet_snapshot_refs$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION validate_snapshot_refs() RETURNS TRIGGER AS $validate_snapshot_refs$
+	DECLARE
+		snapshot_war_id integer;
+		new_assignment_snapshot_id bigint;
+        new_campaign_id integer;
+        new_dispatch_id integer;
+        new_planet_snapshot_id integer;
+    BEGIN
+		SELECT war_id INTO snapshot_war_id FROM war_snapshots WHERE id = NEW.war_snapshot_id;
+
+		-- check assignment snapshot refs
+		FOREACH new_assignment_snapshot_id IN ARRAY NEW.assignment_snapshot_ids LOOP
+			IF NOT EXISTS (SELECT 1 FROM assignment_snapshots WHERE id = new_assignment_snapshot_id) THEN
+				RAISE EXCEPTION 'snapshot at % has non-existent assignment snapshot ID %', NEW.create_time, new_assignment_snapshot_id;
+			END IF;
+		END LOOP;
+
+        -- check campaign refs
+		FOREACH new_campaign_id IN ARRAY NEW.campaign_ids LOOP
+			IF NOT EXISTS (SELECT 1 FROM campaigns WHERE war_id = snapshot_war_id AND id = new_campaign_id) THEN
+				RAISE EXCEPTION 'snapshot at % has non-existent campaign ID % in war %', NEW.create_time, new_campaign_id, snapshot_war_id;
+			END IF;
+		END LOOP;
+
+        -- check dispatch refs
+		FOREACH new_dispatch_id IN ARRAY NEW.dispatch_ids LOOP
+			IF NOT EXISTS (SELECT 1 FROM dispatches WHERE id = new_dispatch_id) THEN
+				RAISE EXCEPTION 'snapshot at % has non-existent dispatch ID %', NEW.create_time, new_dispatch_id;
+			END IF;
+		END LOOP;
+
+        -- check planet snapshot refs
+		FOREACH new_planet_snapshot_id IN ARRAY NEW.planet_snapshot_ids LOOP
+			IF NOT EXISTS (SELECT 1 FROM planet_snapshots WHERE id = new_planet_snapshot_id) THEN
+				RAISE EXCEPTION 'snapshot at % has non-existent planet snapshot ID %', NEW.create_time, new_planet_snapshot_id;
+			END IF;
+		END LOOP;
+
+        RETURN NEW;
+    END;
+$validate_snapshot_refs$ LANGUAGE plpgsql;
+
+
+
+COMMENT ON COLUMN planets.war_id
+    IS 'The war this planet is part of';
+
+COMMENT ON COLUMN campaigns.war_id
+    IS 'The war this campaign is part of';
+
+COMMENT ON COLUMN events.war_id
+    IS 'The war this event is part of';
+
+COMMENT ON COLUMN assignments.war_id
+    IS 'The war this assignment was given in';
+
+COMMENT ON COLUMN planet_snapshots.war_id
+    IS 'The war of the captured planet';
+
+COMMENT ON COLUMN event_snapshots.war_id
+    IS 'The war of the captured event';
+
+COMMENT ON COLUMN assignment_snapshots.war_id
+    IS 'The war of the captured assignment';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS war_final_planets;
+
+
+DROP TABLE IF EXISTS war_summaries;
@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS war_summaries
+(
+    war_id integer NOT NULL REFERENCES wars,
+    close_time timestamp without time zone NOT NULL,
+    first_snapshot_time timestamp without time zone NOT NULL REFERENCES snapshots (create_time),
+    final_snapshot_time timestamp without time zone NOT NULL REFERENCES snapshots (create_time),
+    snapshot_count bigint NOT NULL CHECK (snapshot_count > 0),
+    final_statistics_id bigint NOT NULL REFERENCES snapshot_statistics,
+    peak_player_count numeric NOT NULL CHECK (peak_player_count >= 0),
+    CHECK (final_snapshot_time >= first_snapshot_time),
+    PRIMARY KEY (war_id)
+);
+
+COMMENT ON TABLE war_summaries
+    IS 'Summarizes a war season once it has ended, i.e. once the API reported a new war ID.';
+
+COMMENT ON COLUMN war_summaries.close_time
+    IS 'When the end of the war was detected';
+
+COMMENT ON COLUMN war_summaries.first_snapshot_time
+    IS 'Time of the first snapshot taken during the war';
+
+COMMENT ON COLUMN war_summaries.final_snapshot_time
+    IS 'Time of the last snapshot taken during the war';
+
+COMMENT ON COLUMN war_summaries.snapshot_count
+    IS 'Number of snapshots taken during the war';
+
+COMMENT ON COLUMN war_summaries.final_statistics_id
+    IS 'Global statistics at the end of the war';
+
+COMMENT ON COLUMN war_summaries.peak_player_count
+    IS 'Highest number of players present in any snapshot of the war';
+
+
+
+CREATE TABLE IF NOT EXISTS war_final_planets
+(
+    war_id integer NOT NULL REFERENCES wars,
+    planet_id integer NOT NULL,
+    owner text NOT NULL CHECK (owner <> ''),
+    PRIMARY KEY (war_id, planet_id),
+    FOREIGN KEY (war_id, planet_id) REFERENCES planets
+);
+
+COMMENT ON TABLE war_final_planets
+    IS 'Planet ownership at the end of a war season.';
+
+COMMENT ON COLUMN war_final_planets.owner
+    IS 'The faction controlling the planet when the war ended, as of its latest snapshot or its initial owner if it has never been captured in a snapshot';
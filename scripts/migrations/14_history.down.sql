@@ -0,0 +1,61 @@
+DROP TRIGGER IF EXISTS record_dispatch_history_insert ON dispatches;
+
+
+DROP TRIGGER IF EXISTS record_dispatch_history_update ON dispatches;
+
+
+DROP FUNCTION IF EXISTS record_dispatch_history;
+
+
+DROP TABLE IF EXISTS dispatch_history;
+
+
+DROP TRIGGER IF EXISTS record_assignment_history_insert ON assignments;
+
+
+DROP TRIGGER IF EXISTS record_assignment_history_update ON assignments;
+
+
+DROP FUNCTION IF EXISTS record_assignment_history;
+
+
+DROP TABLE IF EXISTS assignment_history;
+
+
+DROP TRIGGER IF EXISTS record_campaign_history_insert ON campaigns;
+
+
+DROP TRIGGER IF EXISTS record_campaign_history_update ON campaigns;
+
+
+DROP FUNCTION IF EXISTS record_campaign_history;
+
+
+DROP TABLE IF EXISTS campaign_history;
+
+
+DROP TRIGGER IF EXISTS record_biome_history_insert ON biomes;
+
+
+DROP TRIGGER IF EXISTS record_biome_history_update ON biomes;
+
+
+DROP FUNCTION IF EXISTS record_biome_history;
+
+
+DROP TABLE IF EXISTS biome_history;
+
+
+DROP TRIGGER IF EXISTS record_planet_history_insert ON planets;
+
+
+DROP TRIGGER IF EXISTS record_planet_history_update ON planets;
+
+
+DROP FUNCTION IF EXISTS record_planet_history;
+
+
+DROP TABLE IF EXISTS planet_history;
+
+
+DROP FUNCTION IF EXISTS history_time;
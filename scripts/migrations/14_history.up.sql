@@ -0,0 +1,302 @@
+-- The history tables are maintained by triggers on their source tables.
+-- Each row is a version of the source row which was valid in [valid_from, valid_to).
+-- The current version has valid_to = NULL.
+
+CREATE OR REPLACE FUNCTION history_time() RETURNS timestamp without time zone AS $history_time$
+    BEGIN
+        -- all changes within one transaction (i.e. one merge) share the same time
+        RETURN transaction_timestamp() AT TIME ZONE 'UTC';
+    END;
+$history_time$ LANGUAGE plpgsql STABLE;
+
+
+
+CREATE TABLE IF NOT EXISTS planet_history
+(
+    war_id integer NOT NULL,
+    id integer NOT NULL,
+    name text NOT NULL,
+    sector text NOT NULL,
+    position double precision[2] NOT NULL,
+    waypoint_ids integer[] NOT NULL,
+    disabled boolean NOT NULL,
+    biome_name text NOT NULL,
+    hazard_names text[] NOT NULL,
+    max_health bigint NOT NULL,
+    initial_owner text NOT NULL,
+    valid_from timestamp without time zone NOT NULL,
+    valid_to timestamp without time zone,
+    CHECK (valid_to > valid_from),
+    PRIMARY KEY (war_id, id, valid_from),
+    FOREIGN KEY (war_id, id) REFERENCES planets ON DELETE CASCADE
+);
+
+CREATE OR REPLACE FUNCTION record_planet_history() RETURNS TRIGGER AS $record_planet_history$
+    BEGIN
+        -- later changes within the same transaction replace earlier ones
+        DELETE FROM planet_history
+        WHERE war_id = NEW.war_id AND id = NEW.id AND valid_from = history_time();
+
+        UPDATE planet_history SET valid_to = history_time()
+        WHERE war_id = NEW.war_id AND id = NEW.id AND valid_to IS NULL;
+
+        INSERT INTO planet_history (
+            war_id, id, name, sector, position, waypoint_ids, disabled, biome_name, hazard_names, max_health, initial_owner, valid_from
+        ) VALUES (
+            NEW.war_id, NEW.id, NEW.name, NEW.sector, NEW.position, NEW.waypoint_ids, NEW.disabled, NEW.biome_name, NEW.hazard_names, NEW.max_health, NEW.initial_owner, history_time()
+        );
+        RETURN NULL;
+    END;
+$record_planet_history$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_planet_history_insert AFTER INSERT ON planets
+    FOR EACH ROW EXECUTE FUNCTION record_planet_history();
+
+CREATE TRIGGER record_planet_history_update AFTER UPDATE ON planets
+    FOR EACH ROW
+    WHEN ((OLD.name, OLD.sector, OLD.position, OLD.waypoint_ids, OLD.disabled, OLD.biome_name, OLD.hazard_names, OLD.max_health, OLD.initial_owner)
+        IS DISTINCT FROM (NEW.name, NEW.sector, NEW.position, NEW.waypoint_ids, NEW.disabled, NEW.biome_name, NEW.hazard_names, NEW.max_health, NEW.initial_owner))
+    EXECUTE FUNCTION record_planet_history();
+
+INSERT INTO planet_history (
+    war_id, id, name, sector, position, waypoint_ids, disabled, biome_name, hazard_names, max_health, initial_owner, valid_from
+)
+SELECT war_id, id, name, sector, position, waypoint_ids, disabled, biome_name, hazard_names, max_health, initial_owner, history_time()
+FROM planets;
+
+COMMENT ON TABLE planet_history
+    IS 'Contains all versions of the planets, e.g. previous names';
+
+COMMENT ON COLUMN planet_history.valid_from
+    IS 'When this version was first merged';
+
+COMMENT ON COLUMN planet_history.valid_to
+    IS 'When this version was replaced, NULL for the current version';
+
+
+
+CREATE TABLE IF NOT EXISTS biome_history
+(
+    name text NOT NULL,
+    description text NOT NULL,
+    valid_from timestamp without time zone NOT NULL,
+    valid_to timestamp without time zone,
+    CHECK (valid_to > valid_from),
+    PRIMARY KEY (name, valid_from),
+    FOREIGN KEY (name) REFERENCES biomes ON DELETE CASCADE
+);
+
+CREATE OR REPLACE FUNCTION record_biome_history() RETURNS TRIGGER AS $record_biome_history$
+    BEGIN
+        -- later changes within the same transaction replace earlier ones
+        DELETE FROM biome_history
+        WHERE name = NEW.name AND valid_from = history_time();
+
+        UPDATE biome_history SET valid_to = history_time()
+        WHERE name = NEW.name AND valid_to IS NULL;
+
+        INSERT INTO biome_history (
+            name, description, valid_from
+        ) VALUES (
+            NEW.name, NEW.description, history_time()
+        );
+        RETURN NULL;
+    END;
+$record_biome_history$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_biome_history_insert AFTER INSERT ON biomes
+    FOR EACH ROW EXECUTE FUNCTION record_biome_history();
+
+CREATE TRIGGER record_biome_history_update AFTER UPDATE ON biomes
+    FOR EACH ROW
+    WHEN (OLD.description IS DISTINCT FROM NEW.description)
+    EXECUTE FUNCTION record_biome_history();
+
+INSERT INTO biome_history (
+    name, description, valid_from
+)
+SELECT name, description, history_time()
+FROM biomes;
+
+COMMENT ON TABLE biome_history
+    IS 'Contains all versions of the biomes, e.g. previous descriptions';
+
+COMMENT ON COLUMN biome_history.valid_from
+    IS 'When this version was first merged';
+
+COMMENT ON COLUMN biome_history.valid_to
+    IS 'When this version was replaced, NULL for the current version';
+
+
+
+CREATE TABLE IF NOT EXISTS campaign_history
+(
+    war_id integer NOT NULL,
+    id integer NOT NULL,
+    type integer NOT NULL,
+    count numeric NOT NULL,
+    valid_from timestamp without time zone NOT NULL,
+    valid_to timestamp without time zone,
+    CHECK (valid_to > valid_from),
+    PRIMARY KEY (war_id, id, valid_from),
+    FOREIGN KEY (war_id, id) REFERENCES campaigns ON DELETE CASCADE
+);
+
+CREATE OR REPLACE FUNCTION record_campaign_history() RETURNS TRIGGER AS $record_campaign_history$
+    BEGIN
+        -- later changes within the same transaction replace earlier ones
+        DELETE FROM campaign_history
+        WHERE war_id = NEW.war_id AND id = NEW.id AND valid_from = history_time();
+
+        UPDATE campaign_history SET valid_to = history_time()
+        WHERE war_id = NEW.war_id AND id = NEW.id AND valid_to IS NULL;
+
+        INSERT INTO campaign_history (
+            war_id, id, type, count, valid_from
+        ) VALUES (
+            NEW.war_id, NEW.id, NEW.type, NEW.count, history_time()
+        );
+        RETURN NULL;
+    END;
+$record_campaign_history$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_campaign_history_insert AFTER INSERT ON campaigns
+    FOR EACH ROW EXECUTE FUNCTION record_campaign_history();
+
+CREATE TRIGGER record_campaign_history_update AFTER UPDATE ON campaigns
+    FOR EACH ROW
+    WHEN ((OLD.type, OLD.count) IS DISTINCT FROM (NEW.type, NEW.count))
+    EXECUTE FUNCTION record_campaign_history();
+
+INSERT INTO campaign_history (
+    war_id, id, type, count, valid_from
+)
+SELECT war_id, id, type, count, history_time()
+FROM campaigns;
+
+COMMENT ON TABLE campaign_history
+    IS 'Contains all versions of the campaigns';
+
+COMMENT ON COLUMN campaign_history.valid_from
+    IS 'When this version was first merged';
+
+COMMENT ON COLUMN campaign_history.valid_to
+    IS 'When this version was replaced, NULL for the current version';
+
+
+
+CREATE TABLE IF NOT EXISTS assignment_history
+(
+    war_id integer NOT NULL,
+    id bigint NOT NULL,
+    title text NOT NULL,
+    briefing text NOT NULL,
+    description text NOT NULL,
+    expiration timestamp without time zone NOT NULL,
+    reward_type integer NOT NULL,
+    reward_amount numeric NOT NULL,
+    valid_from timestamp without time zone NOT NULL,
+    valid_to timestamp without time zone,
+    CHECK (valid_to > valid_from),
+    PRIMARY KEY (war_id, id, valid_from),
+    FOREIGN KEY (war_id, id) REFERENCES assignments ON DELETE CASCADE
+);
+
+CREATE OR REPLACE FUNCTION record_assignment_history() RETURNS TRIGGER AS $record_assignment_history$
+    BEGIN
+        -- later changes within the same transaction replace earlier ones
+        DELETE FROM assignment_history
+        WHERE war_id = NEW.war_id AND id = NEW.id AND valid_from = history_time();
+
+        UPDATE assignment_history SET valid_to = history_time()
+        WHERE war_id = NEW.war_id AND id = NEW.id AND valid_to IS NULL;
+
+        INSERT INTO assignment_history (
+            war_id, id, title, briefing, description, expiration, reward_type, reward_amount, valid_from
+        ) VALUES (
+            NEW.war_id, NEW.id, NEW.title, NEW.briefing, NEW.description, NEW.expiration, NEW.reward_type, NEW.reward_amount, history_time()
+        );
+        RETURN NULL;
+    END;
+$record_assignment_history$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_assignment_history_insert AFTER INSERT ON assignments
+    FOR EACH ROW EXECUTE FUNCTION record_assignment_history();
+
+-- task IDs change on every merge since tasks are re-inserted, so they are not part of the history
+CREATE TRIGGER record_assignment_history_update AFTER UPDATE ON assignments
+    FOR EACH ROW
+    WHEN ((OLD.title, OLD.briefing, OLD.description, OLD.expiration, OLD.reward_type, OLD.reward_amount)
+        IS DISTINCT FROM (NEW.title, NEW.briefing, NEW.description, NEW.expiration, NEW.reward_type, NEW.reward_amount))
+    EXECUTE FUNCTION record_assignment_history();
+
+INSERT INTO assignment_history (
+    war_id, id, title, briefing, description, expiration, reward_type, reward_amount, valid_from
+)
+SELECT war_id, id, title, briefing, description, expiration, reward_type, reward_amount, history_time()
+FROM assignments;
+
+COMMENT ON TABLE assignment_history
+    IS 'Contains all versions of the assignments, e.g. previous briefings';
+
+COMMENT ON COLUMN assignment_history.valid_from
+    IS 'When this version was first merged';
+
+COMMENT ON COLUMN assignment_history.valid_to
+    IS 'When this version was replaced, NULL for the current version';
+
+
+
+CREATE TABLE IF NOT EXISTS dispatch_history
+(
+    id integer NOT NULL,
+    create_time timestamp without time zone NOT NULL,
+    type integer NOT NULL,
+    message text NOT NULL,
+    valid_from timestamp without time zone NOT NULL,
+    valid_to timestamp without time zone,
+    CHECK (valid_to > valid_from),
+    PRIMARY KEY (id, valid_from),
+    FOREIGN KEY (id) REFERENCES dispatches ON DELETE CASCADE
+);
+
+CREATE OR REPLACE FUNCTION record_dispatch_history() RETURNS TRIGGER AS $record_dispatch_history$
+    BEGIN
+        -- later changes within the same transaction replace earlier ones
+        DELETE FROM dispatch_history
+        WHERE id = NEW.id AND valid_from = history_time();
+
+        UPDATE dispatch_history SET valid_to = history_time()
+        WHERE id = NEW.id AND valid_to IS NULL;
+
+        INSERT INTO dispatch_history (
+            id, create_time, type, message, valid_from
+        ) VALUES (
+            NEW.id, NEW.create_time, NEW.type, NEW.message, history_time()
+        );
+        RETURN NULL;
+    END;
+$record_dispatch_history$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_dispatch_history_insert AFTER INSERT ON dispatches
+    FOR EACH ROW EXECUTE FUNCTION record_dispatch_history();
+
+CREATE TRIGGER record_dispatch_history_update AFTER UPDATE ON dispatches
+    FOR EACH ROW
+    WHEN ((OLD.create_time, OLD.type, OLD.message) IS DISTINCT FROM (NEW.create_time, NEW.type, NEW.message))
+    EXECUTE FUNCTION record_dispatch_history();
+
+INSERT INTO dispatch_history (
+    id, create_time, type, message, valid_from
+)
+SELECT id, create_time, type, message, history_time()
+FROM dispatches;
+
+COMMENT ON TABLE dispatch_history
+    IS 'Contains all versions of the dispatches, e.g. previous messages';
+
+COMMENT ON COLUMN dispatch_history.valid_from
+    IS 'When this version was first merged';
+
+COMMENT ON COLUMN dispatch_history.valid_to
+    IS 'When this version was replaced, NULL for the current version';
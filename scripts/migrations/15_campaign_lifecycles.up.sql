@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS campaign_lifecycles
+(
+    war_id integer NOT NULL,
+    campaign_id integer NOT NULL,
+    planet_id integer,
+    first_seen timestamp without time zone NOT NULL,
+    last_seen timestamp without time zone NOT NULL,
+    ended boolean NOT NULL,
+    outcome text CHECK (outcome IN ('liberated', 'lost')),
+    CHECK (last_seen >= first_seen),
+    CHECK (ended OR outcome IS NULL),
+    PRIMARY KEY (war_id, campaign_id),
+    FOREIGN KEY (war_id, campaign_id) REFERENCES campaigns,
+    FOREIGN KEY (war_id, planet_id) REFERENCES planets
+);
+
+COMMENT ON TABLE campaign_lifecycles
+    IS 'Tracks when campaigns started and ended and how they were resolved, maintained after each snapshot';
+
+COMMENT ON COLUMN campaign_lifecycles.planet_id
+    IS 'The planet on which the campaign is fought';
+
+COMMENT ON COLUMN campaign_lifecycles.first_seen
+    IS 'Time of the first snapshot containing the campaign';
+
+COMMENT ON COLUMN campaign_lifecycles.last_seen
+    IS 'Time of the latest snapshot containing the campaign';
+
+COMMENT ON COLUMN campaign_lifecycles.ended
+    IS 'Whether the campaign is no longer active, i.e. missing from the latest snapshot';
+
+COMMENT ON COLUMN campaign_lifecycles.outcome
+    IS 'Whether the planet was liberated (or defended) or lost, NULL until the planet has been captured in a snapshot after the campaign ended';
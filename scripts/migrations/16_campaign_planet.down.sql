@@ -0,0 +1,32 @@
+DROP TRIGGER IF EXISTS record_campaign_history_update ON campaigns;
+
+
+CREATE OR REPLACE FUNCTION record_campaign_history() RETURNS TRIGGER AS $record_campaign_history$
+    BEGIN
+        -- later changes within the same transaction replace earlier ones
+        DELETE FROM campaign_history
+        WHERE war_id = NEW.war_id AND id = NEW.id AND valid_from = history_time();
+
+        UPDATE campaign_history SET valid_to = history_time()
+        WHERE war_id = NEW.war_id AND id = NEW.id AND valid_to IS NULL;
+
+        INSERT INTO campaign_history (
+            war_id, id, type, count, valid_from
+        ) VALUES (
+            NEW.war_id, NEW.id, NEW.type, NEW.count, history_time()
+        );
+        RETURN NULL;
+    END;
+$record_campaign_history$ LANGUAGE plpgsql;
+
+
+CREATE TRIGGER record_campaign_history_update AFTER UPDATE ON campaigns
+    FOR EACH ROW
+    WHEN ((OLD.type, OLD.count) IS DISTINCT FROM (NEW.type, NEW.count))
+    EXECUTE FUNCTION record_campaign_history();
+
+
+ALTER TABLE campaign_history DROP COLUMN IF EXISTS planet_id;
+
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS planet_id;
@@ -0,0 +1,68 @@
+ALTER TABLE campaigns
+    ADD COLUMN planet_id integer;
+
+ALTER TABLE campaigns
+    ADD CONSTRAINT campaigns_planet_fkey FOREIGN KEY (war_id, planet_id) REFERENCES planets;
+
+COMMENT ON COLUMN campaigns.planet_id
+    IS 'The planet on which this campaign is fought';
+
+-- backfill existing campaigns from the planets their events have been captured on
+UPDATE campaigns
+SET planet_id = campaign_planets.planet_id
+FROM (
+    SELECT DISTINCT ON (events.war_id, events.campaign_id)
+        events.war_id,
+        events.campaign_id,
+        planet_snapshots.planet_id
+    FROM events
+    JOIN event_snapshots
+        ON event_snapshots.war_id = events.war_id AND event_snapshots.event_id = events.id
+    JOIN planet_snapshots
+        ON planet_snapshots.event_snapshot_id = event_snapshots.id
+    ORDER BY events.war_id, events.campaign_id, planet_snapshots.id DESC
+) AS campaign_planets
+WHERE campaigns.war_id = campaign_planets.war_id AND campaigns.id = campaign_planets.campaign_id;
+
+-- campaigns without events have been tracked with their planet since the lifecycles were introduced
+UPDATE campaigns
+SET planet_id = campaign_lifecycles.planet_id
+FROM campaign_lifecycles
+WHERE campaigns.planet_id IS NULL
+    AND campaigns.war_id = campaign_lifecycles.war_id AND campaigns.id = campaign_lifecycles.campaign_id
+    AND campaign_lifecycles.planet_id IS NOT NULL;
+
+
+
+ALTER TABLE campaign_history
+    ADD COLUMN planet_id integer;
+
+UPDATE campaign_history
+SET planet_id = campaigns.planet_id
+FROM campaigns
+WHERE campaign_history.war_id = campaigns.war_id AND campaign_history.id = campaigns.id AND campaign_history.valid_to IS NULL;
+
+CREATE OR REPLACE FUNCTION record_campaign_history() RETURNS TRIGGER AS $record_campaign_history$
+    BEGIN
+        -- later changes within the same transaction replace earlier ones
+        DELETE FROM campaign_history
+        WHERE war_id = NEW.war_id AND id = NEW.id AND valid_from = history_time();
+
+        UPDATE campaign_history SET valid_to = history_time()
+        WHERE war_id = NEW.war_id AND id = NEW.id AND valid_to IS NULL;
+
+        INSERT INTO campaign_history (
+            war_id, id, type, count, planet_id, valid_from
+        ) VALUES (
+            NEW.war_id, NEW.id, NEW.type, NEW.count, NEW.planet_id, history_time()
+        );
+        RETURN NULL;
+    END;
+$record_campaign_history$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS record_campaign_history_update ON campaigns;
+
+CREATE TRIGGER record_campaign_history_update AFTER UPDATE ON campaigns
+    FOR EACH ROW
+    WHEN ((OLD.type, OLD.count, OLD.planet_id) IS DISTINCT FROM (NEW.type, NEW.count, NEW.planet_id))
+    EXECUTE FUNCTION record_campaign_history();
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS sync_runs
+(
+    id uuid NOT NULL,
+    job text NOT NULL,
+    start_time timestamp without time zone NOT NULL,
+    end_time timestamp without time zone NOT NULL,
+    outcome text NOT NULL CHECK (outcome IN ('success', 'failure')),
+    endpoints jsonb NOT NULL,
+    merge_counts jsonb NOT NULL,
+    error text,
+    snapshot_create_time timestamp without time zone,
+    CHECK (end_time >= start_time),
+    CHECK ((outcome = 'failure') = (error IS NOT NULL)),
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS sync_runs_start_time_idx ON sync_runs (start_time);
+
+COMMENT ON TABLE sync_runs
+    IS 'Ledger of all sync job runs, including failed ones';
+
+COMMENT ON COLUMN sync_runs.id
+    IS 'Run ID, also attached to all log records of the run';
+
+COMMENT ON COLUMN sync_runs.job
+    IS 'Name of the sync job, e.g. "snapshot"';
+
+COMMENT ON COLUMN sync_runs.outcome
+    IS 'Whether the run succeeded or failed';
+
+COMMENT ON COLUMN sync_runs.endpoints
+    IS 'Array with one object per queried API endpoint, containing its status (ok, unchanged or failed), latency in milliseconds, upstream and error';
+
+COMMENT ON COLUMN sync_runs.merge_counts
+    IS 'Object with inserted, updated and unchanged rows per merged table, keyed by table name';
+
+COMMENT ON COLUMN sync_runs.error
+    IS 'Error which made the run fail, NULL if it succeeded';
+
+COMMENT ON COLUMN sync_runs.snapshot_create_time
+    IS 'Time of the snapshot created by the run, NULL if no snapshot was created';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS planet_snapshots_war_planet_idx;
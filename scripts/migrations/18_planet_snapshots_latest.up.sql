@@ -0,0 +1,2 @@
+-- supports finding the latest snapshot of each planet of a war
+CREATE INDEX IF NOT EXISTS planet_snapshots_war_planet_idx ON planet_snapshots (war_id, planet_id, id DESC);
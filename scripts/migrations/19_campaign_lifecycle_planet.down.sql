@@ -0,0 +1,13 @@
+ALTER TABLE campaign_lifecycles
+    ADD COLUMN planet_id integer;
+
+ALTER TABLE campaign_lifecycles
+    ADD FOREIGN KEY (war_id, planet_id) REFERENCES planets;
+
+COMMENT ON COLUMN campaign_lifecycles.planet_id
+    IS 'The planet on which the campaign is fought';
+
+UPDATE campaign_lifecycles
+SET planet_id = campaigns.planet_id
+FROM campaigns
+WHERE campaign_lifecycles.war_id = campaigns.war_id AND campaign_lifecycles.campaign_id = campaigns.id;
@@ -0,0 +1,11 @@
+-- the planet of a campaign is stored with the campaign, lifecycles join through it
+
+-- keep planets only known from lifecycles
+UPDATE campaigns
+SET planet_id = campaign_lifecycles.planet_id
+FROM campaign_lifecycles
+WHERE campaigns.planet_id IS NULL
+    AND campaigns.war_id = campaign_lifecycles.war_id AND campaigns.id = campaign_lifecycles.campaign_id
+    AND campaign_lifecycles.planet_id IS NOT NULL;
+
+ALTER TABLE campaign_lifecycles DROP COLUMN IF EXISTS planet_id;
@@ -1,19 +1,27 @@
 -- name: GetAssignment :one
 SELECT id FROM assignments
-WHERE id = $1;
+WHERE war_id = $1 AND id = $2;
 
--- name: AssignmentExists :one
-SELECT EXISTS(SELECT * FROM assignments WHERE id = $1);
-
--- name: MergeAssignment :execrows
+-- name: MergeAssignment :one
 INSERT INTO assignments (
-    id, title, briefing, description, expiration, task_ids, reward_type, reward_amount
+    id, title, briefing, description, expiration, task_ids, reward_type, reward_amount, war_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-ON CONFLICT (id) DO UPDATE
+ON CONFLICT (war_id, id) DO UPDATE
     SET title=$2, briefing=$3, description=$4, expiration=$5, task_ids=$6, reward_type=$7, reward_amount=$8
-;
+WHERE (
+    assignments.title, assignments.briefing, assignments.description, assignments.expiration,
+    assignments.task_ids, assignments.reward_type, assignments.reward_amount
+) IS DISTINCT FROM ($2, $3, $4, $5, $6, $7, $8)
+RETURNING (xmax = 0) AS inserted;
+
+-- name: GetAssignmentTasks :many
+SELECT assignment_tasks.* FROM assignments
+JOIN assignment_tasks
+    ON assignment_tasks.id = ANY(task_ids)
+WHERE assignments.war_id = sqlc.arg(war_id) AND assignments.id = sqlc.arg(assignment_id)
+ORDER BY array_position(task_ids, assignment_tasks.id);
 
 -- name: InsertAssignmentTask :one
 INSERT INTO assignment_tasks (
@@ -30,5 +38,5 @@ WHERE id IN (
     FROM assignments
     JOIN assignment_tasks
         ON assignment_tasks.id = ANY(task_ids)
-    WHERE assignments.id = sqlc.arg(assignment_id)
+    WHERE assignments.war_id = sqlc.arg(war_id) AND assignments.id = sqlc.arg(assignment_id)
 );
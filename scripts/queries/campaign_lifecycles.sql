@@ -0,0 +1,34 @@
+-- name: GetCampaignLifecycle :one
+SELECT * FROM campaign_lifecycles
+WHERE war_id = $1 AND campaign_id = $2;
+
+-- name: GetCampaignLifecycles :many
+SELECT campaign_lifecycles.*, campaigns.planet_id FROM campaign_lifecycles
+JOIN campaigns
+    ON campaigns.war_id = campaign_lifecycles.war_id AND campaigns.id = campaign_lifecycles.campaign_id
+WHERE campaign_lifecycles.war_id = $1
+ORDER BY first_seen, campaign_id;
+
+-- name: MergeCampaignLifecycle :one
+INSERT INTO campaign_lifecycles (
+    war_id, campaign_id, first_seen, last_seen, ended, outcome
+) VALUES (
+    sqlc.arg(war_id), sqlc.arg(campaign_id), sqlc.arg(seen), sqlc.arg(seen), FALSE, NULL
+)
+ON CONFLICT (war_id, campaign_id) DO UPDATE
+    SET last_seen=EXCLUDED.last_seen, ended=FALSE, outcome=NULL
+WHERE campaign_lifecycles.last_seen < EXCLUDED.last_seen
+RETURNING (xmax = 0) AS inserted;
+
+-- name: EndCampaignLifecycles :execrows
+UPDATE campaign_lifecycles
+SET ended = TRUE
+WHERE war_id = sqlc.arg(war_id) AND NOT ended AND NOT (campaign_id = ANY(sqlc.arg(active_ids)::integer[]));
+
+-- name: ResolveCampaignOutcomes :execrows
+UPDATE campaign_lifecycles
+SET outcome = CASE WHEN captured.owner = 'Humans' THEN 'liberated' ELSE 'lost' END
+FROM campaigns, unnest(sqlc.arg(planet_ids)::integer[], sqlc.arg(owners)::text[]) AS captured(planet_id, owner)
+WHERE campaign_lifecycles.war_id = sqlc.arg(war_id) AND campaign_lifecycles.ended AND campaign_lifecycles.outcome IS NULL
+    AND campaigns.war_id = campaign_lifecycles.war_id AND campaigns.id = campaign_lifecycles.campaign_id
+    AND campaigns.planet_id = captured.planet_id;
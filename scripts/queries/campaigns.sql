@@ -1,18 +1,16 @@
 -- name: GetCampaign :one
 SELECT id FROM campaigns
-WHERE id = $1;
+WHERE war_id = $1 AND id = $2;
 
--- name: CampaignExists :one
-SELECT EXISTS(SELECT * FROM campaigns WHERE id = $1);
-
--- name: MergeCampaign :execrows
+-- name: MergeCampaign :one
 INSERT INTO campaigns (
-    id, type, count
+    id, type, count, war_id, planet_id
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4, $5
 )
-ON CONFLICT (id) DO UPDATE
-    SET type=$2, count=$3
+ON CONFLICT (war_id, id) DO UPDATE
+    SET type=$2, count=$3, planet_id=COALESCE($5, campaigns.planet_id)
 WHERE FALSE IN (
-    EXCLUDED.type=$2, EXCLUDED.count=$3
-);
+    campaigns.type=$2, campaigns.count=$3, campaigns.planet_id IS NOT DISTINCT FROM COALESCE($5, campaigns.planet_id)
+)
+RETURNING (xmax = 0) AS inserted;
@@ -2,10 +2,7 @@
 SELECT id FROM dispatches
 WHERE id = $1;
 
--- name: DispatchExists :one
-SELECT EXISTS(SELECT * FROM dispatches WHERE id = $1);
-
--- name: MergeDispatch :execrows
+-- name: MergeDispatch :one
 INSERT INTO dispatches (
     id, create_time, type, message
 ) VALUES (
@@ -14,5 +11,6 @@ INSERT INTO dispatches (
 ON CONFLICT (id) DO UPDATE
     SET create_time=$2, type=$3, message=$4
 WHERE FALSE IN (
-    EXCLUDED.create_time=$2, EXCLUDED.type=$3, EXCLUDED.message=$4
-);
+    dispatches.create_time=$2, dispatches.type=$3, dispatches.message=$4
+)
+RETURNING (xmax = 0) AS inserted;
@@ -1,18 +1,16 @@
 -- name: GetEvent :one
 SELECT id FROM events
-WHERE id = $1;
+WHERE war_id = $1 AND id = $2;
 
--- name: EventExists :one
-SELECT EXISTS(SELECT * FROM events WHERE id = $1);
-
--- name: MergeEvent :execrows
+-- name: MergeEvent :one
 INSERT INTO events (
-    id, campaign_id, type, faction, max_health, start_time, end_time
+    id, campaign_id, type, faction, max_health, start_time, end_time, war_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
-ON CONFLICT (id) DO UPDATE
+ON CONFLICT (war_id, id) DO UPDATE
     SET campaign_id=$2, type=$3, faction=$4, max_health=$5, start_time=$6, end_time=$7
 WHERE FALSE IN (
-    EXCLUDED.campaign_id=$2, EXCLUDED.type=$3, EXCLUDED.faction=$4, EXCLUDED.max_health=$5, EXCLUDED.start_time=$6, EXCLUDED.end_time=$7
-);
+    events.campaign_id=$2, events.type=$3, events.faction=$4, events.max_health=$5, events.start_time=$6, events.end_time=$7
+)
+RETURNING (xmax = 0) AS inserted;
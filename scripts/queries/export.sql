@@ -0,0 +1,49 @@
+-- name: ExportPlanetSnapshots :many
+SELECT
+    s.create_time, ps.war_id, ps.planet_id, p.name AS planet_name, p.sector, ps.current_owner, ps.health, p.max_health, ps.regen_per_second,
+    es.event_id, es.health AS event_health,
+    st.missions_won::bigint AS missions_won, st.missions_lost::bigint AS missions_lost, st.mission_time::bigint AS mission_time,
+    st.terminid_kills::bigint AS terminid_kills, st.automaton_kills::bigint AS automaton_kills, st.illuminate_kills::bigint AS illuminate_kills,
+    st.bullets_fired::bigint AS bullets_fired, st.bullets_hit::bigint AS bullets_hit, st.time_played::bigint AS time_played,
+    st.deaths::bigint AS deaths, st.revives::bigint AS revives, st.friendlies::bigint AS friendlies, st.player_count::bigint AS player_count
+FROM snapshots s
+JOIN planet_snapshots ps ON ps.id = ANY(s.planet_snapshot_ids)
+JOIN planets p ON p.war_id = ps.war_id AND p.id = ps.planet_id
+JOIN snapshot_statistics st ON st.id = ps.statistics_id
+LEFT JOIN event_snapshots es ON es.id = ps.event_snapshot_id
+WHERE s.create_time > sqlc.arg(after) AND s.create_time <= sqlc.arg(until)
+ORDER BY s.create_time, ps.planet_id;
+
+-- name: ExportWarStats :many
+SELECT
+    s.create_time, ws.war_id, ws.impact_multiplier,
+    st.missions_won::bigint AS missions_won, st.missions_lost::bigint AS missions_lost, st.mission_time::bigint AS mission_time,
+    st.terminid_kills::bigint AS terminid_kills, st.automaton_kills::bigint AS automaton_kills, st.illuminate_kills::bigint AS illuminate_kills,
+    st.bullets_fired::bigint AS bullets_fired, st.bullets_hit::bigint AS bullets_hit, st.time_played::bigint AS time_played,
+    st.deaths::bigint AS deaths, st.revives::bigint AS revives, st.friendlies::bigint AS friendlies, st.player_count::bigint AS player_count
+FROM snapshots s
+JOIN war_snapshots ws ON ws.id = s.war_snapshot_id
+JOIN snapshot_statistics st ON st.id = s.statistics_id
+WHERE s.create_time > sqlc.arg(after) AND s.create_time <= sqlc.arg(until)
+ORDER BY s.create_time;
+
+-- name: ExportAssignmentProgress :many
+SELECT
+    s.create_time, asn.war_id, asn.assignment_id, a.title, (p.ordinality - 1)::integer AS task_index, t.task_type, p.progress::bigint AS progress
+FROM snapshots s
+JOIN assignment_snapshots asn ON asn.id = ANY(s.assignment_snapshot_ids)
+JOIN assignments a ON a.war_id = asn.war_id AND a.id = asn.assignment_id
+CROSS JOIN LATERAL unnest(asn.progress) WITH ORDINALITY AS p(progress, ordinality)
+LEFT JOIN assignment_tasks t ON t.id = a.task_ids[p.ordinality]
+WHERE s.create_time > sqlc.arg(after) AND s.create_time <= sqlc.arg(until)
+ORDER BY s.create_time, asn.assignment_id, p.ordinality;
+
+-- name: ExportEvents :many
+SELECT
+    s.create_time, es.war_id, es.event_id, ps.planet_id, e.campaign_id, e.type AS event_type, e.faction, es.health, e.max_health, e.start_time, e.end_time
+FROM snapshots s
+JOIN planet_snapshots ps ON ps.id = ANY(s.planet_snapshot_ids)
+JOIN event_snapshots es ON es.id = ps.event_snapshot_id
+JOIN events e ON e.war_id = es.war_id AND e.id = es.event_id
+WHERE s.create_time > sqlc.arg(after) AND s.create_time <= sqlc.arg(until)
+ORDER BY s.create_time, es.event_id;
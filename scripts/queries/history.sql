@@ -0,0 +1,44 @@
+-- name: GetPlanetAt :one
+SELECT * FROM planet_history
+WHERE war_id = sqlc.arg(war_id) AND id = sqlc.arg(id) AND valid_from <= sqlc.arg(at) AND (valid_to IS NULL OR valid_to > sqlc.arg(at));
+
+-- name: GetPlanetHistory :many
+SELECT * FROM planet_history
+WHERE war_id = sqlc.arg(war_id) AND id = sqlc.arg(id)
+ORDER BY valid_from;
+
+-- name: GetBiomeAt :one
+SELECT * FROM biome_history
+WHERE name = sqlc.arg(name) AND valid_from <= sqlc.arg(at) AND (valid_to IS NULL OR valid_to > sqlc.arg(at));
+
+-- name: GetBiomeHistory :many
+SELECT * FROM biome_history
+WHERE name = sqlc.arg(name)
+ORDER BY valid_from;
+
+-- name: GetCampaignAt :one
+SELECT * FROM campaign_history
+WHERE war_id = sqlc.arg(war_id) AND id = sqlc.arg(id) AND valid_from <= sqlc.arg(at) AND (valid_to IS NULL OR valid_to > sqlc.arg(at));
+
+-- name: GetCampaignHistory :many
+SELECT * FROM campaign_history
+WHERE war_id = sqlc.arg(war_id) AND id = sqlc.arg(id)
+ORDER BY valid_from;
+
+-- name: GetAssignmentAt :one
+SELECT * FROM assignment_history
+WHERE war_id = sqlc.arg(war_id) AND id = sqlc.arg(id) AND valid_from <= sqlc.arg(at) AND (valid_to IS NULL OR valid_to > sqlc.arg(at));
+
+-- name: GetAssignmentHistory :many
+SELECT * FROM assignment_history
+WHERE war_id = sqlc.arg(war_id) AND id = sqlc.arg(id)
+ORDER BY valid_from;
+
+-- name: GetDispatchAt :one
+SELECT * FROM dispatch_history
+WHERE id = sqlc.arg(id) AND valid_from <= sqlc.arg(at) AND (valid_to IS NULL OR valid_to > sqlc.arg(at));
+
+-- name: GetDispatchHistory :many
+SELECT * FROM dispatch_history
+WHERE id = sqlc.arg(id)
+ORDER BY valid_from;
@@ -1,30 +1,25 @@
 -- name: GetPlanet :one
 SELECT id FROM planets
-WHERE id = $1;
+WHERE war_id = $1 AND id = $2;
 
--- name: PlanetExists :one
-SELECT EXISTS(SELECT * FROM planets WHERE id = $1);
-
--- name: MergePlanet :execrows
+-- name: MergePlanet :one
 INSERT INTO planets (
-    id, name, sector, position, waypoint_ids, disabled, biome_name, hazard_names, max_health, initial_owner
+    id, name, sector, position, waypoint_ids, disabled, biome_name, hazard_names, max_health, initial_owner, war_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
-ON CONFLICT (id) DO UPDATE
+ON CONFLICT (war_id, id) DO UPDATE
     SET name=$2, sector=$3, position=$4, waypoint_ids=$5, disabled=$6, biome_name=$7, hazard_names=$8, max_health=$9, initial_owner=$10
 WHERE FALSE IN (
-    EXCLUDED.name=$2, EXCLUDED.sector=$3, EXCLUDED.position=$4,EXCLUDED. waypoint_ids=$5, EXCLUDED.disabled=$6, EXCLUDED.biome_name=$7, EXCLUDED.hazard_names=$8, EXCLUDED.max_health=$9, EXCLUDED.initial_owner=$10
-);
+    planets.name=$2, planets.sector=$3, planets.position=$4, planets.waypoint_ids=$5, planets.disabled=$6, planets.biome_name=$7, planets.hazard_names=$8, planets.max_health=$9, planets.initial_owner=$10
+)
+RETURNING (xmax = 0) AS inserted;
 
 -- name: GetBiome :one
 SELECT name FROM biomes
 WHERE name = $1;
 
--- name: BiomeExists :one
-SELECT EXISTS(SELECT * FROM biomes WHERE name = $1);
-
--- name: MergeBiome :execrows
+-- name: MergeBiome :one
 INSERT INTO biomes (
     name, description
 ) VALUES (
@@ -33,17 +28,15 @@ INSERT INTO biomes (
 ON CONFLICT (name) DO UPDATE
     SET description=$2
 WHERE FALSE IN (
-    EXCLUDED.description=$2
-);
+    biomes.description=$2
+)
+RETURNING (xmax = 0) AS inserted;
 
 -- name: GetHazard :one
 SELECT name FROM hazards
 WHERE name = $1;
 
--- name: HazardExists :one
-SELECT EXISTS(SELECT * FROM hazards WHERE name = $1);
-
--- name: MergeHazard :execrows
+-- name: MergeHazard :one
 INSERT INTO hazards (
     name, description
 ) VALUES (
@@ -52,6 +45,7 @@ INSERT INTO hazards (
 ON CONFLICT (name) DO UPDATE
     SET description=$2
 WHERE FALSE IN (
-    EXCLUDED.description=$2
-);
+    hazards.description=$2
+)
+RETURNING (xmax = 0) AS inserted;
 
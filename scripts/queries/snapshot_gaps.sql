@@ -0,0 +1,21 @@
+-- name: GetSnapshotGaps :many
+SELECT * FROM snapshot_gaps
+WHERE start_time >= $1
+ORDER BY start_time;
+
+-- name: MergeSnapshotGap :one
+INSERT INTO snapshot_gaps (
+    start_time, end_time, missed_runs
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (start_time) DO UPDATE
+    SET end_time=$2, missed_runs=$3
+WHERE FALSE IN (
+    snapshot_gaps.end_time=$2, snapshot_gaps.missed_runs=$3
+)
+RETURNING (xmax = 0) AS inserted;
+
+-- name: DeleteSnapshotGapsExcept :execrows
+DELETE FROM snapshot_gaps
+WHERE start_time >= sqlc.arg(since) AND NOT (start_time = ANY(sqlc.arg(keep)::timestamp[]));
@@ -3,11 +3,21 @@ SELECT * FROM snapshots
 ORDER BY create_time desc
 LIMIT 1;
 
+-- name: GetLatestSnapshotTime :one
+SELECT create_time FROM snapshots
+ORDER BY create_time desc
+LIMIT 1;
+
+-- name: GetSnapshotTimes :many
+SELECT create_time FROM snapshots
+WHERE create_time >= $1
+ORDER BY create_time;
+
 -- name: InsertSnapshot :one
 INSERT INTO snapshots (
-    war_snapshot_id, assignment_snapshot_ids, campaign_ids, dispatch_ids, planet_snapshot_ids, statistics_id
+    create_time, war_snapshot_id, assignment_snapshot_ids, campaign_ids, dispatch_ids, planet_snapshot_ids, statistics_id, upstream
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
 RETURNING create_time;
 
@@ -21,25 +31,25 @@ RETURNING id;
 
 -- name: InsertAssignmentSnapshot :one
 INSERT INTO assignment_snapshots (
-    assignment_id, progress
+    assignment_id, progress, war_id
 ) VALUES (
-    $1, $2
+    $1, $2, $3
 )
 RETURNING id;
 
 -- name: InsertPlanetSnapshot :one
 INSERT INTO planet_snapshots (
-    planet_id, health, current_owner, event_snapshot_id, attacking_planet_ids, regen_per_second, statistics_id
+    planet_id, health, current_owner, event_snapshot_id, attacking_planet_ids, regen_per_second, statistics_id, war_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 )
 RETURNING id;
 
 -- name: InsertEventSnapshot :one
 INSERT INTO event_snapshots (
-    event_id, health
+    event_id, health, war_id
 ) VALUES (
-    $1, $2
+    $1, $2, $3
 )
 RETURNING id;
 
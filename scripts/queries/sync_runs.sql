@@ -0,0 +1,12 @@
+-- name: GetSyncRun :one
+SELECT * FROM sync_runs
+WHERE id = $1;
+
+-- name: MergeSyncRun :one
+INSERT INTO sync_runs (
+    id, job, start_time, end_time, outcome, endpoints, merge_counts, error, snapshot_create_time
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (id) DO NOTHING
+RETURNING (xmax = 0) AS inserted;
@@ -0,0 +1,57 @@
+-- name: GetWarSummary :one
+SELECT * FROM war_summaries
+WHERE war_id = $1;
+
+-- name: MergeWarSummary :one
+INSERT INTO war_summaries (
+    war_id, close_time, first_snapshot_time, final_snapshot_time, snapshot_count, final_statistics_id, peak_player_count
+)
+SELECT
+    war_snapshots.war_id,
+    sqlc.arg(close_time),
+    min(snapshots.create_time),
+    max(snapshots.create_time),
+    count(*),
+    (array_agg(snapshots.statistics_id ORDER BY snapshots.create_time DESC))[1],
+    max(snapshot_statistics.player_count)
+FROM snapshots
+JOIN war_snapshots ON war_snapshots.id = snapshots.war_snapshot_id
+JOIN snapshot_statistics ON snapshot_statistics.id = snapshots.statistics_id
+WHERE war_snapshots.war_id = sqlc.arg(war_id)
+GROUP BY war_snapshots.war_id
+ON CONFLICT (war_id) DO UPDATE
+    SET first_snapshot_time=EXCLUDED.first_snapshot_time, final_snapshot_time=EXCLUDED.final_snapshot_time, snapshot_count=EXCLUDED.snapshot_count, final_statistics_id=EXCLUDED.final_statistics_id, peak_player_count=EXCLUDED.peak_player_count
+WHERE FALSE IN (
+    war_summaries.final_snapshot_time=EXCLUDED.final_snapshot_time, war_summaries.snapshot_count=EXCLUDED.snapshot_count
+)
+RETURNING (xmax = 0) AS inserted;
+
+-- name: MergeWarFinalPlanets :many
+INSERT INTO war_final_planets (
+    war_id, planet_id, owner
+)
+SELECT planets.war_id, planets.id, COALESCE(latest.current_owner, planets.initial_owner)
+FROM planets
+LEFT JOIN (
+    SELECT DISTINCT ON (planet_snapshots.war_id, planet_snapshots.planet_id)
+        planet_snapshots.war_id, planet_snapshots.planet_id, planet_snapshots.current_owner
+    FROM planet_snapshots
+    WHERE planet_snapshots.war_id = $1
+    ORDER BY planet_snapshots.war_id, planet_snapshots.planet_id, planet_snapshots.id DESC
+) latest ON latest.war_id = planets.war_id AND latest.planet_id = planets.id
+WHERE planets.war_id = $1
+ON CONFLICT (war_id, planet_id) DO UPDATE
+    SET owner=EXCLUDED.owner
+WHERE war_final_planets.owner <> EXCLUDED.owner
+RETURNING (xmax = 0) AS inserted;
+
+-- name: GetWarFinalPlanets :many
+SELECT * FROM war_final_planets
+WHERE war_id = $1
+ORDER BY planet_id;
+
+-- name: GetLatestSnapshotWarID :one
+SELECT war_snapshots.war_id FROM snapshots
+JOIN war_snapshots ON war_snapshots.id = snapshots.war_snapshot_id
+ORDER BY snapshots.create_time DESC
+LIMIT 1;
@@ -2,10 +2,7 @@
 SELECT id FROM wars
 WHERE id = $1;
 
--- name: WarExists :one
-SELECT EXISTS(SELECT * FROM wars WHERE id = $1);
-
--- name: MergeWar :execrows
+-- name: MergeWar :one
 INSERT INTO wars (
     id, start_time, end_time, factions
 ) VALUES (
@@ -14,5 +11,6 @@ INSERT INTO wars (
 ON CONFLICT (id) DO UPDATE
     SET start_time=$2, end_time=$3, factions=$4
 WHERE FALSE IN (
-    EXCLUDED.start_time=$2, EXCLUDED.end_time=$3, EXCLUDED.factions=$4
-);
+    wars.start_time=$2, wars.end_time=$3, wars.factions=$4
+)
+RETURNING (xmax = 0) AS inserted;
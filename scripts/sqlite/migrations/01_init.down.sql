@@ -0,0 +1,24 @@
+DROP TABLE IF EXISTS campaign_lifecycles;
+DROP TABLE IF EXISTS war_final_planets;
+DROP TABLE IF EXISTS war_summaries;
+DROP TABLE IF EXISTS snapshot_gaps;
+DROP TABLE IF EXISTS snapshot_planets;
+DROP TABLE IF EXISTS snapshot_dispatches;
+DROP TABLE IF EXISTS snapshot_campaigns;
+DROP TABLE IF EXISTS snapshot_assignments;
+DROP TABLE IF EXISTS snapshots;
+DROP TABLE IF EXISTS assignment_snapshots;
+DROP TABLE IF EXISTS planet_snapshots;
+DROP TABLE IF EXISTS event_snapshots;
+DROP TABLE IF EXISTS snapshot_statistics;
+DROP TABLE IF EXISTS war_snapshots;
+DROP TABLE IF EXISTS dispatches;
+DROP TABLE IF EXISTS assignment_tasks;
+DROP TABLE IF EXISTS assignments;
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS campaigns;
+DROP TABLE IF EXISTS planet_hazards;
+DROP TABLE IF EXISTS planets;
+DROP TABLE IF EXISTS hazards;
+DROP TABLE IF EXISTS biomes;
+DROP TABLE IF EXISTS wars;
@@ -0,0 +1,269 @@
+-- SQLite equivalent of the PostgreSQL schema in scripts/migrations.
+--
+-- SQLite has no array types, so arrays are modeled as follows:
+--   - arrays of values (e.g. planet positions) are stored as JSON arrays in TEXT columns
+--   - arrays of references (e.g. the campaigns of a snapshot) are stored in join tables
+-- Timestamps are stored in UTC.
+
+CREATE TABLE wars
+(
+    id INTEGER NOT NULL,
+    start_time DATETIME NOT NULL,
+    end_time DATETIME NOT NULL,
+    -- JSON array of faction names
+    factions TEXT NOT NULL CHECK (json_array_length(factions) > 0),
+    CHECK (end_time > start_time),
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE biomes
+(
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    PRIMARY KEY (name)
+);
+
+CREATE TABLE hazards
+(
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    PRIMARY KEY (name)
+);
+
+CREATE TABLE planets
+(
+    war_id INTEGER NOT NULL REFERENCES wars,
+    id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    sector TEXT NOT NULL,
+    -- JSON array of the X and Y coordinates
+    position TEXT NOT NULL CHECK (json_array_length(position) = 2),
+    -- JSON array of planet IDs
+    waypoint_ids TEXT NOT NULL,
+    disabled BOOLEAN NOT NULL,
+    biome_name TEXT NOT NULL REFERENCES biomes,
+    max_health INTEGER NOT NULL CHECK (max_health > 0),
+    initial_owner TEXT NOT NULL,
+    PRIMARY KEY (war_id, id)
+);
+
+CREATE TABLE planet_hazards
+(
+    war_id INTEGER NOT NULL,
+    planet_id INTEGER NOT NULL,
+    hazard_name TEXT NOT NULL REFERENCES hazards,
+    PRIMARY KEY (war_id, planet_id, hazard_name),
+    FOREIGN KEY (war_id, planet_id) REFERENCES planets ON DELETE CASCADE
+);
+
+CREATE TABLE campaigns
+(
+    war_id INTEGER NOT NULL REFERENCES wars,
+    id INTEGER NOT NULL,
+    type INTEGER NOT NULL,
+    count INTEGER NOT NULL,
+    planet_id INTEGER,
+    PRIMARY KEY (war_id, id),
+    FOREIGN KEY (war_id, planet_id) REFERENCES planets
+);
+
+CREATE TABLE events
+(
+    war_id INTEGER NOT NULL,
+    id INTEGER NOT NULL,
+    campaign_id INTEGER NOT NULL,
+    type INTEGER NOT NULL,
+    faction TEXT NOT NULL,
+    max_health INTEGER NOT NULL CHECK (max_health > 0),
+    start_time DATETIME NOT NULL,
+    end_time DATETIME NOT NULL,
+    CHECK (end_time > start_time),
+    PRIMARY KEY (war_id, id),
+    FOREIGN KEY (war_id, campaign_id) REFERENCES campaigns
+);
+
+CREATE TABLE assignments
+(
+    war_id INTEGER NOT NULL REFERENCES wars,
+    id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    briefing TEXT NOT NULL,
+    description TEXT NOT NULL,
+    expiration DATETIME NOT NULL,
+    reward_type INTEGER NOT NULL,
+    reward_amount INTEGER NOT NULL,
+    PRIMARY KEY (war_id, id)
+);
+
+CREATE TABLE assignment_tasks
+(
+    war_id INTEGER NOT NULL,
+    assignment_id INTEGER NOT NULL,
+    -- position of the task within the assignment
+    position INTEGER NOT NULL,
+    task_type INTEGER NOT NULL,
+    -- JSON arrays of numbers
+    task_values TEXT NOT NULL,
+    value_types TEXT NOT NULL,
+    CHECK (json_array_length(task_values) = json_array_length(value_types)),
+    PRIMARY KEY (war_id, assignment_id, position),
+    FOREIGN KEY (war_id, assignment_id) REFERENCES assignments ON DELETE CASCADE
+);
+
+CREATE TABLE dispatches
+(
+    id INTEGER NOT NULL,
+    create_time DATETIME NOT NULL,
+    type INTEGER NOT NULL,
+    message TEXT NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE war_snapshots
+(
+    id INTEGER NOT NULL,
+    war_id INTEGER NOT NULL REFERENCES wars,
+    impact_multiplier REAL NOT NULL CHECK (impact_multiplier >= 0),
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE snapshot_statistics
+(
+    id INTEGER NOT NULL,
+    missions_won INTEGER NOT NULL,
+    missions_lost INTEGER NOT NULL,
+    mission_time INTEGER NOT NULL,
+    terminid_kills INTEGER NOT NULL,
+    automaton_kills INTEGER NOT NULL,
+    illuminate_kills INTEGER NOT NULL,
+    bullets_fired INTEGER NOT NULL,
+    bullets_hit INTEGER NOT NULL,
+    time_played INTEGER NOT NULL,
+    deaths INTEGER NOT NULL,
+    revives INTEGER NOT NULL,
+    friendlies INTEGER NOT NULL,
+    player_count INTEGER NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE event_snapshots
+(
+    id INTEGER NOT NULL,
+    war_id INTEGER NOT NULL,
+    event_id INTEGER NOT NULL,
+    health INTEGER NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (war_id, event_id) REFERENCES events
+);
+
+CREATE TABLE planet_snapshots
+(
+    id INTEGER NOT NULL,
+    war_id INTEGER NOT NULL,
+    planet_id INTEGER NOT NULL,
+    health INTEGER NOT NULL,
+    current_owner TEXT NOT NULL,
+    event_snapshot_id INTEGER REFERENCES event_snapshots,
+    -- JSON array of planet IDs
+    attacking_planet_ids TEXT NOT NULL,
+    regen_per_second REAL NOT NULL,
+    statistics_id INTEGER NOT NULL REFERENCES snapshot_statistics,
+    PRIMARY KEY (id),
+    FOREIGN KEY (war_id, planet_id) REFERENCES planets
+);
+
+CREATE TABLE assignment_snapshots
+(
+    id INTEGER NOT NULL,
+    war_id INTEGER NOT NULL,
+    assignment_id INTEGER NOT NULL,
+    -- JSON array of numbers
+    progress TEXT NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (war_id, assignment_id) REFERENCES assignments
+);
+
+CREATE TABLE snapshots
+(
+    create_time DATETIME NOT NULL,
+    war_snapshot_id INTEGER NOT NULL REFERENCES war_snapshots,
+    statistics_id INTEGER NOT NULL REFERENCES snapshot_statistics,
+    upstream TEXT,
+    PRIMARY KEY (create_time)
+);
+
+CREATE TABLE snapshot_assignments
+(
+    create_time DATETIME NOT NULL REFERENCES snapshots ON DELETE CASCADE,
+    assignment_snapshot_id INTEGER NOT NULL REFERENCES assignment_snapshots,
+    PRIMARY KEY (create_time, assignment_snapshot_id)
+);
+
+CREATE TABLE snapshot_campaigns
+(
+    create_time DATETIME NOT NULL REFERENCES snapshots ON DELETE CASCADE,
+    war_id INTEGER NOT NULL,
+    campaign_id INTEGER NOT NULL,
+    PRIMARY KEY (create_time, war_id, campaign_id),
+    FOREIGN KEY (war_id, campaign_id) REFERENCES campaigns
+);
+
+CREATE TABLE snapshot_dispatches
+(
+    create_time DATETIME NOT NULL REFERENCES snapshots ON DELETE CASCADE,
+    dispatch_id INTEGER NOT NULL REFERENCES dispatches,
+    PRIMARY KEY (create_time, dispatch_id)
+);
+
+CREATE TABLE snapshot_planets
+(
+    create_time DATETIME NOT NULL REFERENCES snapshots ON DELETE CASCADE,
+    planet_snapshot_id INTEGER NOT NULL REFERENCES planet_snapshots,
+    PRIMARY KEY (create_time, planet_snapshot_id)
+);
+
+CREATE TABLE snapshot_gaps
+(
+    start_time DATETIME NOT NULL REFERENCES snapshots,
+    end_time DATETIME NOT NULL REFERENCES snapshots,
+    missed_runs INTEGER NOT NULL CHECK (missed_runs > 0),
+    CHECK (end_time > start_time),
+    PRIMARY KEY (start_time)
+);
+
+CREATE TABLE war_summaries
+(
+    war_id INTEGER NOT NULL REFERENCES wars,
+    close_time DATETIME NOT NULL,
+    first_snapshot_time DATETIME NOT NULL REFERENCES snapshots,
+    final_snapshot_time DATETIME NOT NULL REFERENCES snapshots,
+    snapshot_count INTEGER NOT NULL,
+    final_statistics_id INTEGER NOT NULL REFERENCES snapshot_statistics,
+    peak_player_count INTEGER NOT NULL,
+    PRIMARY KEY (war_id)
+);
+
+CREATE TABLE war_final_planets
+(
+    war_id INTEGER NOT NULL,
+    planet_id INTEGER NOT NULL,
+    owner TEXT NOT NULL,
+    PRIMARY KEY (war_id, planet_id),
+    FOREIGN KEY (war_id, planet_id) REFERENCES planets
+);
+
+CREATE TABLE campaign_lifecycles
+(
+    war_id INTEGER NOT NULL,
+    campaign_id INTEGER NOT NULL,
+    planet_id INTEGER,
+    first_seen DATETIME NOT NULL,
+    last_seen DATETIME NOT NULL,
+    ended BOOLEAN NOT NULL,
+    outcome TEXT CHECK (outcome IN ('liberated', 'lost')),
+    CHECK (last_seen >= first_seen),
+    CHECK (ended OR outcome IS NULL),
+    PRIMARY KEY (war_id, campaign_id),
+    FOREIGN KEY (war_id, campaign_id) REFERENCES campaigns,
+    FOREIGN KEY (war_id, planet_id) REFERENCES planets
+);
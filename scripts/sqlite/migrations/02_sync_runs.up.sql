@@ -0,0 +1,17 @@
+CREATE TABLE sync_runs
+(
+    id TEXT NOT NULL,
+    job TEXT NOT NULL,
+    start_time DATETIME NOT NULL,
+    end_time DATETIME NOT NULL,
+    outcome TEXT NOT NULL CHECK (outcome IN ('success', 'failure')),
+    endpoints TEXT NOT NULL,
+    merge_counts TEXT NOT NULL,
+    error TEXT,
+    snapshot_create_time DATETIME,
+    CHECK (end_time >= start_time),
+    CHECK ((outcome = 'failure') = (error IS NOT NULL)),
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX sync_runs_start_time_idx ON sync_runs (start_time);
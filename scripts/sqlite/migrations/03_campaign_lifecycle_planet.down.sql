@@ -0,0 +1,26 @@
+CREATE TABLE campaign_lifecycles_old
+(
+    war_id INTEGER NOT NULL,
+    campaign_id INTEGER NOT NULL,
+    planet_id INTEGER,
+    first_seen DATETIME NOT NULL,
+    last_seen DATETIME NOT NULL,
+    ended BOOLEAN NOT NULL,
+    outcome TEXT CHECK (outcome IN ('liberated', 'lost')),
+    CHECK (last_seen >= first_seen),
+    CHECK (ended OR outcome IS NULL),
+    PRIMARY KEY (war_id, campaign_id),
+    FOREIGN KEY (war_id, campaign_id) REFERENCES campaigns,
+    FOREIGN KEY (war_id, planet_id) REFERENCES planets
+);
+
+INSERT INTO campaign_lifecycles_old (war_id, campaign_id, planet_id, first_seen, last_seen, ended, outcome)
+SELECT campaign_lifecycles.war_id, campaign_lifecycles.campaign_id, campaigns.planet_id,
+    campaign_lifecycles.first_seen, campaign_lifecycles.last_seen, campaign_lifecycles.ended, campaign_lifecycles.outcome
+FROM campaign_lifecycles
+JOIN campaigns
+    ON campaigns.war_id = campaign_lifecycles.war_id AND campaigns.id = campaign_lifecycles.campaign_id;
+
+DROP TABLE campaign_lifecycles;
+
+ALTER TABLE campaign_lifecycles_old RENAME TO campaign_lifecycles;
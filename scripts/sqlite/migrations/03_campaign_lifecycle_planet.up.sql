@@ -0,0 +1,32 @@
+-- the planet of a campaign is stored with the campaign, lifecycles join through it
+
+-- keep planets only known from lifecycles
+UPDATE campaigns
+SET planet_id = (
+    SELECT campaign_lifecycles.planet_id
+    FROM campaign_lifecycles
+    WHERE campaign_lifecycles.war_id = campaigns.war_id AND campaign_lifecycles.campaign_id = campaigns.id
+)
+WHERE campaigns.planet_id IS NULL;
+
+-- SQLite cannot drop columns which are part of a foreign key, so the table is rebuilt
+CREATE TABLE campaign_lifecycles_new
+(
+    war_id INTEGER NOT NULL,
+    campaign_id INTEGER NOT NULL,
+    first_seen DATETIME NOT NULL,
+    last_seen DATETIME NOT NULL,
+    ended BOOLEAN NOT NULL,
+    outcome TEXT CHECK (outcome IN ('liberated', 'lost')),
+    CHECK (last_seen >= first_seen),
+    CHECK (ended OR outcome IS NULL),
+    PRIMARY KEY (war_id, campaign_id),
+    FOREIGN KEY (war_id, campaign_id) REFERENCES campaigns
+);
+
+INSERT INTO campaign_lifecycles_new (war_id, campaign_id, first_seen, last_seen, ended, outcome)
+SELECT war_id, campaign_id, first_seen, last_seen, ended, outcome FROM campaign_lifecycles;
+
+DROP TABLE campaign_lifecycles;
+
+ALTER TABLE campaign_lifecycles_new RENAME TO campaign_lifecycles;
@@ -0,0 +1,28 @@
+DROP TRIGGER biomes_check_insert;
+DROP TRIGGER biomes_check_update;
+DROP TRIGGER hazards_check_insert;
+DROP TRIGGER hazards_check_update;
+DROP TRIGGER planets_check_insert;
+DROP TRIGGER planets_check_update;
+DROP TRIGGER campaigns_check_insert;
+DROP TRIGGER campaigns_check_update;
+DROP TRIGGER events_check_insert;
+DROP TRIGGER events_check_update;
+DROP TRIGGER assignments_check_insert;
+DROP TRIGGER assignments_check_update;
+DROP TRIGGER assignment_tasks_check_insert;
+DROP TRIGGER assignment_tasks_check_update;
+DROP TRIGGER dispatches_check_insert;
+DROP TRIGGER dispatches_check_update;
+DROP TRIGGER event_snapshots_check_insert;
+DROP TRIGGER event_snapshots_check_update;
+DROP TRIGGER planet_snapshots_check_insert;
+DROP TRIGGER planet_snapshots_check_update;
+DROP TRIGGER assignment_snapshots_check_insert;
+DROP TRIGGER assignment_snapshots_check_update;
+DROP TRIGGER snapshot_statistics_check_insert;
+DROP TRIGGER snapshot_statistics_check_update;
+DROP TRIGGER war_summaries_check_insert;
+DROP TRIGGER war_summaries_check_update;
+DROP TRIGGER war_final_planets_check_insert;
+DROP TRIGGER war_final_planets_check_update;
@@ -0,0 +1,179 @@
+-- CHECK constraints of the PostgreSQL schema missing from the initial schema.
+--
+-- SQLite cannot add constraints to existing tables without rebuilding them, which is not possible for tables referenced by
+-- foreign keys, so they are enforced by triggers instead.
+-- Required arrays are stored as JSON null if missing, which is rejected like NULL in PostgreSQL.
+
+CREATE TRIGGER biomes_check_insert BEFORE INSERT ON biomes
+WHEN NEW.name = '' OR NEW.description = ''
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: biomes');
+END;
+
+CREATE TRIGGER biomes_check_update BEFORE UPDATE ON biomes
+WHEN NEW.name = '' OR NEW.description = ''
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: biomes');
+END;
+
+CREATE TRIGGER hazards_check_insert BEFORE INSERT ON hazards
+WHEN NEW.name = '' OR NEW.description = ''
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: hazards');
+END;
+
+CREATE TRIGGER hazards_check_update BEFORE UPDATE ON hazards
+WHEN NEW.name = '' OR NEW.description = ''
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: hazards');
+END;
+
+CREATE TRIGGER planets_check_insert BEFORE INSERT ON planets
+WHEN NEW.name = '' OR NEW.sector = '' OR NEW.initial_owner = '' OR json_type(NEW.waypoint_ids) IS NOT 'array'
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: planets');
+END;
+
+CREATE TRIGGER planets_check_update BEFORE UPDATE ON planets
+WHEN NEW.name = '' OR NEW.sector = '' OR NEW.initial_owner = '' OR json_type(NEW.waypoint_ids) IS NOT 'array'
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: planets');
+END;
+
+CREATE TRIGGER campaigns_check_insert BEFORE INSERT ON campaigns
+WHEN NEW.count < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: campaigns');
+END;
+
+CREATE TRIGGER campaigns_check_update BEFORE UPDATE ON campaigns
+WHEN NEW.count < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: campaigns');
+END;
+
+CREATE TRIGGER events_check_insert BEFORE INSERT ON events
+WHEN NEW.faction = ''
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: events');
+END;
+
+CREATE TRIGGER events_check_update BEFORE UPDATE ON events
+WHEN NEW.faction = ''
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: events');
+END;
+
+CREATE TRIGGER assignments_check_insert BEFORE INSERT ON assignments
+WHEN NEW.title = '' OR NEW.briefing = '' OR NEW.description = '' OR NEW.expiration <= '1900-01-01'
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: assignments');
+END;
+
+CREATE TRIGGER assignments_check_update BEFORE UPDATE ON assignments
+WHEN NEW.title = '' OR NEW.briefing = '' OR NEW.description = '' OR NEW.expiration <= '1900-01-01'
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: assignments');
+END;
+
+CREATE TRIGGER assignment_tasks_check_insert BEFORE INSERT ON assignment_tasks
+WHEN json_type(NEW.task_values) IS NOT 'array' OR json_type(NEW.value_types) IS NOT 'array'
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: assignment_tasks');
+END;
+
+CREATE TRIGGER assignment_tasks_check_update BEFORE UPDATE ON assignment_tasks
+WHEN json_type(NEW.task_values) IS NOT 'array' OR json_type(NEW.value_types) IS NOT 'array'
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: assignment_tasks');
+END;
+
+CREATE TRIGGER dispatches_check_insert BEFORE INSERT ON dispatches
+WHEN NEW.message = ''
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: dispatches');
+END;
+
+CREATE TRIGGER dispatches_check_update BEFORE UPDATE ON dispatches
+WHEN NEW.message = ''
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: dispatches');
+END;
+
+CREATE TRIGGER event_snapshots_check_insert BEFORE INSERT ON event_snapshots
+WHEN NEW.health < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: event_snapshots');
+END;
+
+CREATE TRIGGER event_snapshots_check_update BEFORE UPDATE ON event_snapshots
+WHEN NEW.health < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: event_snapshots');
+END;
+
+CREATE TRIGGER planet_snapshots_check_insert BEFORE INSERT ON planet_snapshots
+WHEN NEW.health < 0 OR NEW.current_owner = '' OR NEW.regen_per_second < 0 OR json_type(NEW.attacking_planet_ids) IS NOT 'array'
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: planet_snapshots');
+END;
+
+CREATE TRIGGER planet_snapshots_check_update BEFORE UPDATE ON planet_snapshots
+WHEN NEW.health < 0 OR NEW.current_owner = '' OR NEW.regen_per_second < 0 OR json_type(NEW.attacking_planet_ids) IS NOT 'array'
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: planet_snapshots');
+END;
+
+CREATE TRIGGER assignment_snapshots_check_insert BEFORE INSERT ON assignment_snapshots
+WHEN json_type(NEW.progress) IS NOT 'array'
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: assignment_snapshots');
+END;
+
+CREATE TRIGGER assignment_snapshots_check_update BEFORE UPDATE ON assignment_snapshots
+WHEN json_type(NEW.progress) IS NOT 'array'
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: assignment_snapshots');
+END;
+
+CREATE TRIGGER snapshot_statistics_check_insert BEFORE INSERT ON snapshot_statistics
+WHEN MIN(
+    NEW.missions_won, NEW.missions_lost, NEW.mission_time, NEW.terminid_kills, NEW.automaton_kills, NEW.illuminate_kills,
+    NEW.bullets_fired, NEW.bullets_hit, NEW.time_played, NEW.deaths, NEW.revives, NEW.friendlies, NEW.player_count
+) < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: snapshot_statistics');
+END;
+
+CREATE TRIGGER snapshot_statistics_check_update BEFORE UPDATE ON snapshot_statistics
+WHEN MIN(
+    NEW.missions_won, NEW.missions_lost, NEW.mission_time, NEW.terminid_kills, NEW.automaton_kills, NEW.illuminate_kills,
+    NEW.bullets_fired, NEW.bullets_hit, NEW.time_played, NEW.deaths, NEW.revives, NEW.friendlies, NEW.player_count
+) < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: snapshot_statistics');
+END;
+
+CREATE TRIGGER war_summaries_check_insert BEFORE INSERT ON war_summaries
+WHEN NEW.snapshot_count <= 0 OR NEW.peak_player_count < 0 OR NEW.final_snapshot_time < NEW.first_snapshot_time
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: war_summaries');
+END;
+
+CREATE TRIGGER war_summaries_check_update BEFORE UPDATE ON war_summaries
+WHEN NEW.snapshot_count <= 0 OR NEW.peak_player_count < 0 OR NEW.final_snapshot_time < NEW.first_snapshot_time
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: war_summaries');
+END;
+
+CREATE TRIGGER war_final_planets_check_insert BEFORE INSERT ON war_final_planets
+WHEN NEW.owner = ''
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: war_final_planets');
+END;
+
+CREATE TRIGGER war_final_planets_check_update BEFORE UPDATE ON war_final_planets
+WHEN NEW.owner = ''
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: war_final_planets');
+END;
@@ -0,0 +1,29 @@
+-- name: AssignmentExists :one
+SELECT EXISTS(SELECT * FROM assignments WHERE war_id = ? AND id = ?);
+
+-- name: MergeAssignment :execrows
+INSERT INTO assignments (
+    war_id, id, title, briefing, description, expiration, reward_type, reward_amount
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?
+)
+ON CONFLICT (war_id, id) DO UPDATE
+    SET title=excluded.title, briefing=excluded.briefing, description=excluded.description, expiration=excluded.expiration, reward_type=excluded.reward_type, reward_amount=excluded.reward_amount
+WHERE assignments.title IS NOT excluded.title OR assignments.briefing IS NOT excluded.briefing OR assignments.description IS NOT excluded.description
+    OR assignments.expiration IS NOT excluded.expiration OR assignments.reward_type IS NOT excluded.reward_type OR assignments.reward_amount IS NOT excluded.reward_amount;
+
+-- name: GetAssignmentTasks :many
+SELECT * FROM assignment_tasks
+WHERE war_id = ? AND assignment_id = ?
+ORDER BY position;
+
+-- name: DeleteAssignmentTasks :exec
+DELETE FROM assignment_tasks
+WHERE war_id = ? AND assignment_id = ?;
+
+-- name: InsertAssignmentTask :exec
+INSERT INTO assignment_tasks (
+    war_id, assignment_id, position, task_type, task_values, value_types
+) VALUES (
+    ?, ?, ?, ?, ?, ?
+);
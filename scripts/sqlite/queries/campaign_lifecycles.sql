@@ -0,0 +1,32 @@
+-- name: GetCampaignLifecycles :many
+SELECT campaign_lifecycles.*, campaigns.planet_id FROM campaign_lifecycles
+JOIN campaigns
+    ON campaigns.war_id = campaign_lifecycles.war_id AND campaigns.id = campaign_lifecycles.campaign_id
+WHERE campaign_lifecycles.war_id = ?
+ORDER BY first_seen, campaign_id;
+
+-- name: CampaignLifecycleExists :one
+SELECT EXISTS(SELECT * FROM campaign_lifecycles WHERE war_id = ? AND campaign_id = ?);
+
+-- name: MergeCampaignLifecycle :execrows
+INSERT INTO campaign_lifecycles (
+    war_id, campaign_id, first_seen, last_seen, ended, outcome
+) VALUES (
+    ?, ?, ?, ?, FALSE, NULL
+)
+ON CONFLICT (war_id, campaign_id) DO UPDATE
+    SET last_seen=excluded.last_seen, ended=FALSE, outcome=NULL
+WHERE campaign_lifecycles.last_seen < excluded.last_seen;
+
+-- name: EndCampaignLifecycles :execrows
+UPDATE campaign_lifecycles
+SET ended = TRUE
+WHERE war_id = sqlc.arg(war_id) AND NOT ended AND campaign_id NOT IN (SELECT value FROM json_each(sqlc.arg(active_ids)));
+
+-- name: ResolveCampaignOutcomes :execrows
+UPDATE campaign_lifecycles
+SET outcome = CASE WHEN captured.owner = 'Humans' THEN 'liberated' ELSE 'lost' END
+FROM campaigns, (SELECT CAST(key AS INTEGER) AS planet_id, value AS owner FROM json_each(sqlc.arg(owners))) AS captured
+WHERE campaign_lifecycles.war_id = sqlc.arg(war_id) AND campaign_lifecycles.ended AND campaign_lifecycles.outcome IS NULL
+    AND campaigns.war_id = campaign_lifecycles.war_id AND campaigns.id = campaign_lifecycles.campaign_id
+    AND campaigns.planet_id = captured.planet_id;
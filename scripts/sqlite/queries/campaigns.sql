@@ -0,0 +1,13 @@
+-- name: CampaignExists :one
+SELECT EXISTS(SELECT * FROM campaigns WHERE war_id = ? AND id = ?);
+
+-- name: MergeCampaign :execrows
+INSERT INTO campaigns (
+    war_id, id, type, count, planet_id
+) VALUES (
+    ?, ?, ?, ?, ?
+)
+ON CONFLICT (war_id, id) DO UPDATE
+    SET type=excluded.type, count=excluded.count, planet_id=COALESCE(excluded.planet_id, campaigns.planet_id)
+WHERE campaigns.type IS NOT excluded.type OR campaigns.count IS NOT excluded.count
+    OR campaigns.planet_id IS NOT COALESCE(excluded.planet_id, campaigns.planet_id);
@@ -0,0 +1,12 @@
+-- name: DispatchExists :one
+SELECT EXISTS(SELECT * FROM dispatches WHERE id = ?);
+
+-- name: MergeDispatch :execrows
+INSERT INTO dispatches (
+    id, create_time, type, message
+) VALUES (
+    ?, ?, ?, ?
+)
+ON CONFLICT (id) DO UPDATE
+    SET create_time=excluded.create_time, type=excluded.type, message=excluded.message
+WHERE dispatches.create_time IS NOT excluded.create_time OR dispatches.type IS NOT excluded.type OR dispatches.message IS NOT excluded.message;
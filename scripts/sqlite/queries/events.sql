@@ -0,0 +1,13 @@
+-- name: EventExists :one
+SELECT EXISTS(SELECT * FROM events WHERE war_id = ? AND id = ?);
+
+-- name: MergeEvent :execrows
+INSERT INTO events (
+    war_id, id, campaign_id, type, faction, max_health, start_time, end_time
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?
+)
+ON CONFLICT (war_id, id) DO UPDATE
+    SET campaign_id=excluded.campaign_id, type=excluded.type, faction=excluded.faction, max_health=excluded.max_health, start_time=excluded.start_time, end_time=excluded.end_time
+WHERE events.campaign_id IS NOT excluded.campaign_id OR events.type IS NOT excluded.type OR events.faction IS NOT excluded.faction
+    OR events.max_health IS NOT excluded.max_health OR events.start_time IS NOT excluded.start_time OR events.end_time IS NOT excluded.end_time;
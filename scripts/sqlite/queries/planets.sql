@@ -0,0 +1,51 @@
+-- name: BiomeExists :one
+SELECT EXISTS(SELECT * FROM biomes WHERE name = ?);
+
+-- name: MergeBiome :execrows
+INSERT INTO biomes (
+    name, description
+) VALUES (
+    ?, ?
+)
+ON CONFLICT (name) DO UPDATE
+    SET description=excluded.description
+WHERE biomes.description IS NOT excluded.description;
+
+-- name: HazardExists :one
+SELECT EXISTS(SELECT * FROM hazards WHERE name = ?);
+
+-- name: MergeHazard :execrows
+INSERT INTO hazards (
+    name, description
+) VALUES (
+    ?, ?
+)
+ON CONFLICT (name) DO UPDATE
+    SET description=excluded.description
+WHERE hazards.description IS NOT excluded.description;
+
+-- name: PlanetExists :one
+SELECT EXISTS(SELECT * FROM planets WHERE war_id = ? AND id = ?);
+
+-- name: MergePlanet :execrows
+INSERT INTO planets (
+    war_id, id, name, sector, position, waypoint_ids, disabled, biome_name, max_health, initial_owner
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+ON CONFLICT (war_id, id) DO UPDATE
+    SET name=excluded.name, sector=excluded.sector, position=excluded.position, waypoint_ids=excluded.waypoint_ids, disabled=excluded.disabled, biome_name=excluded.biome_name, max_health=excluded.max_health, initial_owner=excluded.initial_owner
+WHERE planets.name IS NOT excluded.name OR planets.sector IS NOT excluded.sector OR planets.position IS NOT excluded.position
+    OR planets.waypoint_ids IS NOT excluded.waypoint_ids OR planets.disabled IS NOT excluded.disabled OR planets.biome_name IS NOT excluded.biome_name
+    OR planets.max_health IS NOT excluded.max_health OR planets.initial_owner IS NOT excluded.initial_owner;
+
+-- name: DeletePlanetHazards :exec
+DELETE FROM planet_hazards
+WHERE war_id = ? AND planet_id = ?;
+
+-- name: InsertPlanetHazard :exec
+INSERT INTO planet_hazards (
+    war_id, planet_id, hazard_name
+) VALUES (
+    ?, ?, ?
+);
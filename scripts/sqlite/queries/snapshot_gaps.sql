@@ -0,0 +1,21 @@
+-- name: GetSnapshotGaps :many
+SELECT * FROM snapshot_gaps
+WHERE start_time >= ?
+ORDER BY start_time;
+
+-- name: SnapshotGapExists :one
+SELECT EXISTS(SELECT * FROM snapshot_gaps WHERE start_time = ?);
+
+-- name: MergeSnapshotGap :execrows
+INSERT INTO snapshot_gaps (
+    start_time, end_time, missed_runs
+) VALUES (
+    ?, ?, ?
+)
+ON CONFLICT (start_time) DO UPDATE
+    SET end_time=excluded.end_time, missed_runs=excluded.missed_runs
+WHERE snapshot_gaps.end_time IS NOT excluded.end_time OR snapshot_gaps.missed_runs IS NOT excluded.missed_runs;
+
+-- name: DeleteSnapshotGapsExcept :execrows
+DELETE FROM snapshot_gaps
+WHERE start_time >= sqlc.arg(since) AND start_time NOT IN (SELECT value FROM json_each(sqlc.arg(keep)));
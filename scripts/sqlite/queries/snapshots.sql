@@ -0,0 +1,80 @@
+-- name: GetLatestSnapshot :one
+SELECT snapshots.create_time, war_snapshots.war_id FROM snapshots
+JOIN war_snapshots ON war_snapshots.id = snapshots.war_snapshot_id
+ORDER BY snapshots.create_time DESC
+LIMIT 1;
+
+-- name: InsertWarSnapshot :one
+INSERT INTO war_snapshots (
+    war_id, impact_multiplier
+) VALUES (
+    ?, ?
+)
+RETURNING id;
+
+-- name: InsertSnapshotStatistics :one
+INSERT INTO snapshot_statistics (
+    missions_won, missions_lost, mission_time, terminid_kills, automaton_kills, illuminate_kills, bullets_fired, bullets_hit, time_played, deaths, revives, friendlies, player_count
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING id;
+
+-- name: InsertEventSnapshot :one
+INSERT INTO event_snapshots (
+    war_id, event_id, health
+) VALUES (
+    ?, ?, ?
+)
+RETURNING id;
+
+-- name: InsertPlanetSnapshot :one
+INSERT INTO planet_snapshots (
+    war_id, planet_id, health, current_owner, event_snapshot_id, attacking_planet_ids, regen_per_second, statistics_id
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING id;
+
+-- name: InsertAssignmentSnapshot :one
+INSERT INTO assignment_snapshots (
+    war_id, assignment_id, progress
+) VALUES (
+    ?, ?, ?
+)
+RETURNING id;
+
+-- name: InsertSnapshot :exec
+INSERT INTO snapshots (
+    create_time, war_snapshot_id, statistics_id, upstream
+) VALUES (
+    ?, ?, ?, ?
+);
+
+-- name: InsertSnapshotAssignment :exec
+INSERT INTO snapshot_assignments (
+    create_time, assignment_snapshot_id
+) VALUES (
+    ?, ?
+);
+
+-- name: InsertSnapshotCampaign :exec
+INSERT INTO snapshot_campaigns (
+    create_time, war_id, campaign_id
+) VALUES (
+    ?, ?, ?
+);
+
+-- name: InsertSnapshotDispatch :exec
+INSERT INTO snapshot_dispatches (
+    create_time, dispatch_id
+) VALUES (
+    ?, ?
+);
+
+-- name: InsertSnapshotPlanet :exec
+INSERT INTO snapshot_planets (
+    create_time, planet_snapshot_id
+) VALUES (
+    ?, ?
+);
@@ -0,0 +1,14 @@
+-- name: GetSyncRun :one
+SELECT * FROM sync_runs
+WHERE id = ?;
+
+-- name: SyncRunExists :one
+SELECT EXISTS(SELECT * FROM sync_runs WHERE id = ?);
+
+-- name: MergeSyncRun :execrows
+INSERT INTO sync_runs (
+    id, job, start_time, end_time, outcome, endpoints, merge_counts, error, snapshot_create_time
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,72 @@
+-- name: GetWarSummary :one
+SELECT * FROM war_summaries
+WHERE war_id = ?;
+
+-- name: WarSummaryExists :one
+SELECT EXISTS(SELECT * FROM war_summaries WHERE war_id = ?);
+
+-- name: MergeWarSummary :execrows
+INSERT INTO war_summaries (
+    war_id, close_time, first_snapshot_time, final_snapshot_time, snapshot_count, final_statistics_id, peak_player_count
+)
+SELECT
+    war_snapshots.war_id,
+    sqlc.arg(close_time),
+    min(snapshots.create_time),
+    max(snapshots.create_time),
+    count(*),
+    (
+        SELECT final.statistics_id FROM snapshots AS final
+        JOIN war_snapshots AS final_war ON final_war.id = final.war_snapshot_id
+        WHERE final_war.war_id = war_snapshots.war_id
+        ORDER BY final.create_time DESC
+        LIMIT 1
+    ),
+    max(snapshot_statistics.player_count)
+FROM snapshots
+JOIN war_snapshots ON war_snapshots.id = snapshots.war_snapshot_id
+JOIN snapshot_statistics ON snapshot_statistics.id = snapshots.statistics_id
+WHERE war_snapshots.war_id = sqlc.arg(war_id)
+GROUP BY war_snapshots.war_id
+ON CONFLICT (war_id) DO UPDATE
+    SET first_snapshot_time=excluded.first_snapshot_time, final_snapshot_time=excluded.final_snapshot_time, snapshot_count=excluded.snapshot_count, final_statistics_id=excluded.final_statistics_id, peak_player_count=excluded.peak_player_count
+WHERE war_summaries.final_snapshot_time IS NOT excluded.final_snapshot_time OR war_summaries.snapshot_count IS NOT excluded.snapshot_count;
+
+-- name: UpdateWarFinalPlanets :execrows
+UPDATE war_final_planets
+SET owner = final.owner
+FROM (
+    SELECT planets.war_id, planets.id AS planet_id, COALESCE((
+        SELECT planet_snapshots.current_owner
+        FROM planet_snapshots
+        JOIN snapshot_planets ON snapshot_planets.planet_snapshot_id = planet_snapshots.id
+        WHERE planet_snapshots.war_id = planets.war_id AND planet_snapshots.planet_id = planets.id
+        ORDER BY snapshot_planets.create_time DESC
+        LIMIT 1
+    ), planets.initial_owner) AS owner
+    FROM planets
+    WHERE planets.war_id = ?
+) AS final
+WHERE war_final_planets.war_id = final.war_id AND war_final_planets.planet_id = final.planet_id
+    AND war_final_planets.owner <> final.owner;
+
+-- name: InsertWarFinalPlanets :execrows
+INSERT INTO war_final_planets (
+    war_id, planet_id, owner
+)
+SELECT planets.war_id, planets.id AS planet_id, COALESCE((
+    SELECT planet_snapshots.current_owner
+    FROM planet_snapshots
+    JOIN snapshot_planets ON snapshot_planets.planet_snapshot_id = planet_snapshots.id
+    WHERE planet_snapshots.war_id = planets.war_id AND planet_snapshots.planet_id = planets.id
+    ORDER BY snapshot_planets.create_time DESC
+    LIMIT 1
+), planets.initial_owner) AS owner
+FROM planets
+WHERE planets.war_id = ?
+ON CONFLICT (war_id, planet_id) DO NOTHING;
+
+-- name: GetWarFinalPlanets :many
+SELECT * FROM war_final_planets
+WHERE war_id = ?
+ORDER BY planet_id;
@@ -0,0 +1,12 @@
+-- name: WarExists :one
+SELECT EXISTS(SELECT * FROM wars WHERE id = ?);
+
+-- name: MergeWar :execrows
+INSERT INTO wars (
+    id, start_time, end_time, factions
+) VALUES (
+    ?, ?, ?, ?
+)
+ON CONFLICT (id) DO UPDATE
+    SET start_time=excluded.start_time, end_time=excluded.end_time, factions=excluded.factions
+WHERE wars.start_time IS NOT excluded.start_time OR wars.end_time IS NOT excluded.end_time OR wars.factions IS NOT excluded.factions;
@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 )
 
@@ -16,8 +16,8 @@ type ConnectWaiter interface {
 	Connect(ctx context.Context) error
 }
 
-func waitFor(waiter ConnectWaiter, timeout time.Duration, logger *log.Logger) error {
-	logger.Printf("waiting %s until %T is ready", timeout, waiter)
+func waitFor(waiter ConnectWaiter, timeout time.Duration, logger *slog.Logger) error {
+	logger.Info("waiting until ready", "service", fmt.Sprintf("%T", waiter), "timeout", timeout)
 	ctx, cancel := context.WithTimeoutCause(
 		context.Background(),
 		timeout,